@@ -0,0 +1,239 @@
+// Package audit implements the local JSONL log of write operations.
+package audit
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+)
+
+const (
+	OutcomeOK    = "ok"
+	OutcomeError = "error"
+)
+
+var errEmptyPath = errors.New("empty audit log path")
+
+// Entry is one mutating API call. Entries from the same gog invocation share Run.
+type Entry struct {
+	ID         string    `json:"id"`
+	Time       time.Time `json:"time"`
+	Run        string    `json:"run"`
+	Account    string    `json:"account,omitempty"`
+	Client     string    `json:"client,omitempty"`
+	Command    string    `json:"command,omitempty"`
+	Service    string    `json:"service,omitempty"`
+	Method     string    `json:"method"`
+	Resource   string    `json:"resource"`
+	ResourceID string    `json:"resourceId,omitempty"`
+	Request    string    `json:"request,omitempty"`
+	Status     int       `json:"status,omitempty"`
+	Outcome    string    `json:"outcome"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// DefaultPath returns the audit log location used when audit_log_path is unset.
+func DefaultPath() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", fmt.Errorf("config dir: %w", err)
+	}
+
+	return filepath.Join(dir, "audit.jsonl"), nil
+}
+
+// PathFor returns the configured audit log path (or the default).
+func PathFor(cfg config.File) (string, error) {
+	if p := strings.TrimSpace(cfg.AuditLogPath); p != "" {
+		return expandHome(p)
+	}
+
+	return DefaultPath()
+}
+
+func expandHome(p string) (string, error) {
+	if p != "~" && !strings.HasPrefix(p, "~/") {
+		return p, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("home dir: %w", err)
+	}
+
+	return filepath.Join(home, strings.TrimPrefix(p, "~")), nil
+}
+
+// Log appends entries to a JSONL file.
+type Log struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+func (l *Log) Append(e Entry) error {
+	if strings.TrimSpace(l.Path) == "" {
+		return errEmptyPath
+	}
+	if e.ID == "" {
+		e.ID = NewID()
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encode audit entry: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.Path), 0o700); err != nil {
+		return fmt.Errorf("ensure audit dir: %w", err)
+	}
+	f, err := os.OpenFile(l.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600) //nolint:gosec // configured audit path
+	if err != nil {
+		return fmt.Errorf("open audit log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+
+	return nil
+}
+
+// Filter selects entries for Read. Zero values match everything.
+type Filter struct {
+	Since   time.Time
+	Until   time.Time
+	Account string
+	Service string
+	Limit   int
+}
+
+func (f Filter) match(e Entry) bool {
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !e.Time.Before(f.Until) {
+		return false
+	}
+	if f.Account != "" && !strings.EqualFold(f.Account, e.Account) {
+		return false
+	}
+	if f.Service != "" && !strings.EqualFold(f.Service, e.Service) {
+		return false
+	}
+
+	return true
+}
+
+// Read returns matching entries, newest first. A missing log reads as empty.
+func Read(path string, f Filter) ([]Entry, error) {
+	file, err := os.Open(path) //nolint:gosec // configured audit path
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("open audit log: %w", err)
+	}
+	defer file.Close()
+
+	var out []Entry
+	sc := bufio.NewScanner(file)
+	sc.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			continue
+		}
+		if f.match(e) {
+			out = append(out, e)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read audit log: %w", err)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Time.After(out[j].Time) })
+	if f.Limit > 0 && len(out) > f.Limit {
+		out = out[:f.Limit]
+	}
+
+	return out, nil
+}
+
+// Find returns the entry with the given ID (or unique ID prefix).
+func Find(path string, id string) (Entry, bool, error) {
+	id = strings.TrimSpace(id)
+	entries, err := Read(path, Filter{})
+	if err != nil || id == "" {
+		return Entry{}, false, err
+	}
+
+	var found []Entry
+	for _, e := range entries {
+		if e.ID == id {
+			return e, true, nil
+		}
+		if strings.HasPrefix(e.ID, id) {
+			found = append(found, e)
+		}
+	}
+	if len(found) == 1 {
+		return found[0], true, nil
+	}
+
+	return Entry{}, false, nil
+}
+
+func NewID() string {
+	var b [6]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+
+	return hex.EncodeToString(b[:])
+}
+
+// Session is the per-invocation context the transport stamps onto entries.
+type Session struct {
+	Log     *Log
+	Run     string
+	Command string
+}
+
+type sessionKey struct{}
+
+func WithSession(ctx context.Context, s *Session) context.Context {
+	return context.WithValue(ctx, sessionKey{}, s)
+}
+
+func SessionFromContext(ctx context.Context) *Session {
+	if ctx == nil {
+		return nil
+	}
+	s, _ := ctx.Value(sessionKey{}).(*Session)
+
+	return s
+}
@@ -0,0 +1,86 @@
+package audit
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+)
+
+func TestLog_AppendReadFilter(t *testing.T) {
+	log := &Log{Path: filepath.Join(t.TempDir(), "nested", "audit.jsonl")}
+	base := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	entries := []Entry{
+		{ID: "aaa111", Time: base, Account: "a@b.com", Service: "gmail", Method: "POST", Outcome: OutcomeOK},
+		{ID: "bbb222", Time: base.Add(time.Hour), Account: "c@d.com", Service: "drive", Method: "DELETE", Outcome: OutcomeOK},
+		{ID: "bbb333", Time: base.Add(2 * time.Hour), Account: "A@B.com", Service: "drive", Method: "PATCH", Outcome: OutcomeError},
+	}
+	for _, e := range entries {
+		if err := log.Append(e); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	all, err := Read(log.Path, Filter{})
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if len(all) != 3 || all[0].ID != "bbb333" || all[2].ID != "aaa111" {
+		t.Fatalf("expected newest first, got %+v", all)
+	}
+
+	got, _ := Read(log.Path, Filter{Account: "a@b.com", Service: "drive"})
+	if len(got) != 1 || got[0].ID != "bbb333" {
+		t.Fatalf("unexpected account/service filter result: %+v", got)
+	}
+
+	got, _ = Read(log.Path, Filter{Since: base.Add(30 * time.Minute), Until: base.Add(2 * time.Hour)})
+	if len(got) != 1 || got[0].ID != "bbb222" {
+		t.Fatalf("unexpected time filter result: %+v", got)
+	}
+
+	got, _ = Read(log.Path, Filter{Limit: 2})
+	if len(got) != 2 {
+		t.Fatalf("expected limit 2, got %d", len(got))
+	}
+
+	if e, ok, _ := Find(log.Path, "aaa"); !ok || e.ID != "aaa111" {
+		t.Fatalf("expected prefix match, got %+v %v", e, ok)
+	}
+	if _, ok, _ := Find(log.Path, "bbb"); ok {
+		t.Fatalf("ambiguous prefix must not match")
+	}
+}
+
+func TestRead_MissingFile(t *testing.T) {
+	got, err := Read(filepath.Join(t.TempDir(), "missing.jsonl"), Filter{})
+	if err != nil || got != nil {
+		t.Fatalf("expected empty result, got %v %v", got, err)
+	}
+}
+
+func TestPathFor(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	def, err := PathFor(config.File{})
+	if err != nil || filepath.Base(def) != "audit.jsonl" {
+		t.Fatalf("unexpected default path %q %v", def, err)
+	}
+	custom, err := PathFor(config.File{AuditLogPath: "/var/log/gog.jsonl"})
+	if err != nil || custom != "/var/log/gog.jsonl" {
+		t.Fatalf("unexpected custom path %q %v", custom, err)
+	}
+}
+
+func TestSessionContext(t *testing.T) {
+	if SessionFromContext(context.Background()) != nil {
+		t.Fatalf("expected no session")
+	}
+	s := &Session{Run: "r1"}
+	if got := SessionFromContext(WithSession(context.Background(), s)); got != s {
+		t.Fatalf("expected session round trip")
+	}
+}
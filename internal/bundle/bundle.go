@@ -0,0 +1,200 @@
+// Package bundle seals everything needed to use gog on another machine
+// (refresh tokens, OAuth clients, service account keys, account mappings
+// and keyring secrets) into one passphrase-encrypted file.
+package bundle
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	Format  = "gogbundle"
+	Version = 1
+
+	kdfScrypt = "scrypt"
+	saltSize  = 16
+	keySize   = 32
+
+	maxScryptN  = 1 << 20
+	maxScryptRP = 1 << 6
+
+	// MinPassphraseLength keeps bundles from being sealed with trivially
+	// guessable passphrases.
+	MinPassphraseLength = 8
+)
+
+var (
+	errShortPassphrase = fmt.Errorf("passphrase must be at least %d characters", MinPassphraseLength)
+	errNotBundle       = errors.New("not a gog bundle")
+	errUnsupportedKDF  = errors.New("unsupported key derivation")
+	// ErrWrongPassphrase is returned when a bundle cannot be decrypted,
+	// either because the passphrase is wrong or the file was modified.
+	ErrWrongPassphrase = errors.New("wrong passphrase or corrupted bundle")
+)
+
+// scrypt cost parameters for new bundles; stored in the envelope so they
+// can be raised later without breaking old files.
+var (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// Bundle is the plaintext content of a bundle file.
+type Bundle struct {
+	CreatedAt       time.Time         `json:"created_at"`
+	Tokens          []Token           `json:"tokens,omitempty"`
+	DefaultAccounts map[string]string `json:"default_accounts,omitempty"`
+	Clients         []Client          `json:"clients,omitempty"`
+	ServiceAccounts []ServiceAccount  `json:"service_accounts,omitempty"`
+	AccountAliases  map[string]string `json:"account_aliases,omitempty"`
+	AccountClients  map[string]string `json:"account_clients,omitempty"`
+	ClientDomains   map[string]string `json:"client_domains,omitempty"`
+	Secrets         map[string][]byte `json:"secrets,omitempty"`
+}
+
+type Token struct {
+	Client       string    `json:"client"`
+	Email        string    `json:"email"`
+	Services     []string  `json:"services,omitempty"`
+	Scopes       []string  `json:"scopes,omitempty"`
+	CreatedAt    time.Time `json:"created_at,omitempty"`
+	RefreshToken string    `json:"refresh_token"`
+}
+
+type Client struct {
+	Client       string `json:"client"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+}
+
+type ServiceAccount struct {
+	Email string          `json:"email"`
+	Key   json.RawMessage `json:"key"`
+}
+
+// envelope is the on-disk format. Only the ciphertext is secret; the KDF
+// parameters and the format header are authenticated as additional data.
+type envelope struct {
+	Format     string `json:"format"`
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+func (e envelope) additionalData() []byte {
+	return []byte(fmt.Sprintf("%s/%d/%s/%d/%d/%d", e.Format, e.Version, e.KDF, e.N, e.R, e.P))
+}
+
+// Seal encrypts b with a key derived from passphrase (scrypt, AES-256-GCM).
+func Seal(b Bundle, passphrase string) ([]byte, error) {
+	if len(passphrase) < MinPassphraseLength {
+		return nil, errShortPassphrase
+	}
+
+	plaintext, err := json.Marshal(b)
+	if err != nil {
+		return nil, fmt.Errorf("encode bundle: %w", err)
+	}
+
+	env := envelope{Format: Format, Version: Version, KDF: kdfScrypt, N: scryptN, R: scryptR, P: scryptP, Salt: make([]byte, saltSize)}
+	if _, err := rand.Read(env.Salt); err != nil {
+		return nil, fmt.Errorf("salt: %w", err)
+	}
+
+	aead, err := newAEAD(env, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	env.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(env.Nonce); err != nil {
+		return nil, fmt.Errorf("nonce: %w", err)
+	}
+	env.Ciphertext = aead.Seal(nil, env.Nonce, plaintext, env.additionalData())
+
+	out, err := json.MarshalIndent(env, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode envelope: %w", err)
+	}
+
+	return append(out, '\n'), nil
+}
+
+// Open decrypts a bundle produced by Seal.
+func Open(data []byte, passphrase string) (Bundle, error) {
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil || env.Format != Format {
+		return Bundle{}, errNotBundle
+	}
+
+	if env.Version != Version {
+		return Bundle{}, fmt.Errorf("%w: unsupported version %d", errNotBundle, env.Version)
+	}
+
+	aead, err := newAEAD(env, passphrase)
+	if err != nil {
+		return Bundle{}, err
+	}
+
+	if len(env.Nonce) != aead.NonceSize() {
+		return Bundle{}, ErrWrongPassphrase
+	}
+
+	plaintext, err := aead.Open(nil, env.Nonce, env.Ciphertext, env.additionalData())
+	if err != nil {
+		return Bundle{}, ErrWrongPassphrase
+	}
+
+	var b Bundle
+	if err := json.Unmarshal(plaintext, &b); err != nil {
+		return Bundle{}, fmt.Errorf("decode bundle: %w", err)
+	}
+
+	return b, nil
+}
+
+func newAEAD(env envelope, passphrase string) (cipher.AEAD, error) {
+	if env.KDF != kdfScrypt {
+		return nil, fmt.Errorf("%w %q", errUnsupportedKDF, env.KDF)
+	}
+
+	// Refuse parameters scrypt cannot use (it divides by r and p) and those
+	// that would make a crafted file eat all memory.
+	if env.N <= 1 || env.R < 1 || env.P < 1 || len(env.Salt) != saltSize {
+		return nil, fmt.Errorf("%w: invalid scrypt parameters", errUnsupportedKDF)
+	}
+	if env.N > maxScryptN || env.R > maxScryptRP || env.P > maxScryptRP || env.R*env.P > maxScryptRP {
+		return nil, fmt.Errorf("%w: scrypt parameters too large", errUnsupportedKDF)
+	}
+
+	key, err := scrypt.Key([]byte(passphrase), env.Salt, env.N, env.R, env.P, keySize)
+	if err != nil {
+		return nil, fmt.Errorf("derive key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("new cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("new gcm: %w", err)
+	}
+
+	return aead, nil
+}
@@ -0,0 +1,104 @@
+package bundle
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSealOpen(t *testing.T) {
+	in := Bundle{
+		CreatedAt:       time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Tokens:          []Token{{Client: "default", Email: "a@b.com", Services: []string{"gmail"}, RefreshToken: "1//secret"}},
+		DefaultAccounts: map[string]string{"default": "a@b.com"},
+		Clients:         []Client{{Client: "default", ClientID: "id", ClientSecret: "cs"}},
+		ServiceAccounts: []ServiceAccount{{Email: "w@corp.com", Key: json.RawMessage(`{"type":"service_account"}`)}},
+		AccountAliases:  map[string]string{"work": "w@corp.com"},
+		Secrets:         map[string][]byte{"tracking/a@b.com/tracking_key": []byte("k")},
+	}
+
+	data, err := Seal(in, "correct horse")
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+
+	var env map[string]any
+	if err := json.Unmarshal(data, &env); err != nil || env["format"] != Format || env["kdf"] != kdfScrypt {
+		t.Fatalf("unexpected envelope: %v %s", err, data)
+	}
+
+	out, err := Open(data, "correct horse")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if !out.CreatedAt.Equal(in.CreatedAt) || len(out.Tokens) != 1 || out.Tokens[0].RefreshToken != "1//secret" ||
+		out.Clients[0].ClientSecret != "cs" || string(out.ServiceAccounts[0].Key) != `{"type":"service_account"}` ||
+		out.AccountAliases["work"] != "w@corp.com" || string(out.Secrets["tracking/a@b.com/tracking_key"]) != "k" {
+		t.Fatalf("roundtrip mismatch: %+v", out)
+	}
+
+	if _, err := Open(data, "wrong horse"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("expected wrong passphrase, got %v", err)
+	}
+}
+
+func TestSealOpen_Errors(t *testing.T) {
+	if _, err := Seal(Bundle{}, "short"); err == nil {
+		t.Fatalf("expected short passphrase error")
+	}
+	if _, err := Open([]byte(`{"refresh_token":"x"}`), "passphrase"); !errors.Is(err, errNotBundle) {
+		t.Fatalf("expected not-a-bundle error, got %v", err)
+	}
+
+	data, err := Seal(Bundle{}, "passphrase")
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	// The KDF parameters are authenticated.
+	env.N = 1 << 14
+	tampered, _ := json.Marshal(env)
+	if _, err := Open(tampered, "passphrase"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("expected tampering to be detected, got %v", err)
+	}
+
+	env.N = 1 << 30
+	huge, _ := json.Marshal(env)
+	if _, err := Open(huge, "passphrase"); !errors.Is(err, errUnsupportedKDF) {
+		t.Fatalf("expected oversized parameters to be refused, got %v", err)
+	}
+}
+
+func TestOpen_InvalidKDFParameters(t *testing.T) {
+	data, err := Seal(Bundle{}, "passphrase")
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+
+	for name, mutate := range map[string]func(*envelope){
+		"n=0":        func(e *envelope) { e.N = 0 },
+		"n=1":        func(e *envelope) { e.N = 1 },
+		"r=0":        func(e *envelope) { e.R = 0 },
+		"p=0":        func(e *envelope) { e.P = 0 },
+		"negative r": func(e *envelope) { e.R, e.P = -1, -1 },
+		"huge r":     func(e *envelope) { e.R = 1 << 62 },
+		"short salt": func(e *envelope) { e.Salt = e.Salt[:4] },
+	} {
+		t.Run(name, func(t *testing.T) {
+			var env envelope
+			if err := json.Unmarshal(data, &env); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			mutate(&env)
+			bad, _ := json.Marshal(env)
+			if _, err := Open(bad, "passphrase"); !errors.Is(err, errUnsupportedKDF) {
+				t.Fatalf("expected invalid parameters to be refused, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	gapi "google.golang.org/api/googleapi"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/internal/googleauth"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+var newAPIClient = googleapi.NewHTTPClient
+
+// apiMaxPages bounds --paginate; the pending token is printed when it is hit.
+var apiMaxPages = 1000
+
+// APICmd sends a raw REST request with the stored credentials, for
+// endpoints that have no dedicated command.
+type APICmd struct {
+	Service  string   `arg:"" name:"service" help:"Service whose scopes and base URL to use (gmail|calendar|drive|docs|sheets|tasks|people|contacts|chat|classroom|groups|keep)"`
+	Method   string   `arg:"" name:"method" help:"HTTP method (GET|POST|PUT|PATCH|DELETE)"`
+	Path     string   `arg:"" name:"path" help:"Path relative to the service base URL (e.g. users/me/settings/imap), or a full https://*.googleapis.com URL"`
+	Body     string   `name:"body" help:"JSON request body file (- for stdin)"`
+	Params   []string `name:"param" short:"p" sep:"none" help:"Query parameter key=value (can be repeated)"`
+	Paginate bool     `name:"paginate" help:"Follow nextPageToken and merge list fields from all pages (GET only)"`
+}
+
+func (c *APICmd) Run(ctx context.Context, flags *RootFlags) error {
+	service, err := googleauth.ParseService(c.Service)
+	if err != nil {
+		return usage(err.Error())
+	}
+
+	method := strings.ToUpper(strings.TrimSpace(c.Method))
+	switch method {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return usagef("invalid method %q (expected GET|POST|PUT|PATCH|DELETE)", c.Method)
+	}
+	if c.Paginate && method != http.MethodGet {
+		return usage("--paginate only works with GET")
+	}
+
+	target, err := apiURL(service, c.Path, c.Params)
+	if err != nil {
+		return err
+	}
+
+	body, err := readAPIBody(c.Body)
+	if err != nil {
+		return err
+	}
+
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+
+	client, err := newAPIClient(ctx, service, account)
+	if err != nil {
+		return err
+	}
+
+	if !c.Paginate {
+		data, header, err := doAPIRequest(ctx, client, method, target, body)
+		if err != nil {
+			return err
+		}
+		return writeAPIResponse(ctx, data, header)
+	}
+
+	merged, err := paginateAPI(ctx, client, target)
+	if err != nil {
+		return err
+	}
+	return outfmt.Write(ctx, os.Stdout, merged)
+}
+
+// apiURL resolves path against the service base URL and appends params.
+// Full URLs must point at googleapis.com so tokens never leave Google.
+func apiURL(service googleauth.Service, path string, params []string) (*url.URL, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, usage("empty path")
+	}
+
+	var u *url.URL
+	if strings.Contains(path, "://") {
+		parsed, err := url.Parse(path)
+		if err != nil {
+			return nil, usagef("invalid URL %q: %v", path, err)
+		}
+		host := strings.ToLower(parsed.Hostname())
+		if parsed.Scheme != "https" || (host != "googleapis.com" && !strings.HasSuffix(host, ".googleapis.com")) {
+			return nil, usagef("refusing to send credentials to %q (only https://*.googleapis.com)", path)
+		}
+		u = parsed
+	} else {
+		base, err := googleauth.BaseURL(service)
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := url.Parse(base + strings.TrimPrefix(path, "/"))
+		if err != nil {
+			return nil, usagef("invalid path %q: %v", path, err)
+		}
+		u = parsed
+	}
+
+	if len(params) > 0 {
+		q := u.Query()
+		for _, p := range params {
+			k, v, ok := strings.Cut(p, "=")
+			if !ok || strings.TrimSpace(k) == "" {
+				return nil, usagef("invalid --param %q (expected key=value)", p)
+			}
+			q.Add(strings.TrimSpace(k), v)
+		}
+		u.RawQuery = q.Encode()
+	}
+
+	return u, nil
+}
+
+func readAPIBody(path string) ([]byte, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, nil
+	}
+
+	var (
+		b   []byte
+		err error
+	)
+	if path == "-" {
+		b, err = io.ReadAll(os.Stdin)
+	} else {
+		path, err = config.ExpandPath(path)
+		if err != nil {
+			return nil, err
+		}
+		b, err = os.ReadFile(path) //nolint:gosec // user-provided path
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read body: %w", err)
+	}
+	if len(bytes.TrimSpace(b)) > 0 && !json.Valid(b) {
+		return nil, usage("--body must contain valid JSON")
+	}
+	return b, nil
+}
+
+func doAPIRequest(ctx context.Context, client *http.Client, method string, u *url.URL, body []byte) ([]byte, http.Header, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("build request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := gapi.CheckResponse(resp); err != nil {
+		return nil, nil, err
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read response: %w", err)
+	}
+	return data, resp.Header, nil
+}
+
+// writeAPIResponse renders JSON replies through the output format; other
+// content (e.g. alt=media downloads) is copied to stdout as-is.
+func writeAPIResponse(ctx context.Context, data []byte, header http.Header) error {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	if mediaType == "application/json" || (mediaType == "" && json.Valid(data)) {
+		if v, err := decodeAPIJSON(data); err == nil {
+			return outfmt.Write(ctx, os.Stdout, v)
+		}
+	}
+
+	_, err := os.Stdout.Write(data)
+	return err
+}
+
+// decodeAPIJSON keeps numbers as json.Number so large IDs survive re-encoding.
+func decodeAPIJSON(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// paginateAPI follows nextPageToken and appends every array field of later
+// pages to the first page, e.g. "files" for drive files.list. After
+// apiMaxPages pages it stops, keeps the pending nextPageToken in the result
+// and tells how to resume.
+func paginateAPI(ctx context.Context, client *http.Client, u *url.URL) (map[string]any, error) {
+	var merged map[string]any
+	pageToken := ""
+	for page := 0; page < apiMaxPages; page++ {
+		pu := *u
+		if pageToken != "" {
+			q := pu.Query()
+			q.Set("pageToken", pageToken)
+			pu.RawQuery = q.Encode()
+		}
+
+		data, _, err := doAPIRequest(ctx, client, http.MethodGet, &pu, nil)
+		if err != nil {
+			return nil, err
+		}
+		v, err := decodeAPIJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("decode page %d: %w", page+1, err)
+		}
+		resp, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("decode page %d: expected a JSON object", page+1)
+		}
+
+		pageToken, _ = resp["nextPageToken"].(string)
+		delete(resp, "nextPageToken")
+
+		if merged == nil {
+			merged = resp
+		} else {
+			for k, v := range resp {
+				items, ok := v.([]any)
+				if !ok {
+					continue
+				}
+				prev, _ := merged[k].([]any)
+				merged[k] = append(prev, items...)
+			}
+		}
+
+		if pageToken == "" {
+			break
+		}
+	}
+	if merged == nil {
+		merged = map[string]any{}
+	}
+	if pageToken != "" {
+		merged["nextPageToken"] = pageToken
+		if u := ui.FromContext(ctx); u != nil {
+			u.Err().Printf("# Stopped after %d pages; resume with --param pageToken=%s", apiMaxPages, pageToken)
+		}
+	}
+	return merged, nil
+}
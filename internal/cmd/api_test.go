@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steipete/gogcli/internal/googleauth"
+)
+
+// redirectTransport sends every request to srv, keeping path and query.
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (rt redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := req.Clone(req.Context())
+	r.URL.Scheme = rt.target.Scheme
+	r.URL.Host = rt.target.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func stubAPIClient(t *testing.T, handler http.HandlerFunc) *googleauth.Service {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	target, _ := url.Parse(srv.URL)
+
+	var used googleauth.Service
+	orig := newAPIClient
+	t.Cleanup(func() { newAPIClient = orig })
+	newAPIClient = func(_ context.Context, service googleauth.Service, _ string) (*http.Client, error) {
+		used = service
+		return &http.Client{Transport: redirectTransport{target: target}}, nil
+	}
+	return &used
+}
+
+func TestAPICmd_GetWithParams(t *testing.T) {
+	used := stubAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/gmail/v1/users/me/settings/imap" {
+			http.Error(w, "unexpected "+r.Method+" "+r.URL.Path, http.StatusBadRequest)
+			return
+		}
+		if got := r.URL.Query().Get("q"); got != "a,b" {
+			http.Error(w, "unexpected q "+got, http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		_, _ = io.WriteString(w, `{"enabled":true,"maxFolderSize":12345678901234}`)
+	})
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--account", "a@b.com", "api", "gmail", "get", "users/me/settings/imap", "-p", "q=a,b"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	if *used != googleauth.ServiceGmail {
+		t.Fatalf("unexpected service: %q", *used)
+	}
+	if !strings.Contains(out, `"maxFolderSize": 12345678901234`) || !strings.Contains(out, `"enabled": true`) {
+		t.Fatalf("unexpected output: %q", out)
+	}
+}
+
+func TestAPICmd_Paginate(t *testing.T) {
+	stubAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("pageToken") {
+		case "":
+			_, _ = io.WriteString(w, `{"kind":"drive#revisionList","revisions":[{"id":"1"}],"nextPageToken":"p2"}`)
+		case "p2":
+			_, _ = io.WriteString(w, `{"kind":"drive#revisionList","revisions":[{"id":"2"},{"id":"3"}]}`)
+		default:
+			http.Error(w, "bad token", http.StatusBadRequest)
+		}
+	})
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--json", "--account", "a@b.com", "api", "drive", "GET", "files/f1/revisions", "--paginate"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+
+	var parsed struct {
+		Kind          string           `json:"kind"`
+		Revisions     []map[string]any `json:"revisions"`
+		NextPageToken string           `json:"nextPageToken"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if parsed.Kind != "drive#revisionList" || len(parsed.Revisions) != 3 || parsed.NextPageToken != "" {
+		t.Fatalf("unexpected merge: %+v", parsed)
+	}
+}
+
+func TestAPICmd_PaginateStopsAtMaxPages(t *testing.T) {
+	orig := apiMaxPages
+	t.Cleanup(func() { apiMaxPages = orig })
+	apiMaxPages = 2
+
+	stubAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		next := r.URL.Query().Get("pageToken") + "x"
+		_, _ = io.WriteString(w, `{"files":[{"id":"`+next+`"}],"nextPageToken":"`+next+`"}`)
+	})
+
+	var out string
+	errOut := captureStderr(t, func() {
+		out = captureStdout(t, func() {
+			if err := Execute([]string{"--json", "--account", "a@b.com", "api", "drive", "GET", "files", "--paginate"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+
+	var parsed struct {
+		Files         []map[string]any `json:"files"`
+		NextPageToken string           `json:"nextPageToken"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if len(parsed.Files) != 2 || parsed.NextPageToken != "xx" {
+		t.Fatalf("expected two pages and the pending token, got %+v", parsed)
+	}
+	if !strings.Contains(errOut, "resume with --param pageToken=xx") {
+		t.Fatalf("expected resume hint, got %q", errOut)
+	}
+}
+
+func TestAPICmd_PostBodyFile(t *testing.T) {
+	stubAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method != http.MethodPatch || r.Header.Get("Content-Type") != "application/json" || string(body) != `{"autoReply":true}` {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	})
+
+	path := filepath.Join(t.TempDir(), "body.json")
+	if err := os.WriteFile(path, []byte(`{"autoReply":true}`), 0o600); err != nil {
+		t.Fatalf("write body: %v", err)
+	}
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--account", "a@b.com", "api", "calendar", "PATCH", "/users/me/settings/x", "--body", path}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	if !strings.Contains(out, `"autoReply": true`) {
+		t.Fatalf("unexpected output: %q", out)
+	}
+}
+
+func TestAPICmd_NotFoundExitCode(t *testing.T) {
+	stubAPIClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, `{"error":{"code":404,"message":"File not found: x","errors":[{"reason":"notFound","message":"File not found: x"}]}}`)
+	})
+
+	var err error
+	_ = captureStderr(t, func() {
+		err = Execute([]string{"--account", "a@b.com", "api", "drive", "GET", "files/x"})
+	})
+	if err == nil || ExitCode(err) != 6 {
+		t.Fatalf("expected not found exit code, got %v (%d)", err, ExitCode(err))
+	}
+}
+
+func TestAPIURL(t *testing.T) {
+	u, err := apiURL(googleauth.ServiceTasks, "users/@me/lists", []string{"maxResults=5"})
+	if err != nil {
+		t.Fatalf("apiURL: %v", err)
+	}
+	if u.String() != "https://tasks.googleapis.com/tasks/v1/users/@me/lists?maxResults=5" {
+		t.Fatalf("unexpected URL: %s", u)
+	}
+
+	if u, err := apiURL(googleauth.ServiceDrive, "https://www.googleapis.com/drive/v2/about", nil); err != nil || u.Path != "/drive/v2/about" {
+		t.Fatalf("full URL: %v %v", u, err)
+	}
+
+	for _, bad := range []string{"https://example.com/x", "http://www.googleapis.com/drive/v3/files", "https://googleapis.com.evil.test/x"} {
+		if _, err := apiURL(googleauth.ServiceDrive, bad, nil); err == nil || ExitCode(err) != 2 {
+			t.Fatalf("%s: expected usage error, got %v", bad, err)
+		}
+	}
+	if _, err := apiURL(googleauth.ServiceDrive, "files", []string{"novalue"}); err == nil {
+		t.Fatalf("expected error for malformed --param")
+	}
+}
@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/steipete/gogcli/internal/audit"
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+type AuditCmd struct {
+	List AuditListCmd `cmd:"" default:"withargs" help:"List recorded write operations (newest first)"`
+	Show AuditShowCmd `cmd:"" help:"Show one audit entry"`
+	Path AuditPathCmd `cmd:"" help:"Print audit log path"`
+}
+
+// withAuditSession enables write auditing for this invocation when the
+// audit_log config key is set.
+func withAuditSession(ctx context.Context, kctx *kong.Context) context.Context {
+	cfg, err := config.ReadConfig()
+	if err != nil || !cfg.AuditLog {
+		return ctx
+	}
+	path, err := audit.PathFor(cfg)
+	if err != nil {
+		slog.Warn("audit log disabled", "err", err)
+		return ctx
+	}
+
+	return audit.WithSession(ctx, &audit.Session{
+		Log:     &audit.Log{Path: path},
+		Run:     audit.NewID(),
+		Command: auditCommandPath(kctx),
+	})
+}
+
+// auditCommandPath keeps command words and drops positional placeholders.
+func auditCommandPath(kctx *kong.Context) string {
+	fields := strings.Fields(kctx.Command())
+	words := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if strings.HasPrefix(f, "<") {
+			continue
+		}
+		words = append(words, f)
+	}
+
+	return strings.Join(words, " ")
+}
+
+func auditLogPath() (string, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return "", err
+	}
+
+	return audit.PathFor(cfg)
+}
+
+type AuditListCmd struct {
+	Since   string `name:"since" help:"Only entries at/after this time (duration like 24h/7d, RFC3339, date, today, yesterday)"`
+	Until   string `name:"until" help:"Only entries before this time (same formats as --since)"`
+	Service string `name:"service" help:"Only entries for this service (gmail, drive, calendar, ...)"`
+	Max     int    `name:"max" aliases:"limit" help:"Max results" default:"50"`
+}
+
+func (c *AuditListCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+
+	// --account filters instead of selecting credentials here.
+	account := strings.TrimSpace(flags.Account)
+	if resolved, ok, err := resolveAccountAlias(account); err != nil {
+		return err
+	} else if ok {
+		account = resolved
+	} else if shouldAutoSelectAccount(account) {
+		account = ""
+	}
+
+	now := time.Now()
+	since, err := parseAuditTime(c.Since, now)
+	if err != nil {
+		return usage(fmt.Sprintf("invalid --since: %v", err))
+	}
+	until, err := parseAuditTime(c.Until, now)
+	if err != nil {
+		return usage(fmt.Sprintf("invalid --until: %v", err))
+	}
+
+	path, err := auditLogPath()
+	if err != nil {
+		return err
+	}
+	entries, err := audit.Read(path, audit.Filter{
+		Since:   since,
+		Until:   until,
+		Account: account,
+		Service: strings.TrimSpace(c.Service),
+		Limit:   c.Max,
+	})
+	if err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		if entries == nil {
+			entries = []audit.Entry{}
+		}
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"entries": entries})
+	}
+
+	if len(entries) == 0 {
+		u.Err().Println("No audit entries")
+		return nil
+	}
+
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "ID\tTIME\tACCOUNT\tCOMMAND\tMETHOD\tRESOURCE\tOUTCOME")
+	for _, e := range entries {
+		outcome := e.Outcome
+		if e.Status != 0 {
+			outcome += " " + strconv.Itoa(e.Status)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			e.ID,
+			e.Time.Local().Format("2006-01-02 15:04:05"),
+			sanitizeTab(e.Account),
+			sanitizeTab(e.Command),
+			e.Method,
+			sanitizeTab(e.Resource),
+			outcome,
+		)
+	}
+	return nil
+}
+
+// parseAuditTime accepts a lookback duration ("90m", "24h", "7d") or any
+// time expression understood by parseTimeExpr.
+func parseAuditTime(expr string, now time.Time) (time.Time, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return time.Time{}, nil
+	}
+	if days, ok := strings.CutSuffix(expr, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(expr); err == nil {
+		return now.Add(-d), nil
+	}
+
+	return parseTimeExpr(expr, now, time.Local)
+}
+
+type AuditShowCmd struct {
+	ID string `arg:"" name:"id" help:"Entry ID (or unique prefix)"`
+}
+
+func (c *AuditShowCmd) Run(ctx context.Context) error {
+	u := ui.FromContext(ctx)
+	id := strings.TrimSpace(c.ID)
+	if id == "" {
+		return usage("empty id")
+	}
+
+	path, err := auditLogPath()
+	if err != nil {
+		return err
+	}
+	e, ok, err := audit.Find(path, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("audit entry not found: %s", id)
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"entry": e})
+	}
+
+	u.Out().Printf("id\t%s", e.ID)
+	u.Out().Printf("time\t%s", e.Time.Local().Format(time.RFC3339))
+	u.Out().Printf("run\t%s", e.Run)
+	u.Out().Printf("account\t%s", e.Account)
+	if e.Client != "" {
+		u.Out().Printf("client\t%s", e.Client)
+	}
+	u.Out().Printf("command\t%s", e.Command)
+	u.Out().Printf("service\t%s", e.Service)
+	u.Out().Printf("method\t%s", e.Method)
+	u.Out().Printf("resource\t%s", e.Resource)
+	if e.ResourceID != "" {
+		u.Out().Printf("resource_id\t%s", e.ResourceID)
+	}
+	if e.Request != "" {
+		u.Out().Printf("request\t%s", e.Request)
+	}
+	if e.Status != 0 {
+		u.Out().Printf("status\t%d", e.Status)
+	}
+	u.Out().Printf("outcome\t%s", e.Outcome)
+	if e.Error != "" {
+		u.Out().Printf("error\t%s", e.Error)
+	}
+	return nil
+}
+
+type AuditPathCmd struct{}
+
+func (c *AuditPathCmd) Run(ctx context.Context) error {
+	path, err := auditLogPath()
+	if err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"path": path})
+	}
+	fmt.Fprintln(os.Stdout, path)
+	return nil
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steipete/gogcli/internal/audit"
+)
+
+func TestExecute_AuditListAndShow(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg"))
+
+	path, err := audit.DefaultPath()
+	if err != nil {
+		t.Fatalf("DefaultPath: %v", err)
+	}
+	log := &audit.Log{Path: path}
+	now := time.Now().UTC()
+	for _, e := range []audit.Entry{
+		{ID: "old111", Time: now.Add(-48 * time.Hour), Account: "a@b.com", Service: "gmail", Method: "POST", Resource: "/gmail/v1/users/me/messages/send", Outcome: audit.OutcomeOK},
+		{ID: "new222", Time: now.Add(-time.Hour), Account: "a@b.com", Service: "drive", Method: "DELETE", Resource: "/drive/v3/files/f1", Command: "drive delete", Outcome: audit.OutcomeOK, Status: 204},
+		{ID: "new333", Time: now.Add(-time.Minute), Account: "c@d.com", Service: "drive", Method: "PATCH", Resource: "/drive/v3/files/f2", Outcome: audit.OutcomeOK},
+	} {
+		if err := log.Append(e); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--json", "--account", "a@b.com", "audit", "list", "--since", "1d", "--service", "drive"}); err != nil {
+			t.Fatalf("audit list: %v", err)
+		}
+	})
+	var listed struct {
+		Entries []audit.Entry `json:"entries"`
+	}
+	if err := json.Unmarshal([]byte(out), &listed); err != nil {
+		t.Fatalf("decode %q: %v", out, err)
+	}
+	if len(listed.Entries) != 1 || listed.Entries[0].ID != "new222" {
+		t.Fatalf("unexpected entries: %+v", listed.Entries)
+	}
+
+	out = captureStdout(t, func() {
+		if err := Execute([]string{"--plain", "audit", "show", "new2"}); err != nil {
+			t.Fatalf("audit show: %v", err)
+		}
+	})
+	if !strings.Contains(out, "command\tdrive delete") || !strings.Contains(out, "status\t204") {
+		t.Fatalf("unexpected show output: %q", out)
+	}
+}
+
+func TestParseAuditTime(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	for expr, want := range map[string]time.Time{
+		"":                     {},
+		"2h":                   now.Add(-2 * time.Hour),
+		"7d":                   now.AddDate(0, 0, -7),
+		"2026-01-05T10:00:00Z": time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC),
+	} {
+		got, err := parseAuditTime(expr, now)
+		if err != nil || !got.Equal(want) {
+			t.Fatalf("parseAuditTime(%q) = %v, %v; want %v", expr, got, err, want)
+		}
+	}
+	if _, err := parseAuditTime("whenever", now); err == nil {
+		t.Fatalf("expected error")
+	}
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/steipete/gogcli/internal/authclient"
 	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/googleapi"
 	"github.com/steipete/gogcli/internal/googleauth"
 	"github.com/steipete/gogcli/internal/outfmt"
 	"github.com/steipete/gogcli/internal/secrets"
@@ -26,6 +27,7 @@ var (
 	checkRefreshToken    = googleauth.CheckRefreshToken
 	ensureKeychainAccess = secrets.EnsureKeychainAccess
 	fetchAuthorizedEmail = googleauth.EmailForRefreshToken
+	flushTokenCache      = googleapi.FlushTokenCache
 )
 
 func ensureKeychainAccessIfNeeded() error {
@@ -47,6 +49,7 @@ const (
 	authTypeOAuth               = "oauth"
 	authTypeServiceAccount      = "service_account"
 	authTypeOAuthServiceAccount = "oauth+service_account"
+	authTypeADC                 = "adc"
 )
 
 type AuthCmd struct {
@@ -59,8 +62,10 @@ type AuthCmd struct {
 	Keyring     AuthKeyringCmd        `cmd:"" name:"keyring" help:"Configure keyring backend"`
 	Remove      AuthRemoveCmd         `cmd:"" name:"remove" help:"Remove a stored refresh token"`
 	Tokens      AuthTokensCmd         `cmd:"" name:"tokens" help:"Manage stored refresh tokens"`
+	Bundle      AuthBundleCmd         `cmd:"" name:"bundle" help:"Export or import an encrypted bundle of all accounts, clients and secrets"`
 	Manage      AuthManageCmd         `cmd:"" name:"manage" help:"Open accounts manager in browser" aliases:"login"`
 	ServiceAcct AuthServiceAccountCmd `cmd:"" name:"service-account" help:"Configure service account (Workspace only; domain-wide delegation)"`
+	ADC         AuthADCCmd            `cmd:"" name:"adc" help:"Use Application Default Credentials, workload identity or service account impersonation"`
 	Keep        AuthKeepCmd           `cmd:"" name:"keep" help:"Configure service account for Google Keep (Workspace only)"`
 }
 
@@ -120,7 +125,7 @@ func (c *AuthCredentialsSetCmd) Run(ctx context.Context) error {
 		}
 	}
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"saved":  true,
 			"path":   outPath,
 			"client": client,
@@ -192,14 +197,14 @@ func (c *AuthCredentialsListCmd) Run(ctx context.Context) error {
 
 	if len(entries) == 0 {
 		if outfmt.IsJSON(ctx) {
-			return outfmt.WriteJSON(os.Stdout, map[string]any{"clients": []entry{}})
+			return outfmt.Write(ctx, os.Stdout, map[string]any{"clients": []entry{}})
 		}
 		u.Err().Println("No OAuth client credentials stored")
 		return nil
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"clients": entries})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"clients": entries})
 	}
 
 	w, done := tableWriter(ctx)
@@ -216,6 +221,7 @@ type AuthTokensCmd struct {
 	Delete AuthTokensDeleteCmd `cmd:"" name:"delete" help:"Delete a stored refresh token"`
 	Export AuthTokensExportCmd `cmd:"" name:"export" help:"Export a refresh token to a file (contains secrets)"`
 	Import AuthTokensImportCmd `cmd:"" name:"import" help:"Import a refresh token file into keyring (contains secrets)"`
+	Flush  AuthTokensFlushCmd  `cmd:"" name:"flush" help:"Drop cached access tokens (next call refreshes)"`
 }
 
 type AuthTokensListCmd struct{}
@@ -241,13 +247,13 @@ func (c *AuthTokensListCmd) Run(ctx context.Context) error {
 
 	if len(filtered) == 0 {
 		if outfmt.IsJSON(ctx) {
-			return outfmt.WriteJSON(os.Stdout, map[string]any{"keys": []string{}})
+			return outfmt.Write(ctx, os.Stdout, map[string]any{"keys": []string{}})
 		}
 		u.Err().Println("No tokens stored")
 		return nil
 	}
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"keys": filtered})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"keys": filtered})
 	}
 	for _, k := range filtered {
 		u.Out().Println(k)
@@ -282,7 +288,7 @@ func (c *AuthTokensDeleteCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"deleted": true,
 			"email":   email,
 			"client":  client,
@@ -371,7 +377,7 @@ func (c *AuthTokensExportCmd) Run(ctx context.Context) error {
 
 	u.Err().Println("WARNING: exported file contains a refresh token (keep it safe and delete it when done)")
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"exported": true,
 			"email":    tok.Email,
 			"client":   client,
@@ -466,7 +472,7 @@ func (c *AuthTokensImportCmd) Run(ctx context.Context) error {
 
 	u.Err().Println("Imported refresh token into keyring")
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"imported": true,
 			"email":    ex.Email,
 			"client":   client,
@@ -478,6 +484,21 @@ func (c *AuthTokensImportCmd) Run(ctx context.Context) error {
 	return nil
 }
 
+type AuthTokensFlushCmd struct{}
+
+func (c *AuthTokensFlushCmd) Run(ctx context.Context) error {
+	u := ui.FromContext(ctx)
+	n, err := flushTokenCache()
+	if err != nil {
+		return err
+	}
+	if outfmt.IsJSON(ctx) {
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"flushed": n})
+	}
+	u.Out().Printf("flushed\t%d", n)
+	return nil
+}
+
 type AuthAddCmd struct {
 	Email        string `arg:"" name:"email" help:"Email"`
 	Manual       bool   `name:"manual" help:"Browserless auth flow (paste redirect URL)"`
@@ -485,6 +506,7 @@ type AuthAddCmd struct {
 	ServicesCSV  string `name:"services" help:"Services to authorize: user|all or comma-separated ${auth_services} (Keep uses service account: gog auth service-account set)" default:"user"`
 	Readonly     bool   `name:"readonly" help:"Use read-only scopes where available (still includes OIDC identity scopes)"`
 	DriveScope   string `name:"drive-scope" help:"Drive scope mode: full|readonly|file" enum:"full,readonly,file" default:"full"`
+	Incremental  bool   `name:"incremental" help:"Keep the services and scopes already granted to this account and add the requested ones"`
 }
 
 func (c *AuthAddCmd) Run(ctx context.Context) error {
@@ -496,28 +518,67 @@ func (c *AuthAddCmd) Run(ctx context.Context) error {
 		return err
 	}
 
-	services, err := parseAuthServices(c.ServicesCSV)
+	authorizedEmail, serviceNames, err := c.authorize(ctx, client)
 	if err != nil {
 		return err
 	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
+			"stored":   true,
+			"email":    authorizedEmail,
+			"services": serviceNames,
+			"client":   client,
+		})
+	}
+	u.Out().Printf("email\t%s", authorizedEmail)
+	u.Out().Printf("services\t%s", strings.Join(serviceNames, ","))
+	u.Out().Printf("client\t%s", client)
+	return nil
+}
+
+// authorize runs the browser flow for client and stores the refresh token.
+// It returns the authorized email and the services stored with the token.
+func (c *AuthAddCmd) authorize(ctx context.Context, client string) (string, []string, error) {
+	services, err := parseAuthServices(c.ServicesCSV)
+	if err != nil {
+		return "", nil, err
+	}
 	if len(services) == 0 {
-		return fmt.Errorf("no services selected")
+		return "", nil, fmt.Errorf("no services selected")
 	}
 
 	if c.Readonly && c.DriveScope == strFile {
-		return usage("cannot combine --readonly with --drive-scope=file (file is write-capable)")
+		return "", nil, usage("cannot combine --readonly with --drive-scope=file (file is write-capable)")
 	}
 	scopes, err := googleauth.ScopesForManageWithOptions(services, googleauth.ScopeOptions{
 		Readonly:   c.Readonly,
 		DriveScope: googleauth.DriveScopeMode(c.DriveScope),
 	})
 	if err != nil {
-		return err
+		return "", nil, err
+	}
+
+	serviceNames := make([]string, 0, len(services))
+	for _, svc := range services {
+		serviceNames = append(serviceNames, string(svc))
 	}
 
+	// Google adds the new grant to the existing one (include_granted_scopes);
+	// keep the stored metadata in step with that.
+	if c.Incremental {
+		if store, storeErr := openSecretsStore(); storeErr == nil {
+			if prior, getErr := store.GetToken(client, c.Email); getErr == nil {
+				scopes = unionStrings(prior.Scopes, scopes)
+				serviceNames = unionStrings(prior.Services, serviceNames)
+			}
+		}
+	}
+	sort.Strings(serviceNames)
+
 	// Pre-flight: ensure keychain is accessible before starting OAuth
 	if keychainErr := ensureKeychainAccessIfNeeded(); keychainErr != nil {
-		return fmt.Errorf("keychain access: %w", keychainErr)
+		return "", nil, fmt.Errorf("keychain access: %w", keychainErr)
 	}
 
 	refreshToken, err := authorizeGoogle(ctx, googleauth.AuthorizeOptions{
@@ -528,26 +589,21 @@ func (c *AuthAddCmd) Run(ctx context.Context) error {
 		Client:       client,
 	})
 	if err != nil {
-		return err
+		return "", nil, err
 	}
 
 	authorizedEmail, err := fetchAuthorizedEmail(ctx, client, refreshToken, scopes, 15*time.Second)
 	if err != nil {
-		return fmt.Errorf("fetch authorized email: %w", err)
+		return "", nil, fmt.Errorf("fetch authorized email: %w", err)
 	}
 	if normalizeEmail(authorizedEmail) != normalizeEmail(c.Email) {
-		return fmt.Errorf("authorized as %s, expected %s", authorizedEmail, c.Email)
+		return "", nil, fmt.Errorf("authorized as %s, expected %s", authorizedEmail, c.Email)
 	}
 
 	store, err := openSecretsStore()
 	if err != nil {
-		return err
+		return "", nil, err
 	}
-	serviceNames := make([]string, 0, len(services))
-	for _, svc := range services {
-		serviceNames = append(serviceNames, string(svc))
-	}
-	sort.Strings(serviceNames)
 
 	if err := store.SetToken(client, authorizedEmail, secrets.Token{
 		Client:       client,
@@ -556,32 +612,35 @@ func (c *AuthAddCmd) Run(ctx context.Context) error {
 		Scopes:       scopes,
 		RefreshToken: refreshToken,
 	}); err != nil {
-		return err
+		return "", nil, err
 	}
-	if override != "" {
+	if authclient.ClientOverrideFromContext(ctx) != "" {
 		cfg, err := config.ReadConfig()
 		if err != nil {
-			return err
+			return "", nil, err
 		}
 		if err := config.SetAccountClient(&cfg, authorizedEmail, client); err != nil {
-			return err
+			return "", nil, err
 		}
 		if err := config.WriteConfig(cfg); err != nil {
-			return err
+			return "", nil, err
 		}
 	}
-	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
-			"stored":   true,
-			"email":    authorizedEmail,
-			"services": serviceNames,
-			"client":   client,
-		})
+	return authorizedEmail, serviceNames, nil
+}
+
+// unionStrings returns a followed by the values of b not already in a.
+func unionStrings(a []string, b []string) []string {
+	seen := make(map[string]struct{}, len(a)+len(b))
+	out := make([]string, 0, len(a)+len(b))
+	for _, v := range append(append([]string{}, a...), b...) {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		out = append(out, v)
 	}
-	u.Out().Printf("email\t%s", authorizedEmail)
-	u.Out().Printf("services\t%s", strings.Join(serviceNames, ","))
-	u.Out().Printf("client\t%s", client)
-	return nil
+	return out
 }
 
 type AuthListCmd struct {
@@ -610,6 +669,7 @@ func (c *AuthStatusCmd) Run(ctx context.Context, flags *RootFlags) error {
 	authPreferred := ""
 	serviceAccountConfigured := false
 	serviceAccountPath := ""
+	adcConfigured := false
 	client := ""
 	credentialsPath := ""
 	credentialsExists := false
@@ -633,8 +693,13 @@ func (c *AuthStatusCmd) Run(ctx context.Context, flags *RootFlags) error {
 				serviceAccountConfigured = true
 				serviceAccountPath = p
 			}
+			if _, ok, adcErr := config.ReadADCAccount(account); adcErr == nil && ok {
+				adcConfigured = true
+			}
 			if serviceAccountConfigured {
 				authPreferred = authTypeServiceAccount
+			} else if adcConfigured {
+				authPreferred = authTypeADC
 			} else {
 				authPreferred = authTypeOAuth
 			}
@@ -642,7 +707,7 @@ func (c *AuthStatusCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"config": map[string]any{
 				"path":   configPath,
 				"exists": configExists,
@@ -659,6 +724,7 @@ func (c *AuthStatusCmd) Run(ctx context.Context, flags *RootFlags) error {
 				"auth_preferred":             authPreferred,
 				"service_account_configured": serviceAccountConfigured,
 				"service_account_path":       serviceAccountPath,
+				"adc_configured":             adcConfigured,
 			},
 		})
 	}
@@ -678,6 +744,7 @@ func (c *AuthStatusCmd) Run(ctx context.Context, flags *RootFlags) error {
 		if serviceAccountPath != "" {
 			u.Out().Printf("service_account_path\t%s", serviceAccountPath)
 		}
+		u.Out().Printf("adc_configured\t%t", adcConfigured)
 	}
 	return nil
 }
@@ -817,7 +884,7 @@ func (c *AuthListCmd) Run(ctx context.Context) error {
 			}
 			out = append(out, it)
 		}
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"accounts": out})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"accounts": out})
 	}
 	if len(entries) == 0 {
 		u.Err().Println("No tokens stored")
@@ -899,7 +966,7 @@ type AuthServicesCmd struct {
 func (c *AuthServicesCmd) Run(ctx context.Context) error {
 	infos := googleauth.ServicesInfo()
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"services": infos})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"services": infos})
 	}
 	if c.Markdown {
 		_, err := io.WriteString(os.Stdout, googleauth.ServicesMarkdown(infos))
@@ -950,7 +1017,7 @@ func (c *AuthRemoveCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"deleted": true,
 			"email":   email,
 			"client":  client,
@@ -1034,7 +1101,7 @@ func (c *AuthKeepCmd) Run(ctx context.Context) error {
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"stored": true,
 			"email":  email,
 			"path":   destPath,
@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// adcCredentialTypes are the credential JSON types golang.org/x/oauth2/google
+// accepts for Application Default Credentials.
+var adcCredentialTypes = map[string]bool{
+	"authorized_user":                  true,
+	"service_account":                  true,
+	"external_account":                 true,
+	"external_account_authorized_user": true,
+	"impersonated_service_account":     true,
+}
+
+type AuthADCCmd struct {
+	Set    AuthADCSetCmd    `cmd:"" name:"set" help:"Use Application Default Credentials for an account"`
+	Unset  AuthADCUnsetCmd  `cmd:"" name:"unset" help:"Stop using Application Default Credentials for an account"`
+	Status AuthADCStatusCmd `cmd:"" name:"status" help:"Show Application Default Credentials setup for an account"`
+}
+
+type AuthADCSetCmd struct {
+	Email       string   `arg:"" name:"email" help:"Account email (Workspace user, or the service account itself)" required:""`
+	Credentials string   `name:"credentials" help:"Credentials JSON (authorized_user, service_account or external_account); default: GOOGLE_APPLICATION_CREDENTIALS, gcloud ADC file, metadata server"`
+	Impersonate string   `name:"impersonate" help:"Service account to impersonate via the IAM Credentials API (domain-wide delegation for user emails)"`
+	Delegates   []string `name:"delegate" help:"Intermediate service account in the impersonation chain (can be repeated)"`
+}
+
+func (c *AuthADCSetCmd) Run(ctx context.Context) error {
+	u := ui.FromContext(ctx)
+
+	email := normalizeEmail(c.Email)
+	if email == "" {
+		return usage("empty email")
+	}
+
+	acct := config.ADCAccount{Impersonate: normalizeEmail(c.Impersonate)}
+	if acct.Impersonate != "" && !strings.HasSuffix(acct.Impersonate, ".gserviceaccount.com") {
+		return usagef("--impersonate must be a service account email, got %q", c.Impersonate)
+	}
+	for _, d := range c.Delegates {
+		if d = normalizeEmail(d); d != "" {
+			acct.Delegates = append(acct.Delegates, d)
+		}
+	}
+	if len(acct.Delegates) > 0 && acct.Impersonate == "" {
+		return usage("--delegate requires --impersonate")
+	}
+
+	credType := ""
+	if strings.TrimSpace(c.Credentials) != "" {
+		path, err := config.ExpandPath(c.Credentials)
+		if err != nil {
+			return err
+		}
+		path, err = filepath.Abs(path)
+		if err != nil {
+			return fmt.Errorf("resolve credentials path: %w", err)
+		}
+		credType, err = readADCCredentialType(path)
+		if err != nil {
+			return err
+		}
+		acct.CredentialsFile = path
+	}
+
+	if err := config.SetADCAccount(email, acct); err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
+			"stored":      true,
+			"email":       email,
+			"credentials": acct.CredentialsFile,
+			"type":        credType,
+			"impersonate": acct.Impersonate,
+			"delegates":   acct.Delegates,
+		})
+	}
+	u.Out().Printf("email\t%s", email)
+	if acct.CredentialsFile != "" {
+		u.Out().Printf("credentials\t%s", acct.CredentialsFile)
+		u.Out().Printf("type\t%s", credType)
+	}
+	if acct.Impersonate != "" {
+		u.Out().Printf("impersonate\t%s", acct.Impersonate)
+	}
+	if len(acct.Delegates) > 0 {
+		u.Out().Printf("delegates\t%s", strings.Join(acct.Delegates, ","))
+	}
+	u.Out().Println("Application Default Credentials configured. Use: gog <cmd> --account " + email)
+	return nil
+}
+
+type AuthADCUnsetCmd struct {
+	Email string `arg:"" name:"email" help:"Account email" required:""`
+}
+
+func (c *AuthADCUnsetCmd) Run(ctx context.Context) error {
+	u := ui.FromContext(ctx)
+
+	email := normalizeEmail(c.Email)
+	if email == "" {
+		return usage("empty email")
+	}
+
+	deleted, err := config.DeleteADCAccount(email)
+	if err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
+			"deleted": deleted,
+			"email":   email,
+		})
+	}
+	u.Out().Printf("deleted\t%t", deleted)
+	u.Out().Printf("email\t%s", email)
+	return nil
+}
+
+type AuthADCStatusCmd struct {
+	Email string `arg:"" name:"email" help:"Account email" required:""`
+}
+
+func (c *AuthADCStatusCmd) Run(ctx context.Context) error {
+	u := ui.FromContext(ctx)
+
+	email := normalizeEmail(c.Email)
+	if email == "" {
+		return usage("empty email")
+	}
+
+	acct, ok, err := config.ReadADCAccount(email)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		if outfmt.IsJSON(ctx) {
+			return outfmt.Write(ctx, os.Stdout, map[string]any{
+				"email":      email,
+				"configured": false,
+				"message":    "no application default credentials configured",
+			})
+		}
+		u.Out().Printf("email\t%s", email)
+		u.Out().Printf("configured\tfalse")
+		return nil
+	}
+
+	source, path := adcSource(acct.CredentialsFile)
+	credType := ""
+	typeErr := ""
+	if path != "" {
+		if t, err := readADCCredentialType(path); err != nil {
+			typeErr = err.Error()
+		} else {
+			credType = t
+		}
+	}
+
+	if outfmt.IsJSON(ctx) {
+		out := map[string]any{
+			"email":       email,
+			"configured":  true,
+			"source":      source,
+			"credentials": path,
+			"type":        credType,
+			"impersonate": acct.Impersonate,
+			"delegates":   acct.Delegates,
+		}
+		if typeErr != "" {
+			out["error"] = typeErr
+		}
+		return outfmt.Write(ctx, os.Stdout, out)
+	}
+	u.Out().Printf("email\t%s", email)
+	u.Out().Printf("configured\ttrue")
+	u.Out().Printf("source\t%s", source)
+	if path != "" {
+		u.Out().Printf("credentials\t%s", path)
+	}
+	if credType != "" {
+		u.Out().Printf("type\t%s", credType)
+	}
+	if typeErr != "" {
+		u.Out().Printf("error\t%s", typeErr)
+	}
+	if acct.Impersonate != "" {
+		u.Out().Printf("impersonate\t%s", acct.Impersonate)
+	}
+	if len(acct.Delegates) > 0 {
+		u.Out().Printf("delegates\t%s", strings.Join(acct.Delegates, ","))
+	}
+	return nil
+}
+
+// adcSource reports where Application Default Credentials will come from,
+// in the lookup order of golang.org/x/oauth2/google.
+func adcSource(credentialsFile string) (string, string) {
+	if v := strings.TrimSpace(credentialsFile); v != "" {
+		return "file", v
+	}
+	if v := strings.TrimSpace(os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")); v != "" {
+		return "GOOGLE_APPLICATION_CREDENTIALS", v
+	}
+	if p := gcloudADCPath(); p != "" {
+		if _, err := os.Stat(p); err == nil {
+			return "gcloud", p
+		}
+	}
+	return "metadata", ""
+}
+
+func gcloudADCPath() string {
+	const name = "application_default_credentials.json"
+	if dir := strings.TrimSpace(os.Getenv("CLOUDSDK_CONFIG")); dir != "" {
+		return filepath.Join(dir, name)
+	}
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("APPDATA"), "gcloud", name)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "gcloud", name)
+}
+
+func readADCCredentialType(path string) (string, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // user-provided path
+	if err != nil {
+		return "", fmt.Errorf("read credentials: %w", err)
+	}
+	var f struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return "", fmt.Errorf("invalid credentials JSON: %w", err)
+	}
+	if !adcCredentialTypes[f.Type] {
+		return "", fmt.Errorf("unsupported credentials type %q", f.Type)
+	}
+	return f.Type, nil
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steipete/gogcli/internal/config"
+)
+
+func TestExecute_AuthADC_SetStatusUnset(t *testing.T) {
+	useTestHome(t)
+
+	credsPath := filepath.Join(t.TempDir(), "wif.json")
+	if err := os.WriteFile(credsPath, []byte(`{"type":"external_account","audience":"//iam.googleapis.com/projects/1/locations/global/workloadIdentityPools/ci/providers/gh","subject_token_type":"urn:ietf:params:oauth:token-type:jwt","token_url":"https://sts.googleapis.com/v1/token","credential_source":{"file":"/tmp/oidc-token"}}`), 0o600); err != nil {
+		t.Fatalf("write credentials: %v", err)
+	}
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--json", "auth", "adc", "set", "User@Corp.com", "--credentials", credsPath, "--impersonate", "bot@p.iam.gserviceaccount.com", "--delegate", "hop@p.iam.gserviceaccount.com"}); err != nil {
+			t.Fatalf("set: %v", err)
+		}
+	})
+	if !strings.Contains(out, `"type": "external_account"`) {
+		t.Fatalf("unexpected set output: %s", out)
+	}
+
+	acct, ok, err := config.ReadADCAccount("user@corp.com")
+	if err != nil || !ok || acct.CredentialsFile != credsPath || acct.Impersonate != "bot@p.iam.gserviceaccount.com" {
+		t.Fatalf("unexpected stored account: %#v ok=%v err=%v", acct, ok, err)
+	}
+
+	out = captureStdout(t, func() {
+		if err := Execute([]string{"--json", "auth", "adc", "status", "user@corp.com"}); err != nil {
+			t.Fatalf("status: %v", err)
+		}
+	})
+	var status map[string]any
+	if err := json.Unmarshal([]byte(out), &status); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if status["configured"] != true || status["source"] != "file" || status["type"] != "external_account" {
+		t.Fatalf("unexpected status: %s", out)
+	}
+
+	out = captureStdout(t, func() {
+		if err := Execute([]string{"--json", "--account", "user@corp.com", "auth", "status"}); err != nil {
+			t.Fatalf("auth status: %v", err)
+		}
+	})
+	if !strings.Contains(out, `"auth_preferred": "adc"`) {
+		t.Fatalf("expected adc preferred in auth status: %s", out)
+	}
+
+	_ = captureStdout(t, func() {
+		if err := Execute([]string{"auth", "adc", "unset", "user@corp.com"}); err != nil {
+			t.Fatalf("unset: %v", err)
+		}
+	})
+	if _, ok, _ := config.ReadADCAccount("user@corp.com"); ok {
+		t.Fatalf("expected entry removed")
+	}
+}
+
+func TestExecute_AuthADC_SetValidation(t *testing.T) {
+	useTestHome(t)
+
+	keyPath := filepath.Join(t.TempDir(), "client.json")
+	if err := os.WriteFile(keyPath, []byte(`{"installed":{"client_id":"x"}}`), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	cases := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"auth", "adc", "set", "a@b.com", "--impersonate", "someone@b.com"}, "must be a service account"},
+		{[]string{"auth", "adc", "set", "a@b.com", "--delegate", "hop@p.iam.gserviceaccount.com"}, "--delegate requires --impersonate"},
+		{[]string{"auth", "adc", "set", "a@b.com", "--credentials", keyPath}, "unsupported credentials type"},
+	}
+	for _, tc := range cases {
+		var err error
+		_ = captureStderr(t, func() {
+			err = Execute(tc.args)
+		})
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%v: expected %q, got %v", tc.args, tc.want, err)
+		}
+	}
+}
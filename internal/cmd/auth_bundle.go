@@ -0,0 +1,509 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/99designs/keyring"
+	"golang.org/x/term"
+
+	"github.com/steipete/gogcli/internal/bundle"
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/errfmt"
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/secrets"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+const bundlePassphraseEnv = "GOG_BUNDLE_PASSPHRASE" //nolint:gosec // env var name, not a credential
+
+const (
+	bundleImported  = "imported"
+	bundleReplaced  = "replaced"
+	bundleUnchanged = "unchanged"
+	bundleConflict  = "conflict"
+	bundleFailed    = "failed"
+)
+
+var readPassphrase = func() ([]byte, error) { return term.ReadPassword(int(os.Stdin.Fd())) }
+
+type AuthBundleCmd struct {
+	Export AuthBundleExportCmd `cmd:"" name:"export" help:"Export all tokens, OAuth clients, service account keys, account mappings and secrets to an encrypted bundle"`
+	Import AuthBundleImportCmd `cmd:"" name:"import" help:"Merge an encrypted bundle into this machine's config and keyring"`
+}
+
+type AuthBundleExportCmd struct {
+	Output         OutputPathRequiredFlag `embed:""`
+	Overwrite      bool                   `name:"overwrite" help:"Overwrite output file if it exists"`
+	PassphraseFile string                 `name:"passphrase-file" help:"Read the passphrase from a file (default: $$GOG_BUNDLE_PASSPHRASE or prompt)"`
+}
+
+func (c *AuthBundleExportCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	outPath := strings.TrimSpace(c.Output.Path)
+	if outPath == "" {
+		return usage("empty outPath")
+	}
+	outPath, err := config.ExpandPath(outPath)
+	if err != nil {
+		return err
+	}
+
+	passphrase, err := bundlePassphrase(ctx, flags, c.PassphraseFile, true)
+	if err != nil {
+		return err
+	}
+
+	b, err := collectBundle()
+	if err != nil {
+		return err
+	}
+	data, err := bundle.Seal(b, passphrase)
+	if err != nil {
+		return err
+	}
+
+	if mkErr := os.MkdirAll(filepath.Dir(outPath), 0o700); mkErr != nil {
+		return mkErr
+	}
+	openFlags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !c.Overwrite {
+		openFlags = os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	}
+	f, err := os.OpenFile(outPath, openFlags, 0o600) //nolint:gosec // user-provided path
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
+			"exported":         true,
+			"path":             outPath,
+			"tokens":           len(b.Tokens),
+			"clients":          len(b.Clients),
+			"service_accounts": len(b.ServiceAccounts),
+			"secrets":          len(b.Secrets),
+		})
+	}
+	u.Out().Printf("exported\ttrue")
+	u.Out().Printf("path\t%s", outPath)
+	u.Out().Printf("tokens\t%d", len(b.Tokens))
+	u.Out().Printf("clients\t%d", len(b.Clients))
+	u.Out().Printf("service_accounts\t%d", len(b.ServiceAccounts))
+	u.Out().Printf("secrets\t%d", len(b.Secrets))
+	return nil
+}
+
+type AuthBundleImportCmd struct {
+	InPath         string `arg:"" name:"inPath" help:"Bundle path or '-' for stdin"`
+	Overwrite      bool   `name:"overwrite" help:"Replace existing entries that differ from the bundle"`
+	PassphraseFile string `name:"passphrase-file" help:"Read the passphrase from a file (default: $$GOG_BUNDLE_PASSPHRASE or prompt)"`
+}
+
+type bundleResult struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+func (c *AuthBundleImportCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	if flags.DryRun {
+		return usage("auth bundle import does not support --dry-run")
+	}
+
+	inPath := strings.TrimSpace(c.InPath)
+	var (
+		data []byte
+		err  error
+	)
+	if inPath == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		inPath, err = config.ExpandPath(inPath)
+		if err != nil {
+			return err
+		}
+		data, err = os.ReadFile(inPath) //nolint:gosec // user-provided path
+	}
+	if err != nil {
+		return fmt.Errorf("read bundle: %w", err)
+	}
+
+	passphrase, err := bundlePassphrase(ctx, flags, c.PassphraseFile, false)
+	if err != nil {
+		return err
+	}
+	b, err := bundle.Open(data, passphrase)
+	if err != nil {
+		return err
+	}
+
+	if keychainErr := ensureKeychainAccessIfNeeded(); keychainErr != nil {
+		return fmt.Errorf("keychain access: %w", keychainErr)
+	}
+	store, err := openSecretsStore()
+	if err != nil {
+		return err
+	}
+
+	results, err := applyBundle(b, store, c.Overwrite)
+	if err != nil {
+		return err
+	}
+
+	counts := map[string]int{}
+	for _, r := range results {
+		counts[r.Status]++
+	}
+
+	if outfmt.IsJSON(ctx) {
+		if err := outfmt.Write(ctx, os.Stdout, map[string]any{
+			"results":   results,
+			"imported":  counts[bundleImported] + counts[bundleReplaced],
+			"unchanged": counts[bundleUnchanged],
+			"conflicts": counts[bundleConflict],
+			"failed":    counts[bundleFailed],
+		}); err != nil {
+			return err
+		}
+	} else if len(results) == 0 {
+		u.Err().Println("Bundle is empty")
+	} else {
+		w, flush := tableWriter(ctx)
+		fmt.Fprintln(w, "KIND\tNAME\tSTATUS\tERROR")
+		for _, r := range results {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Kind, sanitizeTab(r.Name), r.Status, sanitizeTab(r.Error))
+		}
+		flush()
+	}
+
+	if n := counts[bundleConflict] + counts[bundleFailed]; n > 0 {
+		msg := fmt.Sprintf("%d of %d entries not imported", n, len(results))
+		if counts[bundleConflict] > 0 && !c.Overwrite {
+			msg += " (use --overwrite to replace conflicting entries)"
+		}
+		return &ExitError{Code: errfmt.ExitError, Err: errors.New(msg)}
+	}
+	return nil
+}
+
+// bundlePassphrase reads the passphrase from --passphrase-file,
+// GOG_BUNDLE_PASSPHRASE or the terminal (twice when confirm is set).
+func bundlePassphrase(ctx context.Context, flags *RootFlags, file string, confirm bool) (string, error) {
+	if file = strings.TrimSpace(file); file != "" {
+		path, err := config.ExpandPath(file)
+		if err != nil {
+			return "", err
+		}
+		b, err := os.ReadFile(path) //nolint:gosec // user-provided path
+		if err != nil {
+			return "", fmt.Errorf("read passphrase file: %w", err)
+		}
+		return strings.TrimRight(string(b), "\r\n"), nil
+	}
+	if v := os.Getenv(bundlePassphraseEnv); v != "" {
+		return v, nil
+	}
+	if flags.NoInput || !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", usagef("no bundle passphrase: set %s or use --passphrase-file (non-interactive)", bundlePassphraseEnv)
+	}
+
+	u := ui.FromContext(ctx)
+	prompt := func(label string) (string, error) {
+		u.Err().Print(label)
+		b, err := readPassphrase()
+		u.Err().Println("")
+		if err != nil {
+			return "", fmt.Errorf("read passphrase: %w", err)
+		}
+		return string(b), nil
+	}
+	passphrase, err := prompt("Bundle passphrase: ")
+	if err != nil || !confirm {
+		return passphrase, err
+	}
+	again, err := prompt("Repeat passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if again != passphrase {
+		return "", usage("passphrases do not match")
+	}
+	return passphrase, nil
+}
+
+// collectBundle gathers everything a new machine needs: tokens and default
+// accounts for every client, OAuth clients, service account keys, account
+// mappings from config.json and other keyring secrets (tracking keys).
+func collectBundle() (bundle.Bundle, error) {
+	b := bundle.Bundle{CreatedAt: time.Now().UTC()}
+
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		return b, err
+	}
+	b.AccountAliases = cfg.AccountAliases
+	b.AccountClients = cfg.AccountClients
+	b.ClientDomains = cfg.ClientDomains
+
+	clients := map[string]bool{}
+	infos, err := config.ListClientCredentials()
+	if err != nil {
+		return b, err
+	}
+	for _, info := range infos {
+		creds, err := config.ReadClientCredentialsFor(info.Client)
+		if err != nil {
+			return b, fmt.Errorf("read credentials for client %s: %w", info.Client, err)
+		}
+		clients[info.Client] = true
+		b.Clients = append(b.Clients, bundle.Client{Client: info.Client, ClientID: creds.ClientID, ClientSecret: creds.ClientSecret})
+	}
+
+	emails, err := config.ListServiceAccountEmails()
+	if err != nil {
+		return b, err
+	}
+	for _, email := range emails {
+		path, _, ok := bestServiceAccountPathAndMtime(normalizeEmail(email))
+		if !ok {
+			continue
+		}
+		key, err := os.ReadFile(path) //nolint:gosec // path from config dir
+		if err != nil {
+			return b, fmt.Errorf("read service account key for %s: %w", email, err)
+		}
+		if !json.Valid(key) {
+			return b, fmt.Errorf("service account key for %s is not valid JSON", email)
+		}
+		b.ServiceAccounts = append(b.ServiceAccounts, bundle.ServiceAccount{Email: normalizeEmail(email), Key: bytes.TrimSpace(key)})
+	}
+
+	store, err := openSecretsStore()
+	if err != nil {
+		return b, err
+	}
+	tokens, err := store.ListTokens()
+	if err != nil {
+		return b, err
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		return secrets.TokenKey(tokens[i].Client, tokens[i].Email) < secrets.TokenKey(tokens[j].Client, tokens[j].Email)
+	})
+	for _, tok := range tokens {
+		clients[tok.Client] = true
+		b.Tokens = append(b.Tokens, bundle.Token{
+			Client:       tok.Client,
+			Email:        tok.Email,
+			Services:     tok.Services,
+			Scopes:       tok.Scopes,
+			CreatedAt:    tok.CreatedAt,
+			RefreshToken: tok.RefreshToken,
+		})
+	}
+	for client := range clients {
+		email, err := store.GetDefaultAccount(client)
+		if err != nil {
+			return b, err
+		}
+		if email != "" {
+			if b.DefaultAccounts == nil {
+				b.DefaultAccounts = map[string]string{}
+			}
+			b.DefaultAccounts[client] = email
+		}
+	}
+
+	keyNames, err := store.Keys()
+	if err != nil {
+		return b, err
+	}
+	keys := bundleSecretsOf(store)
+	for _, key := range keyNames {
+		// The token cache key only decrypts this machine's cached access tokens.
+		if _, _, ok := secrets.ParseTokenKey(key); ok || strings.HasPrefix(key, "default_account") || key == googleapi.TokenCacheSecretKey {
+			continue
+		}
+		value, err := keys.GetSecret(key)
+		if err != nil {
+			return b, fmt.Errorf("read secret %s: %w", key, err)
+		}
+		if b.Secrets == nil {
+			b.Secrets = map[string][]byte{}
+		}
+		b.Secrets[key] = value
+	}
+
+	return b, nil
+}
+
+// bundleSecrets is the part of secrets.KeyringStore that reads and writes
+// plain keyring secrets.
+type bundleSecrets interface {
+	GetSecret(key string) ([]byte, error)
+	SetSecret(key string, value []byte) error
+}
+
+// keyringBundleSecrets opens the keyring per call; used when the store
+// cannot hold secrets.
+type keyringBundleSecrets struct{}
+
+func (keyringBundleSecrets) GetSecret(key string) ([]byte, error) { return secrets.GetSecret(key) }
+func (keyringBundleSecrets) SetSecret(key string, value []byte) error {
+	return secrets.SetSecret(key, value)
+}
+
+// bundleSecretsOf goes through the already opened store when it can, so a
+// file keyring asks for its password once instead of once per secret.
+func bundleSecretsOf(store secrets.Store) bundleSecrets {
+	if s, ok := store.(bundleSecrets); ok {
+		return s
+	}
+	return keyringBundleSecrets{}
+}
+
+// applyBundle merges b into config and keyring. Entries that already exist
+// with a different value are conflicts unless overwrite is set.
+func applyBundle(b bundle.Bundle, store secrets.Store, overwrite bool) ([]bundleResult, error) {
+	var out []bundleResult
+	add := func(kind, name string, existed, same bool, write func() error) {
+		r := bundleResult{Kind: kind, Name: name}
+		switch {
+		case existed && same:
+			r.Status = bundleUnchanged
+		case existed && !overwrite:
+			r.Status = bundleConflict
+		default:
+			if err := write(); err != nil {
+				r.Status, r.Error = bundleFailed, err.Error()
+				break
+			}
+			r.Status = bundleImported
+			if existed {
+				r.Status = bundleReplaced
+			}
+		}
+		out = append(out, r)
+	}
+
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		return nil, err
+	}
+	cfgChanged := false
+	mergeMap := func(kind string, dst *map[string]string, src map[string]string) {
+		for _, k := range sortedKeys(src) {
+			cur, existed := (*dst)[k]
+			add(kind, k, existed, cur == src[k], func() error {
+				if *dst == nil {
+					*dst = map[string]string{}
+				}
+				(*dst)[k] = src[k]
+				cfgChanged = true
+				return nil
+			})
+		}
+	}
+	mergeMap("account_alias", &cfg.AccountAliases, b.AccountAliases)
+	mergeMap("account_client", &cfg.AccountClients, b.AccountClients)
+	mergeMap("client_domain", &cfg.ClientDomains, b.ClientDomains)
+	if cfgChanged {
+		if err := config.WriteConfig(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, cl := range b.Clients {
+		creds := config.ClientCredentials{ClientID: cl.ClientID, ClientSecret: cl.ClientSecret}
+		cur, err := config.ReadClientCredentialsFor(cl.Client)
+		add("client", cl.Client, err == nil, cur == creds, func() error {
+			return config.WriteClientCredentialsFor(cl.Client, creds)
+		})
+	}
+
+	for _, sa := range b.ServiceAccounts {
+		path, err := config.ServiceAccountPath(sa.Email)
+		if err != nil {
+			return nil, err
+		}
+		cur, readErr := os.ReadFile(path) //nolint:gosec // path from config dir
+		add("service_account", sa.Email, readErr == nil, bytes.Equal(bytes.TrimSpace(cur), bytes.TrimSpace(sa.Key)), func() error {
+			if _, err := parseServiceAccountJSON(sa.Key); err != nil {
+				return err
+			}
+			if _, err := config.EnsureDir(); err != nil {
+				return err
+			}
+			return os.WriteFile(path, sa.Key, 0o600)
+		})
+	}
+
+	for _, tok := range b.Tokens {
+		cur, err := store.GetToken(tok.Client, tok.Email)
+		if err != nil && !errors.Is(err, keyring.ErrKeyNotFound) {
+			out = append(out, bundleResult{Kind: "token", Name: secrets.TokenKey(tok.Client, tok.Email), Status: bundleFailed, Error: err.Error()})
+			continue
+		}
+		add("token", secrets.TokenKey(tok.Client, tok.Email), err == nil, cur.RefreshToken == tok.RefreshToken, func() error {
+			return store.SetToken(tok.Client, tok.Email, secrets.Token{
+				Client:       tok.Client,
+				Email:        tok.Email,
+				Services:     tok.Services,
+				Scopes:       tok.Scopes,
+				CreatedAt:    tok.CreatedAt,
+				RefreshToken: tok.RefreshToken,
+			})
+		})
+	}
+
+	for _, client := range sortedKeys(b.DefaultAccounts) {
+		email := b.DefaultAccounts[client]
+		cur, err := store.GetDefaultAccount(client)
+		if err != nil {
+			out = append(out, bundleResult{Kind: "default_account", Name: client, Status: bundleFailed, Error: err.Error()})
+			continue
+		}
+		add("default_account", client, cur != "", strings.EqualFold(cur, email), func() error {
+			return store.SetDefaultAccount(client, email)
+		})
+	}
+
+	secretKeys := make([]string, 0, len(b.Secrets))
+	for k := range b.Secrets {
+		secretKeys = append(secretKeys, k)
+	}
+	sort.Strings(secretKeys)
+	keys := bundleSecretsOf(store)
+	for _, key := range secretKeys {
+		value := b.Secrets[key]
+		cur, err := keys.GetSecret(key)
+		if err != nil && !errors.Is(err, keyring.ErrKeyNotFound) {
+			out = append(out, bundleResult{Kind: "secret", Name: key, Status: bundleFailed, Error: err.Error()})
+			continue
+		}
+		add("secret", key, err == nil, bytes.Equal(cur, value), func() error {
+			return keys.SetSecret(key, value)
+		})
+	}
+
+	return out, nil
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/99designs/keyring"
+
+	"github.com/steipete/gogcli/internal/bundle"
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/secrets"
+)
+
+func useTestHome(t *testing.T) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg"))
+}
+
+func TestExecute_AuthBundle_ExportImport(t *testing.T) {
+	t.Setenv("GOG_KEYRING_BACKEND", "file")
+	t.Setenv("GOG_KEYRING_PASSWORD", "testpass")
+	t.Setenv("GOG_BUNDLE_PASSPHRASE", "bundle passphrase")
+	t.Setenv("GOG_ACCOUNT", "")
+
+	// Old machine.
+	useTestHome(t)
+	store, err := openSecretsStore()
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	if err := store.SetToken("default", "a@b.com", secrets.Token{Email: "a@b.com", Services: []string{"gmail"}, RefreshToken: "1//a"}); err != nil {
+		t.Fatalf("SetToken: %v", err)
+	}
+	if err := store.SetDefaultAccount("default", "a@b.com"); err != nil {
+		t.Fatalf("SetDefaultAccount: %v", err)
+	}
+	if err := secrets.SetSecret("tracking/a@b.com/tracking_key", []byte("tk")); err != nil {
+		t.Fatalf("SetSecret: %v", err)
+	}
+	if err := config.WriteClientCredentialsFor("default", config.ClientCredentials{ClientID: "id", ClientSecret: "cs"}); err != nil {
+		t.Fatalf("write credentials: %v", err)
+	}
+	if err := config.WriteConfig(config.File{AccountAliases: map[string]string{"work": "a@b.com"}}); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	saPath, _ := config.ServiceAccountPath("w@corp.com")
+	if err := os.WriteFile(saPath, []byte(`{"type":"service_account","client_email":"sa@p.iam.gserviceaccount.com"}`), 0o600); err != nil {
+		t.Fatalf("write service account: %v", err)
+	}
+
+	bundlePath := filepath.Join(t.TempDir(), "me.gogbundle")
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--json", "auth", "bundle", "export", "--out", bundlePath}); err != nil {
+			t.Fatalf("export: %v", err)
+		}
+	})
+	if !strings.Contains(out, `"tokens": 1`) || !strings.Contains(out, `"secrets": 1`) {
+		t.Fatalf("unexpected export output: %s", out)
+	}
+	raw, _ := os.ReadFile(bundlePath)
+	if strings.Contains(string(raw), "1//a") || strings.Contains(string(raw), "a@b.com") {
+		t.Fatalf("bundle is not encrypted: %s", raw)
+	}
+
+	// New machine with a conflicting alias.
+	useTestHome(t)
+	if err := config.WriteConfig(config.File{AccountAliases: map[string]string{"work": "other@b.com"}}); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	var runErr error
+	out = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			runErr = Execute([]string{"--json", "auth", "bundle", "import", bundlePath})
+		})
+	})
+	if runErr == nil || !strings.Contains(runErr.Error(), "--overwrite") {
+		t.Fatalf("expected conflict error, got %v", runErr)
+	}
+	var report struct {
+		Results   []bundleResult `json:"results"`
+		Imported  int            `json:"imported"`
+		Conflicts int            `json:"conflicts"`
+	}
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if report.Imported != 5 || report.Conflicts != 1 {
+		t.Fatalf("unexpected import report: %s", out)
+	}
+
+	store, err = openSecretsStore()
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	if tok, err := store.GetToken("default", "a@b.com"); err != nil || tok.RefreshToken != "1//a" {
+		t.Fatalf("token not imported: %+v %v", tok, err)
+	}
+	if v, err := secrets.GetSecret("tracking/a@b.com/tracking_key"); err != nil || string(v) != "tk" {
+		t.Fatalf("secret not imported: %q %v", v, err)
+	}
+	if creds, err := config.ReadClientCredentialsFor("default"); err != nil || creds.ClientSecret != "cs" {
+		t.Fatalf("credentials not imported: %+v %v", creds, err)
+	}
+	if cfg, _ := config.ReadConfig(); cfg.AccountAliases["work"] != "other@b.com" {
+		t.Fatalf("conflicting alias overwritten: %+v", cfg.AccountAliases)
+	}
+
+	out = captureStdout(t, func() {
+		if err := Execute([]string{"--json", "auth", "bundle", "import", bundlePath, "--overwrite"}); err != nil {
+			t.Fatalf("import --overwrite: %v", err)
+		}
+	})
+	if !strings.Contains(out, `"imported": 1`) || !strings.Contains(out, `"unchanged": 5`) {
+		t.Fatalf("unexpected overwrite report: %s", out)
+	}
+	if cfg, _ := config.ReadConfig(); cfg.AccountAliases["work"] != "a@b.com" {
+		t.Fatalf("alias not replaced: %+v", cfg.AccountAliases)
+	}
+
+	t.Setenv("GOG_BUNDLE_PASSPHRASE", "wrong passphrase")
+	_ = captureStderr(t, func() {
+		runErr = Execute([]string{"auth", "bundle", "import", bundlePath})
+	})
+	if runErr == nil || !strings.Contains(runErr.Error(), "wrong passphrase") {
+		t.Fatalf("expected wrong passphrase error, got %v", runErr)
+	}
+}
+
+// secretMemStore adds plain secrets to memSecretsStore, like
+// secrets.KeyringStore.
+type secretMemStore struct {
+	*memSecretsStore
+	secrets map[string][]byte
+}
+
+func (s secretMemStore) GetSecret(key string) ([]byte, error) {
+	v, ok := s.secrets[key]
+	if !ok {
+		return nil, keyring.ErrKeyNotFound
+	}
+	return v, nil
+}
+
+func (s secretMemStore) SetSecret(key string, value []byte) error {
+	s.secrets[key] = value
+	return nil
+}
+
+func TestApplyBundle_SecretsThroughOpenStore(t *testing.T) {
+	useTestHome(t)
+
+	store := secretMemStore{memSecretsStore: newMemSecretsStore(), secrets: map[string][]byte{"same": []byte("v")}}
+	results, err := applyBundle(bundle.Bundle{Secrets: map[string][]byte{"same": []byte("v"), "new": []byte("n")}}, store, false)
+	if err != nil {
+		t.Fatalf("applyBundle: %v", err)
+	}
+
+	got := map[string]string{}
+	for _, r := range results {
+		got[r.Name] = r.Status
+	}
+	if got["same"] != bundleUnchanged || got["new"] != bundleImported || string(store.secrets["new"]) != "n" {
+		t.Fatalf("unexpected results %+v, secrets %q", results, store.secrets)
+	}
+}
@@ -2,23 +2,35 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"strings"
 
 	"golang.org/x/term"
 
 	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/errfmt"
 	"github.com/steipete/gogcli/internal/outfmt"
 	"github.com/steipete/gogcli/internal/secrets"
 	"github.com/steipete/gogcli/internal/ui"
 )
 
+var (
+	openKeyringBackend = secrets.OpenBackend
+	migrateKeyring     = secrets.Migrate
+)
+
 type AuthKeyringCmd struct {
+	Backend AuthKeyringBackendCmd `cmd:"" name:"backend" default:"withargs" help:"Show or set the keyring backend"`
+	Migrate AuthKeyringMigrateCmd `cmd:"" name:"migrate" help:"Copy stored tokens and secrets to another keyring backend"`
+}
+
+type AuthKeyringBackendCmd struct {
 	Backend  string `arg:"" optional:"" name:"backend" help:"Keyring backend: auto|keychain|file"`
 	Backend2 string `arg:"" optional:"" name:"backend2" help:"(compat) Use: gog auth keyring set <backend>"`
 }
 
-func (c *AuthKeyringCmd) Run(ctx context.Context) error {
+func (c *AuthKeyringBackendCmd) Run(ctx context.Context) error {
 	u := ui.FromContext(ctx)
 
 	const keyringPasswordEnv = "GOG_KEYRING_PASSWORD" //nolint:gosec // env var name, not a credential
@@ -41,7 +53,7 @@ func (c *AuthKeyringCmd) Run(ctx context.Context) error {
 		}
 
 		if outfmt.IsJSON(ctx) {
-			return outfmt.WriteJSON(os.Stdout, map[string]any{
+			return outfmt.Write(ctx, os.Stdout, map[string]any{
 				"keyring_backend": info.Value,
 				"source":          info.Source,
 				"path":            path,
@@ -108,7 +120,7 @@ func (c *AuthKeyringCmd) Run(ctx context.Context) error {
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"written":         true,
 			"path":            path,
 			"keyring_backend": backend,
@@ -124,3 +136,127 @@ func (c *AuthKeyringCmd) Run(ctx context.Context) error {
 	u.Out().Printf("keyring_backend\t%s", backend)
 	return nil
 }
+
+type AuthKeyringMigrateCmd struct {
+	To           string `name:"to" required:"" help:"Destination backend: auto|keychain|file"`
+	From         string `name:"from" help:"Source backend: auto|keychain|file (default: the current backend)"`
+	Overwrite    bool   `name:"overwrite" help:"Replace destination keys that hold a different value"`
+	DeleteSource bool   `name:"delete-source" help:"Delete each key from the source once its copy is verified"`
+}
+
+func (c *AuthKeyringMigrateCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+
+	to, err := parseKeyringBackendArg("--to", c.To)
+	if err != nil {
+		return err
+	}
+	from := strings.TrimSpace(c.From)
+	if from == "" {
+		info, resolveErr := secrets.ResolveKeyringBackendInfo()
+		if resolveErr != nil {
+			return resolveErr
+		}
+		from = info.Value
+	} else if from, err = parseKeyringBackendArg("--from", from); err != nil {
+		return err
+	}
+	if from == to {
+		return usagef("--from and --to are both %q", to)
+	}
+	// "auto" opens the file backend on headless Linux and the keychain on
+	// macOS; migrating onto itself with --delete-source would wipe it.
+	if eff := secrets.EffectiveKeyringBackend(from); eff == secrets.EffectiveKeyringBackend(to) {
+		return usagef("--from %q and --to %q are the same keyring (%s) here", from, to, eff)
+	}
+	if flags.DryRun {
+		return usage("auth keyring migrate does not support --dry-run")
+	}
+	if c.DeleteSource {
+		if err := confirmDestructive(ctx, flags, fmt.Sprintf("delete migrated keys from the %s keyring", from)); err != nil {
+			return err
+		}
+	}
+
+	src, err := openKeyringBackend(from)
+	if err != nil {
+		return fmt.Errorf("open %s keyring: %w", from, err)
+	}
+	dst, err := openKeyringBackend(to)
+	if err != nil {
+		return fmt.Errorf("open %s keyring: %w", to, err)
+	}
+
+	results, err := migrateKeyring(src, dst, secrets.MigrateOptions{Overwrite: c.Overwrite, DeleteSource: c.DeleteSource})
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, r := range results {
+		if r.Status == secrets.MigrateConflict || r.Status == secrets.MigrateFailed || r.Error != "" {
+			failed++
+		}
+	}
+
+	// Point gog at the new backend once everything made it across.
+	switched := false
+	if failed == 0 {
+		cfg, err := config.ReadConfig()
+		if err != nil {
+			return err
+		}
+		cfg.KeyringBackend = to
+		if err := config.WriteConfig(cfg); err != nil {
+			return err
+		}
+		switched = true
+	}
+
+	if outfmt.IsJSON(ctx) {
+		if results == nil {
+			results = []secrets.MigrateResult{}
+		}
+		if err := outfmt.Write(ctx, os.Stdout, map[string]any{
+			"from":     from,
+			"to":       to,
+			"results":  results,
+			"failed":   failed,
+			"switched": switched,
+		}); err != nil {
+			return err
+		}
+	} else {
+		if len(results) == 0 {
+			u.Err().Printf("No keys in the %s keyring", from)
+		} else {
+			w, flush := tableWriter(ctx)
+			fmt.Fprintln(w, "KEY\tSTATUS\tDELETED\tERROR")
+			for _, r := range results {
+				fmt.Fprintf(w, "%s\t%s\t%t\t%s\n", sanitizeTab(r.Key), r.Status, r.Deleted, sanitizeTab(r.Error))
+			}
+			flush()
+		}
+		if switched {
+			u.Err().Printf("keyring_backend set to %s in config.json", to)
+			if v := strings.TrimSpace(os.Getenv("GOG_KEYRING_BACKEND")); v != "" {
+				u.Err().Printf("NOTE: GOG_KEYRING_BACKEND=%s overrides config.json", v)
+			}
+		}
+	}
+
+	if failed > 0 {
+		return &ExitError{Code: errfmt.ExitError, Err: fmt.Errorf("%d of %d keys not migrated; keyring_backend left unchanged", failed, len(results))}
+	}
+	return nil
+}
+
+func parseKeyringBackendArg(flag, value string) (string, error) {
+	backend := strings.ToLower(strings.TrimSpace(value))
+	switch backend {
+	case "auto", "keychain", strFile:
+		return backend, nil
+	case "default":
+		return "auto", nil
+	}
+	return "", usagef("invalid %s %q (expected auto, keychain, or file)", flag, value)
+}
@@ -6,6 +6,8 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/steipete/gogcli/internal/config"
@@ -136,3 +138,84 @@ func TestAuthKeyringSet_InvalidBackend(t *testing.T) {
 		t.Fatalf("expected usage exit 2, got: %v", err)
 	}
 }
+
+func TestAuthKeyringMigrate(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg-config"))
+	t.Setenv("GOG_KEYRING_BACKEND", "")
+
+	origOpen, origMigrate := openKeyringBackend, migrateKeyring
+	t.Cleanup(func() { openKeyringBackend, migrateKeyring = origOpen, origMigrate })
+
+	var opened []string
+	openKeyringBackend = func(backend string) (*secrets.KeyringStore, error) {
+		opened = append(opened, backend)
+		return &secrets.KeyringStore{}, nil
+	}
+	results := []secrets.MigrateResult{
+		{Key: "default_account", Status: secrets.MigrateCopied},
+		{Key: "token:default:a@b.com", Status: secrets.MigrateCopied},
+	}
+	migrateKeyring = func(_, _ *secrets.KeyringStore, opts secrets.MigrateOptions) ([]secrets.MigrateResult, error) {
+		if !opts.Overwrite || opts.DeleteSource {
+			t.Fatalf("unexpected options %+v", opts)
+		}
+		return results, nil
+	}
+
+	ctx := outfmt.WithMode(context.Background(), outfmt.Mode{JSON: true})
+	out := captureStdout(t, func() {
+		if err := runKong(t, &AuthKeyringCmd{}, []string{"migrate", "--from", "file", "--to", "keychain", "--overwrite"}, ctx, &RootFlags{}); err != nil {
+			t.Fatalf("run: %v", err)
+		}
+	})
+	if len(opened) != 2 || opened[0] != "file" || opened[1] != "keychain" {
+		t.Fatalf("unexpected backends opened: %q", opened)
+	}
+	if !strings.Contains(out, `"token:default:a@b.com"`) || !strings.Contains(out, `"switched": true`) {
+		t.Fatalf("unexpected output: %s", out)
+	}
+	if cfg, err := config.ReadConfig(); err != nil || cfg.KeyringBackend != "keychain" {
+		t.Fatalf("expected keyring_backend=keychain, got %q (%v)", cfg.KeyringBackend, err)
+	}
+
+	results = append(results, secrets.MigrateResult{Key: "tracking_key", Status: secrets.MigrateConflict, Error: "destination holds a different value"})
+	var err error
+	_ = captureStdout(t, func() {
+		err = runKong(t, &AuthKeyringCmd{}, []string{"migrate", "--from", "keychain", "--to", "file", "--overwrite"}, ctx, &RootFlags{})
+	})
+	if err == nil || !strings.Contains(err.Error(), "1 of 3 keys not migrated") {
+		t.Fatalf("expected partial failure, got %v", err)
+	}
+	if cfg, _ := config.ReadConfig(); cfg.KeyringBackend != "keychain" {
+		t.Fatalf("keyring_backend switched despite failures: %q", cfg.KeyringBackend)
+	}
+
+	if err := runKong(t, &AuthKeyringCmd{}, []string{"migrate", "--to", "keychain"}, ctx, &RootFlags{}); err == nil || !strings.Contains(err.Error(), "both") {
+		t.Fatalf("expected same-backend usage error, got %v", err)
+	}
+}
+
+func TestAuthKeyringMigrate_AutoIsFileOnHeadlessLinux(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("auto only resolves to the file backend on Linux")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg-config"))
+	t.Setenv("GOG_KEYRING_BACKEND", "")
+	t.Setenv("DBUS_SESSION_BUS_ADDRESS", "")
+
+	origOpen := openKeyringBackend
+	t.Cleanup(func() { openKeyringBackend = origOpen })
+	openKeyringBackend = func(string) (*secrets.KeyringStore, error) {
+		t.Fatalf("keyring opened for a same-keyring migration")
+		return nil, nil
+	}
+
+	err := runKong(t, &AuthKeyringCmd{}, []string{"migrate", "--to", "file", "--delete-source"}, context.Background(), &RootFlags{Force: true})
+	if err == nil || !strings.Contains(err.Error(), "same keyring") {
+		t.Fatalf("expected same-keyring usage error, got %v", err)
+	}
+}
@@ -97,7 +97,7 @@ func (c *AuthServiceAccountSetCmd) Run(ctx context.Context) error {
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"stored":       true,
 			"email":        email,
 			"path":         destPath,
@@ -141,7 +141,7 @@ func (c *AuthServiceAccountUnsetCmd) Run(ctx context.Context, flags *RootFlags)
 	if err := os.Remove(path); err != nil {
 		if os.IsNotExist(err) {
 			if outfmt.IsJSON(ctx) {
-				return outfmt.WriteJSON(os.Stdout, map[string]any{
+				return outfmt.Write(ctx, os.Stdout, map[string]any{
 					"deleted": false,
 					"email":   email,
 					"path":    path,
@@ -156,7 +156,7 @@ func (c *AuthServiceAccountUnsetCmd) Run(ctx context.Context, flags *RootFlags)
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"deleted": true,
 			"email":   email,
 			"path":    path,
@@ -189,7 +189,7 @@ func (c *AuthServiceAccountStatusCmd) Run(ctx context.Context) error {
 	if err != nil {
 		if os.IsNotExist(err) {
 			if outfmt.IsJSON(ctx) {
-				return outfmt.WriteJSON(os.Stdout, map[string]any{
+				return outfmt.Write(ctx, os.Stdout, map[string]any{
 					"email":   email,
 					"path":    path,
 					"exists":  false,
@@ -211,7 +211,7 @@ func (c *AuthServiceAccountStatusCmd) Run(ctx context.Context) error {
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"email":        email,
 			"path":         path,
 			"exists":       true,
@@ -207,3 +207,35 @@ func (m *memStore) SetDefaultAccount(client string, email string) error {
 	m.defaultEmail = email
 	return nil
 }
+
+func TestAuthTokensFlush_JSON(t *testing.T) {
+	useTestHome(t)
+
+	dir, err := config.TokenCacheDir()
+	if err != nil {
+		t.Fatalf("TokenCacheDir: %v", err)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	for _, name := range []string{"a.bin", "b.bin"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0o600); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--json", "auth", "tokens", "flush"}); err != nil {
+			t.Fatalf("flush: %v", err)
+		}
+	})
+	var parsed struct {
+		Flushed int `json:"flushed"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil || parsed.Flushed != 2 {
+		t.Fatalf("unexpected output %q (%v)", out, err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Fatalf("expected empty cache dir, got %d entries", len(entries))
+	}
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/steipete/gogcli/internal/googleapi"
+)
+
+var (
+	newGmailBatch    = googleapi.NewGmailBatch
+	newDriveBatch    = googleapi.NewDriveBatch
+	newCalendarBatch = googleapi.NewCalendarBatch
+)
+
+// openBatch returns nil when no batch client can be built; callers then
+// fall back to one request per item. The batch client reuses the
+// authorized client of the command's service through the client cache of
+// ctx, so it adds no keyring access or token exchange.
+func openBatch(ctx context.Context, open func(context.Context, string) (*googleapi.BatchClient, error), account string) *googleapi.BatchClient {
+	b, err := open(ctx, account)
+	if err != nil {
+		slog.Debug("batch client unavailable, using single requests", "err", err)
+		return nil
+	}
+	return b
+}
+
+// batchGetJSON fetches each URL through b and decodes the replies in order.
+// errs[i] is set when the i-th request failed.
+func batchGetJSON[T any](ctx context.Context, b *googleapi.BatchClient, urls []string) ([]*T, []error) {
+	out := make([]*T, len(urls))
+	errs := make([]error, len(urls))
+
+	reqs := make([]*http.Request, len(urls))
+	for i, u := range urls {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			for j := range errs {
+				errs[j] = err
+			}
+			return out, errs
+		}
+		reqs[i] = req
+	}
+
+	for i, r := range b.Do(ctx, reqs) {
+		v := new(T)
+		if err := r.Decode(v); err != nil {
+			errs[i] = err
+			continue
+		}
+		out[i] = v
+	}
+	return out, errs
+}
+
+func batchURL(basePath string, path string, query url.Values) string {
+	u := strings.TrimSuffix(basePath, "/") + "/" + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return u
+}
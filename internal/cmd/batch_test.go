@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+
+	"github.com/steipete/gogcli/internal/googleapi"
+)
+
+// newBatchTestServer answers multipart batch calls on batchPath by routing
+// every sub-request through handler. Plain requests go to handler directly.
+func newBatchTestServer(t *testing.T, batchPath string, handler http.HandlerFunc) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var batches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != batchPath {
+			handler(w, r)
+			return
+		}
+		batches.Add(1)
+		_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var out bytes.Buffer
+		mw := multipart.NewWriter(&out)
+		mr := multipart.NewReader(r.Body, params["boundary"])
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			sub, err := http.ReadRequest(bufio.NewReader(part))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			rec := httptest.NewRecorder()
+			handler(rec, sub)
+
+			h := textproto.MIMEHeader{}
+			h.Set("Content-Type", "application/http")
+			h.Set("Content-ID", "<response-"+strings.Trim(part.Header.Get("Content-ID"), "<>")+">")
+			pw, _ := mw.CreatePart(h)
+			fmt.Fprintf(pw, "HTTP/1.1 %d %s\r\nContent-Type: application/json\r\n\r\n", rec.Code, http.StatusText(rec.Code))
+			_, _ = pw.Write(rec.Body.Bytes())
+		}
+		_ = mw.Close()
+
+		w.Header().Set("Content-Type", "multipart/mixed; boundary="+mw.Boundary())
+		_, _ = w.Write(out.Bytes())
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &batches
+}
+
+func TestFetchThreadDetails_Batch(t *testing.T) {
+	var singles atomic.Int32
+	srv, batches := newBatchTestServer(t, "/batch/gmail/v1", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/gmail/v1/users/me/threads/t1" && r.URL.Path != "/gmail/v1/users/me/threads/t2" {
+			singles.Add(1)
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("format") != "metadata" {
+			http.Error(w, "missing format", http.StatusBadRequest)
+			return
+		}
+		id := strings.TrimPrefix(r.URL.Path, "/gmail/v1/users/me/threads/")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id": id,
+			"messages": []map[string]any{{
+				"id":       "m_" + id,
+				"labelIds": []string{"INBOX"},
+				"payload": map[string]any{"headers": []map[string]string{
+					{"name": "From", "value": "a@example.com"},
+					{"name": "Subject", "value": "Subject " + id},
+					{"name": "Date", "value": "Mon, 01 Jan 2024 10:00:00 +0000"},
+				}},
+			}},
+		})
+	})
+
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	batch := &googleapi.BatchClient{Client: srv.Client(), Endpoint: srv.URL + "/batch/gmail/v1"}
+
+	items, err := fetchThreadDetails(context.Background(), svc, batch, []*gmail.Thread{{Id: "t1"}, {Id: "t2"}}, map[string]string{"INBOX": "Inbox"}, false, time.UTC)
+	if err != nil {
+		t.Fatalf("fetchThreadDetails: %v", err)
+	}
+	if batches.Load() != 1 || singles.Load() != 0 {
+		t.Fatalf("expected one batch call, got batches=%d singles=%d", batches.Load(), singles.Load())
+	}
+	if len(items) != 2 || items[0].ID != "t1" || items[1].Subject != "Subject t2" || items[0].Labels[0] != "Inbox" {
+		t.Fatalf("unexpected items: %#v", items)
+	}
+}
+
+func TestDriveGetCmd_MultipleIDsBatch(t *testing.T) {
+	origNew := newDriveService
+	origBatch := newDriveBatch
+	t.Cleanup(func() {
+		newDriveService = origNew
+		newDriveBatch = origBatch
+	})
+
+	srv, batches := newBatchTestServer(t, "/batch/drive/v3", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/files/")
+		if id == "missing" {
+			http.Error(w, `{"error":{"code":404,"message":"File not found"}}`, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": id, "name": "Name " + id, "mimeType": "text/plain"})
+	})
+
+	svc, err := drive.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newDriveService = func(context.Context, string) (*drive.Service, error) { return svc, nil }
+	newDriveBatch = func(context.Context, string) (*googleapi.BatchClient, error) {
+		return &googleapi.BatchClient{Client: srv.Client(), Endpoint: srv.URL + "/batch/drive/v3"}, nil
+	}
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", "a@b.com", "drive", "get", "f1", "f2"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	var parsed struct {
+		Files []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"files"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json parse: %v\nout=%q", err, out)
+	}
+	if len(parsed.Files) != 2 || parsed.Files[1].Name != "Name f2" {
+		t.Fatalf("unexpected files: %#v", parsed.Files)
+	}
+	if batches.Load() != 1 {
+		t.Fatalf("expected one batch call, got %d", batches.Load())
+	}
+
+	_ = captureStderr(t, func() {
+		err := Execute([]string{"--json", "--account", "a@b.com", "drive", "get", "f1", "missing"})
+		if err == nil || !strings.Contains(err.Error(), "file missing") {
+			t.Fatalf("expected missing file error, got %v", err)
+		}
+	})
+}
@@ -3,9 +3,13 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"os"
 	"strings"
 
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/steipete/gogcli/internal/googleapi"
 	"github.com/steipete/gogcli/internal/outfmt"
 	"github.com/steipete/gogcli/internal/ui"
 )
@@ -54,7 +58,7 @@ func (c *CalendarCalendarsCmd) Run(ctx context.Context, flags *RootFlags) error
 		return err
 	}
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"calendars":     resp.Items,
 			"nextPageToken": resp.NextPageToken,
 		})
@@ -101,7 +105,7 @@ func (c *CalendarAclCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"rules":         resp.Items,
 			"nextPageToken": resp.NextPageToken,
 		})
@@ -128,22 +132,23 @@ func (c *CalendarAclCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type CalendarEventsCmd struct {
-	CalendarID        string `arg:"" name:"calendarId" optional:"" help:"Calendar ID (default: primary)"`
-	From              string `name:"from" help:"Start time (RFC3339, date, or relative: today, tomorrow, monday)"`
-	To                string `name:"to" help:"End time (RFC3339, date, or relative)"`
-	Today             bool   `name:"today" help:"Today only (timezone-aware)"`
-	Tomorrow          bool   `name:"tomorrow" help:"Tomorrow only (timezone-aware)"`
-	Week              bool   `name:"week" help:"This week (uses --week-start, default Mon)"`
-	Days              int    `name:"days" help:"Next N days (timezone-aware)" default:"0"`
-	WeekStart         string `name:"week-start" help:"Week start day for --week (sun, mon, ...)" default:""`
-	Max               int64  `name:"max" aliases:"limit" help:"Max results" default:"10"`
-	Page              string `name:"page" help:"Page token"`
-	Query             string `name:"query" help:"Free text search"`
-	All               bool   `name:"all" help:"Fetch events from all calendars"`
-	PrivatePropFilter string `name:"private-prop-filter" help:"Filter by private extended property (key=value)"`
-	SharedPropFilter  string `name:"shared-prop-filter" help:"Filter by shared extended property (key=value)"`
-	Fields            string `name:"fields" help:"Comma-separated fields to return"`
-	Weekday           bool   `name:"weekday" help:"Include start/end day-of-week columns" default:"${calendar_weekday}"`
+	CalendarID        string      `arg:"" name:"calendarId" optional:"" help:"Calendar ID (default: primary)"`
+	From              string      `name:"from" help:"Start time (RFC3339, date, or relative: today, tomorrow, monday)"`
+	To                string      `name:"to" help:"End time (RFC3339, date, or relative)"`
+	Today             bool        `name:"today" help:"Today only (timezone-aware)"`
+	Tomorrow          bool        `name:"tomorrow" help:"Tomorrow only (timezone-aware)"`
+	Week              bool        `name:"week" help:"This week (uses --week-start, default Mon)"`
+	Days              int         `name:"days" help:"Next N days (timezone-aware)" default:"0"`
+	WeekStart         string      `name:"week-start" help:"Week start day for --week (sun, mon, ...)" default:""`
+	Max               int64       `name:"max" aliases:"limit" help:"Max results" default:"10"`
+	Page              string      `name:"page" help:"Page token"`
+	Paging            PagingFlags `embed:""`
+	Query             string      `name:"query" help:"Free text search"`
+	All               bool        `name:"all" help:"Fetch events from all calendars"`
+	PrivatePropFilter string      `name:"private-prop-filter" help:"Filter by private extended property (key=value)"`
+	SharedPropFilter  string      `name:"shared-prop-filter" help:"Filter by shared extended property (key=value)"`
+	Fields            string      `name:"fields" help:"Comma-separated fields to return"`
+	Weekday           bool        `name:"weekday" help:"Include start/end day-of-week columns" default:"${calendar_weekday}"`
 }
 
 func (c *CalendarEventsCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -156,6 +161,9 @@ func (c *CalendarEventsCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if c.All && calendarID != "" {
 		return usage("calendarId not allowed with --all flag")
 	}
+	if c.All && c.Paging.AllPages {
+		return usage("--all-pages is not supported with --all (page tokens are per calendar)")
+	}
 	if !c.All && calendarID == "" {
 		calendarID = "primary"
 	}
@@ -184,12 +192,12 @@ func (c *CalendarEventsCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if c.All {
 		return listAllCalendarsEvents(ctx, svc, from, to, c.Max, c.Page, c.Query, c.PrivatePropFilter, c.SharedPropFilter, c.Fields, c.Weekday)
 	}
-	return listCalendarEvents(ctx, svc, calendarID, from, to, c.Max, c.Page, c.Query, c.PrivatePropFilter, c.SharedPropFilter, c.Fields, c.Weekday)
+	return listCalendarEvents(ctx, svc, calendarID, from, to, c.Max, c.Page, c.Paging, c.Query, c.PrivatePropFilter, c.SharedPropFilter, c.Fields, c.Weekday)
 }
 
 type CalendarEventCmd struct {
-	CalendarID string `arg:"" name:"calendarId" help:"Calendar ID"`
-	EventID    string `arg:"" name:"eventId" help:"Event ID"`
+	CalendarID string   `arg:"" name:"calendarId" help:"Calendar ID"`
+	EventIDs   []string `arg:"" name:"eventId" help:"Event ID(s); several IDs are fetched in one batch request"`
 }
 
 func (c *CalendarEventCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -199,11 +207,16 @@ func (c *CalendarEventCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 	calendarID := strings.TrimSpace(c.CalendarID)
-	eventID := strings.TrimSpace(c.EventID)
+	eventIDs := make([]string, 0, len(c.EventIDs))
+	for _, id := range c.EventIDs {
+		if id = strings.TrimSpace(id); id != "" {
+			eventIDs = append(eventIDs, id)
+		}
+	}
 	if calendarID == "" {
 		return usage("empty calendarId")
 	}
-	if eventID == "" {
+	if len(eventIDs) == 0 {
 		return usage("empty eventId")
 	}
 
@@ -212,14 +225,64 @@ func (c *CalendarEventCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 
-	event, err := svc.Events.Get(calendarID, eventID).Do()
+	if len(eventIDs) > 1 {
+		events, err := getCalendarEvents(ctx, svc, openBatch(ctx, newCalendarBatch, account), calendarID, eventIDs)
+		if err != nil {
+			return err
+		}
+		tz, loc, _ := getCalendarLocation(ctx, svc, calendarID)
+		if outfmt.IsJSON(ctx) {
+			wrapped := make([]*eventWithDays, 0, len(events))
+			for _, event := range events {
+				wrapped = append(wrapped, wrapEventWithDaysWithTimezone(event, tz, loc))
+			}
+			return outfmt.Write(ctx, os.Stdout, map[string]any{"events": wrapped})
+		}
+		for i, event := range events {
+			if i > 0 {
+				u.Out().Println("")
+			}
+			printCalendarEventWithTimezone(u, event, tz, loc)
+		}
+		return nil
+	}
+
+	event, err := svc.Events.Get(calendarID, eventIDs[0]).Do()
 	if err != nil {
 		return err
 	}
 	tz, loc, _ := getCalendarLocation(ctx, svc, calendarID)
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"event": wrapEventWithDaysWithTimezone(event, tz, loc)})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"event": wrapEventWithDaysWithTimezone(event, tz, loc)})
 	}
 	printCalendarEventWithTimezone(u, event, tz, loc)
 	return nil
 }
+
+// getCalendarEvents fetches several events from one calendar, batched when possible.
+func getCalendarEvents(ctx context.Context, svc *calendar.Service, batch *googleapi.BatchClient, calendarID string, eventIDs []string) ([]*calendar.Event, error) {
+	events := make([]*calendar.Event, 0, len(eventIDs))
+	if batch == nil {
+		for _, id := range eventIDs {
+			event, err := svc.Events.Get(calendarID, id).Context(ctx).Do()
+			if err != nil {
+				return nil, fmt.Errorf("event %s: %w", id, err)
+			}
+			events = append(events, event)
+		}
+		return events, nil
+	}
+
+	urls := make([]string, 0, len(eventIDs))
+	for _, id := range eventIDs {
+		urls = append(urls, batchURL(svc.BasePath, "calendars/"+url.PathEscape(calendarID)+"/events/"+url.PathEscape(id), nil))
+	}
+	got, errs := batchGetJSON[calendar.Event](ctx, batch, urls)
+	for i, id := range eventIDs {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("event %s: %w", id, errs[i])
+		}
+		events = append(events, got[i])
+	}
+	return events, nil
+}
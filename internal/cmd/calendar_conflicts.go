@@ -80,7 +80,7 @@ func (c *CalendarConflictsCmd) Run(ctx context.Context, flags *RootFlags) error
 	conflicts := detectConflicts(resp.Calendars)
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"conflicts": conflicts,
 			"count":     len(conflicts),
 		})
@@ -102,6 +102,10 @@ func (c *CalendarCreateCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 	transparency = applyEventTypeTransparencyDefault(transparency, eventType)
 
+	if err := checkOutbound(ctx, flags, account, outboundAction{Action: "invite attendees", Recipients: splitCSV(c.Attendees)}); err != nil {
+		return err
+	}
+
 	svc, err := newCalendarService(ctx, account)
 	if err != nil {
 		return err
@@ -159,7 +163,7 @@ func (c *CalendarCreateCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 	tz, loc, _ := getCalendarLocation(ctx, svc, calendarID)
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"event": wrapEventWithDaysWithTimezone(created, tz, loc)})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"event": wrapEventWithDaysWithTimezone(created, tz, loc)})
 	}
 	printCalendarEventWithTimezone(u, created, tz, loc)
 	return nil
@@ -390,6 +394,14 @@ func (c *CalendarUpdateCmd) Run(ctx context.Context, kctx *kong.Context, flags *
 		return usage("no updates provided")
 	}
 
+	invited := c.AddAttendee
+	if flagProvided(kctx, "attendees") {
+		invited = c.Attendees
+	}
+	if err := checkOutbound(ctx, flags, account, outboundAction{Action: "invite attendees", Recipients: splitCSV(invited)}); err != nil {
+		return err
+	}
+
 	svc, err := newCalendarService(ctx, account)
 	if err != nil {
 		return err
@@ -414,6 +426,9 @@ func (c *CalendarUpdateCmd) Run(ctx context.Context, kctx *kong.Context, flags *
 		return err
 	}
 
+	// Snapshot for the undo journal; a failed read only skips journaling.
+	before, beforeErr := svc.Events.Get(calendarID, targetEventID).Context(ctx).Do()
+
 	updated, err := svc.Events.Patch(calendarID, targetEventID, patch).Do()
 	if err != nil {
 		return err
@@ -423,9 +438,17 @@ func (c *CalendarUpdateCmd) Run(ctx context.Context, kctx *kong.Context, flags *
 			return err
 		}
 	}
+	if beforeErr == nil && before != nil && before.Id != "" {
+		inv := undoCalendarEvent{CalendarID: calendarID, Snapshot: before}
+		if scope == scopeFuture {
+			inv.ParentID = eventID
+			inv.ParentRecurrence = parentRecurrence
+		}
+		recordUndo(flags, account, undoKindCalendarEvent, fmt.Sprintf("calendar update %s (scope %s)", targetEventID, scope), inv)
+	}
 	tz, loc, _ := getCalendarLocation(ctx, svc, calendarID)
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"event": wrapEventWithDaysWithTimezone(updated, tz, loc)})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"event": wrapEventWithDaysWithTimezone(updated, tz, loc)})
 	}
 	printCalendarEventWithTimezone(u, updated, tz, loc)
 	return nil
@@ -890,7 +913,7 @@ func (c *CalendarDeleteCmd) Run(ctx context.Context, flags *RootFlags) error {
 		}
 	}
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"deleted":    true,
 			"calendarId": calendarID,
 			"eventId":    targetEventID,
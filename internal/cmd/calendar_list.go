@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -13,40 +14,58 @@ import (
 	"github.com/steipete/gogcli/internal/ui"
 )
 
-func listCalendarEvents(ctx context.Context, svc *calendar.Service, calendarID, from, to string, maxResults int64, page, query, privatePropFilter, sharedPropFilter, fields string, showWeekday bool) error {
+func listCalendarEvents(ctx context.Context, svc *calendar.Service, calendarID, from, to string, maxResults int64, page string, paging PagingFlags, query, privatePropFilter, sharedPropFilter, fields string, showWeekday bool) error {
 	u := ui.FromContext(ctx)
 
-	call := svc.Events.List(calendarID).
-		TimeMin(from).
-		TimeMax(to).
-		MaxResults(maxResults).
-		PageToken(page).
-		SingleEvents(true).
-		OrderBy("startTime")
-	if strings.TrimSpace(query) != "" {
-		call = call.Q(query)
-	}
-	if strings.TrimSpace(privatePropFilter) != "" {
-		call = call.PrivateExtendedProperty(privatePropFilter)
-	}
-	if strings.TrimSpace(sharedPropFilter) != "" {
-		call = call.SharedExtendedProperty(sharedPropFilter)
+	fetch := func(pageToken string) ([]*calendar.Event, string, error) {
+		call := svc.Events.List(calendarID).
+			TimeMin(from).
+			TimeMax(to).
+			MaxResults(maxResults).
+			PageToken(pageToken).
+			SingleEvents(true).
+			OrderBy("startTime")
+		if strings.TrimSpace(query) != "" {
+			call = call.Q(query)
+		}
+		if strings.TrimSpace(privatePropFilter) != "" {
+			call = call.PrivateExtendedProperty(privatePropFilter)
+		}
+		if strings.TrimSpace(sharedPropFilter) != "" {
+			call = call.SharedExtendedProperty(sharedPropFilter)
+		}
+		if strings.TrimSpace(fields) != "" {
+			call = call.Fields(gapi.Field(fields))
+		}
+		resp, err := call.Context(ctx).Do()
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Items, resp.NextPageToken, nil
 	}
-	if strings.TrimSpace(fields) != "" {
-		call = call.Fields(gapi.Field(fields))
+	table := calendarEventsTable(showWeekday)
+	if paging.AllPages {
+		return streamAllPages(ctx, paging, page, func(pageToken string) ([]*eventWithDays, string, error) {
+			items, next, err := fetch(pageToken)
+			if err != nil {
+				return nil, "", err
+			}
+			return wrapEventsWithDays(items), next, nil
+		}, table)
 	}
-	resp, err := call.Context(ctx).Do()
+
+	items, nextPageToken, err := fetch(page)
 	if err != nil {
 		return err
 	}
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
-			"events":        wrapEventsWithDays(resp.Items),
-			"nextPageToken": resp.NextPageToken,
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
+			"events":        wrapEventsWithDays(items),
+			"nextPageToken": nextPageToken,
 		})
 	}
 
-	if len(resp.Items) == 0 {
+	if len(items) == 0 {
 		u.Err().Println("No events")
 		return nil
 	}
@@ -54,22 +73,31 @@ func listCalendarEvents(ctx context.Context, svc *calendar.Service, calendarID,
 	w, flush := tableWriter(ctx)
 	defer flush()
 
+	fmt.Fprintln(w, table.header)
+	for _, e := range wrapEventsWithDays(items) {
+		table.row(w, e)
+	}
+	printNextPageHint(u, nextPageToken)
+	return nil
+}
+
+func calendarEventsTable(showWeekday bool) pageTable[*eventWithDays] {
 	if showWeekday {
-		fmt.Fprintln(w, "ID\tSTART\tSTART_DOW\tEND\tEND_DOW\tSUMMARY")
-		for _, e := range resp.Items {
-			startDay, endDay := eventDaysOfWeek(e)
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", e.Id, eventStart(e), startDay, eventEnd(e), endDay, e.Summary)
+		return pageTable[*eventWithDays]{
+			header: "ID\tSTART\tSTART_DOW\tEND\tEND_DOW\tSUMMARY",
+			row: func(w io.Writer, e *eventWithDays) {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", e.Id, eventStart(e.Event), e.StartDayOfWeek, eventEnd(e.Event), e.EndDayOfWeek, e.Summary)
+			},
+			empty: "No events",
 		}
-		printNextPageHint(u, resp.NextPageToken)
-		return nil
 	}
-
-	fmt.Fprintln(w, "ID\tSTART\tEND\tSUMMARY")
-	for _, e := range resp.Items {
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e.Id, eventStart(e), eventEnd(e), e.Summary)
+	return pageTable[*eventWithDays]{
+		header: "ID\tSTART\tEND\tSUMMARY",
+		row: func(w io.Writer, e *eventWithDays) {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e.Id, eventStart(e.Event), eventEnd(e.Event), e.Summary)
+		},
+		empty: "No events",
 	}
-	printNextPageHint(u, resp.NextPageToken)
-	return nil
 }
 
 type eventWithCalendar struct {
@@ -139,7 +167,7 @@ func listAllCalendarsEvents(ctx context.Context, svc *calendar.Service, from, to
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"events": all})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"events": all})
 	}
 	if len(all) == 0 {
 		u.Err().Println("No events")
@@ -133,7 +133,7 @@ func (c *CalendarProposeTimeCmd) Run(ctx context.Context, flags *RootFlags) erro
 				result["comment"] = strings.TrimSpace(c.Comment)
 			}
 		}
-		return outfmt.WriteJSON(os.Stdout, result)
+		return outfmt.Write(ctx, os.Stdout, result)
 	}
 
 	// Text output
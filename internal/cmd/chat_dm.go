@@ -42,6 +42,9 @@ func (c *ChatDMSendCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if text == "" {
 		return usage("required: --text")
 	}
+	if err = checkOutbound(ctx, flags, account, outboundAction{Action: "send chat message", Recipients: []string{email}}); err != nil {
+		return err
+	}
 
 	svc, err := newChatService(ctx, account)
 	if err != nil {
@@ -77,7 +80,7 @@ func (c *ChatDMSendCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"message": resp})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"message": resp})
 	}
 
 	if resp == nil {
@@ -126,7 +129,7 @@ func (c *ChatDMSpaceCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"space": space})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"space": space})
 	}
 	if space.Name != "" {
 		u.Out().Printf("resource\t%s", space.Name)
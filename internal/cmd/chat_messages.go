@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -18,12 +19,21 @@ type ChatMessagesCmd struct {
 }
 
 type ChatMessagesListCmd struct {
-	Space  string `arg:"" name:"space" help:"Space name (spaces/...)"`
-	Max    int64  `name:"max" aliases:"limit" help:"Max results" default:"50"`
-	Page   string `name:"page" help:"Page token"`
-	Order  string `name:"order" help:"Order by (e.g. createTime desc)"`
-	Thread string `name:"thread" help:"Filter by thread (spaces/.../threads/...)"`
-	Unread bool   `name:"unread" help:"Only messages after last read time"`
+	Space  string      `arg:"" name:"space" help:"Space name (spaces/...)"`
+	Max    int64       `name:"max" aliases:"limit" help:"Max results" default:"50"`
+	Page   string      `name:"page" help:"Page token"`
+	Paging PagingFlags `embed:""`
+	Order  string      `name:"order" help:"Order by (e.g. createTime desc)"`
+	Thread string      `name:"thread" help:"Filter by thread (spaces/.../threads/...)"`
+	Unread bool        `name:"unread" help:"Only messages after last read time"`
+}
+
+type chatMessageItem struct {
+	Resource   string `json:"resource"`
+	Sender     string `json:"sender,omitempty"`
+	Text       string `json:"text,omitempty"`
+	CreateTime string `json:"createTime,omitempty"`
+	Thread     string `json:"thread,omitempty"`
 }
 
 func (c *ChatMessagesListCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -66,35 +76,27 @@ func (c *ChatMessagesListCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 	filter := strings.Join(filters, " AND ")
 
-	call := svc.Spaces.Messages.List(space).
-		PageSize(c.Max).
-		PageToken(c.Page)
-	if strings.TrimSpace(c.Order) != "" {
-		call = call.OrderBy(c.Order)
-	}
-	if filter != "" {
-		call = call.Filter(filter)
-	}
-
-	resp, err := call.Do()
-	if err != nil {
-		return err
-	}
+	fetch := func(pageToken string) ([]chatMessageItem, string, error) {
+		call := svc.Spaces.Messages.List(space).
+			PageSize(c.Max).
+			PageToken(pageToken)
+		if strings.TrimSpace(c.Order) != "" {
+			call = call.OrderBy(c.Order)
+		}
+		if filter != "" {
+			call = call.Filter(filter)
+		}
 
-	if outfmt.IsJSON(ctx) {
-		type item struct {
-			Resource   string `json:"resource"`
-			Sender     string `json:"sender,omitempty"`
-			Text       string `json:"text,omitempty"`
-			CreateTime string `json:"createTime,omitempty"`
-			Thread     string `json:"thread,omitempty"`
+		resp, err := call.Do()
+		if err != nil {
+			return nil, "", err
 		}
-		items := make([]item, 0, len(resp.Messages))
+		items := make([]chatMessageItem, 0, len(resp.Messages))
 		for _, msg := range resp.Messages {
 			if msg == nil {
 				continue
 			}
-			items = append(items, item{
+			items = append(items, chatMessageItem{
 				Resource:   msg.Name,
 				Sender:     chatMessageSender(msg),
 				Text:       chatMessageText(msg),
@@ -102,35 +104,52 @@ func (c *ChatMessagesListCmd) Run(ctx context.Context, flags *RootFlags) error {
 				Thread:     chatMessageThread(msg),
 			})
 		}
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return items, resp.NextPageToken, nil
+	}
+	if c.Paging.AllPages {
+		return streamAllPages(ctx, c.Paging, c.Page, fetch, chatMessagesTable)
+	}
+
+	items, nextPageToken, err := fetch(c.Page)
+	if err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"messages":      items,
-			"nextPageToken": resp.NextPageToken,
+			"nextPageToken": nextPageToken,
 		})
 	}
 
-	if len(resp.Messages) == 0 {
+	if len(items) == 0 {
 		u.Err().Println("No messages")
 		return nil
 	}
 
 	w, flush := tableWriter(ctx)
 	defer flush()
-	fmt.Fprintln(w, "RESOURCE\tSENDER\tTIME\tTEXT")
-	for _, msg := range resp.Messages {
-		if msg == nil {
-			continue
-		}
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
-			msg.Name,
-			sanitizeTab(chatMessageSender(msg)),
-			sanitizeTab(msg.CreateTime),
-			sanitizeChatText(chatMessageText(msg)),
-		)
+	fmt.Fprintln(w, chatMessagesTable.header)
+	for _, it := range items {
+		chatMessagesTable.row(w, it)
 	}
-	printNextPageHint(u, resp.NextPageToken)
+	printNextPageHint(u, nextPageToken)
 	return nil
 }
 
+var chatMessagesTable = pageTable[chatMessageItem]{
+	header: "RESOURCE\tSENDER\tTIME\tTEXT",
+	row: func(w io.Writer, it chatMessageItem) {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+			it.Resource,
+			sanitizeTab(it.Sender),
+			sanitizeTab(it.CreateTime),
+			sanitizeChatText(it.Text),
+		)
+	},
+	empty: "No messages",
+}
+
 type ChatMessagesSendCmd struct {
 	Space  string `arg:"" name:"space" help:"Space name (spaces/...)"`
 	Text   string `name:"text" help:"Message text (required)"`
@@ -183,7 +202,7 @@ func (c *ChatMessagesSendCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"message": resp})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"message": resp})
 	}
 
 	if resp == nil {
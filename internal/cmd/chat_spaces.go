@@ -67,7 +67,7 @@ func (c *ChatSpacesListCmd) Run(ctx context.Context, flags *RootFlags) error {
 				ThreadState: space.SpaceThreadingState,
 			})
 		}
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"spaces":        items,
 			"nextPageToken": resp.NextPageToken,
 		})
@@ -164,7 +164,7 @@ func (c *ChatSpacesFindCmd) Run(ctx context.Context, flags *RootFlags) error {
 				SpaceURI:  space.SpaceUri,
 			})
 		}
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"spaces": items})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"spaces": items})
 	}
 
 	if len(matches) == 0 {
@@ -243,7 +243,7 @@ func (c *ChatSpacesCreateCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"space": resp})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"space": resp})
 	}
 
 	if resp == nil {
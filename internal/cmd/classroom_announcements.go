@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -22,11 +23,12 @@ type ClassroomAnnouncementsCmd struct {
 }
 
 type ClassroomAnnouncementsListCmd struct {
-	CourseID string `arg:"" name:"courseId" help:"Course ID or alias"`
-	States   string `name:"state" help:"Announcement states filter (comma-separated: DRAFT,PUBLISHED,DELETED)"`
-	OrderBy  string `name:"order-by" help:"Order by (e.g., updateTime desc)"`
-	Max      int64  `name:"max" aliases:"limit" help:"Max results" default:"100"`
-	Page     string `name:"page" help:"Page token"`
+	CourseID string      `arg:"" name:"courseId" help:"Course ID or alias"`
+	States   string      `name:"state" help:"Announcement states filter (comma-separated: DRAFT,PUBLISHED,DELETED)"`
+	OrderBy  string      `name:"order-by" help:"Order by (e.g., updateTime desc)"`
+	Max      int64       `name:"max" aliases:"limit" help:"Max results" default:"100"`
+	Page     string      `name:"page" help:"Page token"`
+	Paging   PagingFlags `embed:""`
 }
 
 func (c *ClassroomAnnouncementsListCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -57,13 +59,23 @@ func (c *ClassroomAnnouncementsListCmd) Run(ctx context.Context, flags *RootFlag
 		call.OrderBy(v)
 	}
 
+	if c.Paging.AllPages {
+		return wrapClassroomError(streamAllPages(ctx, c.Paging, c.Page, func(pageToken string) ([]*classroom.Announcement, string, error) {
+			resp, err := call.PageToken(pageToken).Do()
+			if err != nil {
+				return nil, "", err
+			}
+			return resp.Announcements, resp.NextPageToken, nil
+		}, classroomAnnouncementsTable))
+	}
+
 	resp, err := call.Do()
 	if err != nil {
 		return wrapClassroomError(err)
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"announcements": resp.Announcements,
 			"nextPageToken": resp.NextPageToken,
 		})
@@ -76,10 +88,19 @@ func (c *ClassroomAnnouncementsListCmd) Run(ctx context.Context, flags *RootFlag
 
 	w, flush := tableWriter(ctx)
 	defer flush()
-	fmt.Fprintln(w, "ID\tSTATE\tTEXT\tSCHEDULED\tUPDATED")
+	fmt.Fprintln(w, classroomAnnouncementsTable.header)
 	for _, ann := range resp.Announcements {
+		classroomAnnouncementsTable.row(w, ann)
+	}
+	printNextPageHint(u, resp.NextPageToken)
+	return nil
+}
+
+var classroomAnnouncementsTable = pageTable[*classroom.Announcement]{
+	header: "ID\tSTATE\tTEXT\tSCHEDULED\tUPDATED",
+	row: func(w io.Writer, ann *classroom.Announcement) {
 		if ann == nil {
-			continue
+			return
 		}
 		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
 			sanitizeTab(ann.Id),
@@ -88,9 +109,8 @@ func (c *ClassroomAnnouncementsListCmd) Run(ctx context.Context, flags *RootFlag
 			sanitizeTab(ann.ScheduledTime),
 			sanitizeTab(ann.UpdateTime),
 		)
-	}
-	printNextPageHint(u, resp.NextPageToken)
-	return nil
+	},
+	empty: "No announcements",
 }
 
 type ClassroomAnnouncementsGetCmd struct {
@@ -124,7 +144,7 @@ func (c *ClassroomAnnouncementsGetCmd) Run(ctx context.Context, flags *RootFlags
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"announcement": ann})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"announcement": ann})
 	}
 
 	u.Out().Printf("id\t%s", ann.Id)
@@ -181,7 +201,7 @@ func (c *ClassroomAnnouncementsCreateCmd) Run(ctx context.Context, flags *RootFl
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"announcement": created})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"announcement": created})
 	}
 	u.Out().Printf("id\t%s", created.Id)
 	u.Out().Printf("state\t%s", created.State)
@@ -240,7 +260,7 @@ func (c *ClassroomAnnouncementsUpdateCmd) Run(ctx context.Context, flags *RootFl
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"announcement": updated})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"announcement": updated})
 	}
 	u.Out().Printf("id\t%s", updated.Id)
 	u.Out().Printf("state\t%s", updated.State)
@@ -282,7 +302,7 @@ func (c *ClassroomAnnouncementsDeleteCmd) Run(ctx context.Context, flags *RootFl
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"deleted":        true,
 			"courseId":       courseID,
 			"announcementId": announcementID,
@@ -340,7 +360,7 @@ func (c *ClassroomAnnouncementsAssigneesCmd) Run(ctx context.Context, flags *Roo
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"announcement": updated})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"announcement": updated})
 	}
 	u.Out().Printf("id\t%s", updated.Id)
 	u.Out().Printf("assignee_mode\t%s", updated.AssigneeMode)
@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -26,11 +27,12 @@ type ClassroomCoursesCmd struct {
 }
 
 type ClassroomCoursesListCmd struct {
-	States    string `name:"state" help:"Course states filter (comma-separated: ACTIVE,ARCHIVED,PROVISIONED,DECLINED)"`
-	TeacherID string `name:"teacher" help:"Filter by teacher user ID or email"`
-	StudentID string `name:"student" help:"Filter by student user ID or email"`
-	Max       int64  `name:"max" aliases:"limit" help:"Max results" default:"100"`
-	Page      string `name:"page" help:"Page token"`
+	States    string      `name:"state" help:"Course states filter (comma-separated: ACTIVE,ARCHIVED,PROVISIONED,DECLINED)"`
+	TeacherID string      `name:"teacher" help:"Filter by teacher user ID or email"`
+	StudentID string      `name:"student" help:"Filter by student user ID or email"`
+	Max       int64       `name:"max" aliases:"limit" help:"Max results" default:"100"`
+	Page      string      `name:"page" help:"Page token"`
+	Paging    PagingFlags `embed:""`
 }
 
 func (c *ClassroomCoursesListCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -60,13 +62,23 @@ func (c *ClassroomCoursesListCmd) Run(ctx context.Context, flags *RootFlags) err
 		call.StudentId(v)
 	}
 
+	if c.Paging.AllPages {
+		return wrapClassroomError(streamAllPages(ctx, c.Paging, c.Page, func(pageToken string) ([]*classroom.Course, string, error) {
+			resp, err := call.PageToken(pageToken).Do()
+			if err != nil {
+				return nil, "", err
+			}
+			return resp.Courses, resp.NextPageToken, nil
+		}, classroomCoursesTable))
+	}
+
 	resp, err := call.Do()
 	if err != nil {
 		return wrapClassroomError(err)
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"courses":       resp.Courses,
 			"nextPageToken": resp.NextPageToken,
 		})
@@ -79,10 +91,19 @@ func (c *ClassroomCoursesListCmd) Run(ctx context.Context, flags *RootFlags) err
 
 	w, flush := tableWriter(ctx)
 	defer flush()
-	fmt.Fprintln(w, "ID\tNAME\tSECTION\tSTATE\tOWNER")
+	fmt.Fprintln(w, classroomCoursesTable.header)
 	for _, course := range resp.Courses {
+		classroomCoursesTable.row(w, course)
+	}
+	printNextPageHint(u, resp.NextPageToken)
+	return nil
+}
+
+var classroomCoursesTable = pageTable[*classroom.Course]{
+	header: "ID\tNAME\tSECTION\tSTATE\tOWNER",
+	row: func(w io.Writer, course *classroom.Course) {
 		if course == nil {
-			continue
+			return
 		}
 		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
 			sanitizeTab(course.Id),
@@ -91,9 +112,8 @@ func (c *ClassroomCoursesListCmd) Run(ctx context.Context, flags *RootFlags) err
 			sanitizeTab(course.CourseState),
 			sanitizeTab(course.OwnerId),
 		)
-	}
-	printNextPageHint(u, resp.NextPageToken)
-	return nil
+	},
+	empty: "No courses",
 }
 
 type ClassroomCoursesGetCmd struct {
@@ -122,7 +142,7 @@ func (c *ClassroomCoursesGetCmd) Run(ctx context.Context, flags *RootFlags) erro
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"course": course})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"course": course})
 	}
 
 	u.Out().Printf("id\t%s", course.Id)
@@ -208,7 +228,7 @@ func (c *ClassroomCoursesCreateCmd) Run(ctx context.Context, flags *RootFlags) e
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"course": created})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"course": created})
 	}
 	u.Out().Printf("id\t%s", created.Id)
 	u.Out().Printf("name\t%s", created.Name)
@@ -285,7 +305,7 @@ func (c *ClassroomCoursesUpdateCmd) Run(ctx context.Context, flags *RootFlags) e
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"course": updated})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"course": updated})
 	}
 	u := ui.FromContext(ctx)
 	u.Out().Printf("id\t%s", updated.Id)
@@ -324,7 +344,7 @@ func (c *ClassroomCoursesDeleteCmd) Run(ctx context.Context, flags *RootFlags) e
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"deleted":  true,
 			"courseId": courseID,
 		})
@@ -373,7 +393,7 @@ func updateCourseState(ctx context.Context, flags *RootFlags, courseID, state st
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"course": updated})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"course": updated})
 	}
 	u.Out().Printf("id\t%s", updated.Id)
 	u.Out().Printf("state\t%s", updated.CourseState)
@@ -420,7 +440,7 @@ func (c *ClassroomCoursesJoinCmd) Run(ctx context.Context, flags *RootFlags) err
 			return wrapClassroomError(err)
 		}
 		if outfmt.IsJSON(ctx) {
-			return outfmt.WriteJSON(os.Stdout, map[string]any{"student": created})
+			return outfmt.Write(ctx, os.Stdout, map[string]any{"student": created})
 		}
 		u.Out().Printf("user_id\t%s", created.UserId)
 		u.Out().Printf("email\t%s", profileEmail(created.Profile))
@@ -433,7 +453,7 @@ func (c *ClassroomCoursesJoinCmd) Run(ctx context.Context, flags *RootFlags) err
 			return wrapClassroomError(err)
 		}
 		if outfmt.IsJSON(ctx) {
-			return outfmt.WriteJSON(os.Stdout, map[string]any{"teacher": created})
+			return outfmt.Write(ctx, os.Stdout, map[string]any{"teacher": created})
 		}
 		u.Out().Printf("user_id\t%s", created.UserId)
 		u.Out().Printf("email\t%s", profileEmail(created.Profile))
@@ -490,7 +510,7 @@ func (c *ClassroomCoursesLeaveCmd) Run(ctx context.Context, flags *RootFlags) er
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"removed":  true,
 			"courseId": courseID,
 			"userId":   userID,
@@ -532,7 +552,7 @@ func (c *ClassroomCoursesURLCmd) Run(ctx context.Context, flags *RootFlags) erro
 			}
 			urls = append(urls, map[string]string{"id": id, "url": link})
 		}
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"urls": urls})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"urls": urls})
 	}
 
 	for _, id := range c.CourseIDs {
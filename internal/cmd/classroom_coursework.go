@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -22,13 +23,14 @@ type ClassroomCourseworkCmd struct {
 }
 
 type ClassroomCourseworkListCmd struct {
-	CourseID  string `arg:"" name:"courseId" help:"Course ID or alias"`
-	States    string `name:"state" help:"Coursework states filter (comma-separated: DRAFT,PUBLISHED,DELETED)"`
-	Topic     string `name:"topic" help:"Filter by topic ID"`
-	OrderBy   string `name:"order-by" help:"Order by (e.g., updateTime desc, dueDate desc)"`
-	Max       int64  `name:"max" aliases:"limit" help:"Max results" default:"100"`
-	Page      string `name:"page" help:"Page token"`
-	ScanPages int    `name:"scan-pages" help:"Pages to scan when filtering by topic" default:"3"`
+	CourseID  string      `arg:"" name:"courseId" help:"Course ID or alias"`
+	States    string      `name:"state" help:"Coursework states filter (comma-separated: DRAFT,PUBLISHED,DELETED)"`
+	Topic     string      `name:"topic" help:"Filter by topic ID"`
+	OrderBy   string      `name:"order-by" help:"Order by (e.g., updateTime desc, dueDate desc)"`
+	Max       int64       `name:"max" aliases:"limit" help:"Max results" default:"100"`
+	Page      string      `name:"page" help:"Page token"`
+	Paging    PagingFlags `embed:""`
+	ScanPages int         `name:"scan-pages" help:"Pages to scan when filtering by topic" default:"3"`
 }
 
 func (c *ClassroomCourseworkListCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -62,30 +64,32 @@ func (c *ClassroomCourseworkListCmd) Run(ctx context.Context, flags *RootFlags)
 		return call.Do()
 	}
 
-	coursework, nextPageToken, err := scanClassroomTopicPages(
-		c.Topic,
-		c.Page,
-		c.ScanPages,
-		func(page string) ([]*classroom.CourseWork, string, error) {
-			resp, callErr := makeCall(page)
-			if callErr != nil {
-				return nil, "", callErr
-			}
-			return resp.CourseWork, resp.NextPageToken, nil
-		},
-		func(work *classroom.CourseWork) string {
-			if work == nil {
-				return ""
-			}
-			return work.TopicId
-		},
-	)
+	fetchPage := func(page string) ([]*classroom.CourseWork, string, error) {
+		resp, callErr := makeCall(page)
+		if callErr != nil {
+			return nil, "", callErr
+		}
+		return resp.CourseWork, resp.NextPageToken, nil
+	}
+	topicOf := func(work *classroom.CourseWork) string {
+		if work == nil {
+			return ""
+		}
+		return work.TopicId
+	}
+	if c.Paging.AllPages {
+		return wrapClassroomError(streamAllPages(ctx, c.Paging, c.Page, func(page string) ([]*classroom.CourseWork, string, error) {
+			return scanClassroomTopicPages(c.Topic, page, 1, fetchPage, topicOf)
+		}, classroomCourseworkTable))
+	}
+
+	coursework, nextPageToken, err := scanClassroomTopicPages(c.Topic, c.Page, c.ScanPages, fetchPage, topicOf)
 	if err != nil {
 		return wrapClassroomError(err)
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"coursework":    coursework,
 			"nextPageToken": nextPageToken,
 		})
@@ -99,10 +103,19 @@ func (c *ClassroomCourseworkListCmd) Run(ctx context.Context, flags *RootFlags)
 
 	w, flush := tableWriter(ctx)
 	defer flush()
-	fmt.Fprintln(w, "ID\tTITLE\tSTATE\tDUE\tTYPE\tMAX_POINTS")
+	fmt.Fprintln(w, classroomCourseworkTable.header)
 	for _, work := range coursework {
+		classroomCourseworkTable.row(w, work)
+	}
+	printNextPageHint(u, nextPageToken)
+	return nil
+}
+
+var classroomCourseworkTable = pageTable[*classroom.CourseWork]{
+	header: "ID\tTITLE\tSTATE\tDUE\tTYPE\tMAX_POINTS",
+	row: func(w io.Writer, work *classroom.CourseWork) {
 		if work == nil {
-			continue
+			return
 		}
 		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
 			sanitizeTab(work.Id),
@@ -112,9 +125,8 @@ func (c *ClassroomCourseworkListCmd) Run(ctx context.Context, flags *RootFlags)
 			sanitizeTab(work.WorkType),
 			formatFloatValue(work.MaxPoints),
 		)
-	}
-	printNextPageHint(u, nextPageToken)
-	return nil
+	},
+	empty: "No coursework",
 }
 
 type ClassroomCourseworkGetCmd struct {
@@ -148,7 +160,7 @@ func (c *ClassroomCourseworkGetCmd) Run(ctx context.Context, flags *RootFlags) e
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"coursework": work})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"coursework": work})
 	}
 
 	u.Out().Printf("id\t%s", work.Id)
@@ -264,7 +276,7 @@ func (c *ClassroomCourseworkCreateCmd) Run(ctx context.Context, flags *RootFlags
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"coursework": created})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"coursework": created})
 	}
 	u.Out().Printf("id\t%s", created.Id)
 	u.Out().Printf("title\t%s", created.Title)
@@ -377,7 +389,7 @@ func (c *ClassroomCourseworkUpdateCmd) Run(ctx context.Context, flags *RootFlags
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"coursework": updated})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"coursework": updated})
 	}
 	u.Out().Printf("id\t%s", updated.Id)
 	u.Out().Printf("title\t%s", updated.Title)
@@ -420,7 +432,7 @@ func (c *ClassroomCourseworkDeleteCmd) Run(ctx context.Context, flags *RootFlags
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"deleted":      true,
 			"courseId":     courseID,
 			"courseworkId": courseworkID,
@@ -478,7 +490,7 @@ func (c *ClassroomCourseworkAssigneesCmd) Run(ctx context.Context, flags *RootFl
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"coursework": updated})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"coursework": updated})
 	}
 	u.Out().Printf("id\t%s", updated.Id)
 	u.Out().Printf("assignee_mode\t%s", updated.AssigneeMode)
@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -19,10 +20,11 @@ type ClassroomGuardiansCmd struct {
 }
 
 type ClassroomGuardiansListCmd struct {
-	StudentID string `arg:"" name:"studentId" help:"Student ID"`
-	Email     string `name:"email" help:"Filter by invited email address"`
-	Max       int64  `name:"max" aliases:"limit" help:"Max results" default:"100"`
-	Page      string `name:"page" help:"Page token"`
+	StudentID string      `arg:"" name:"studentId" help:"Student ID"`
+	Email     string      `name:"email" help:"Filter by invited email address"`
+	Max       int64       `name:"max" aliases:"limit" help:"Max results" default:"100"`
+	Page      string      `name:"page" help:"Page token"`
+	Paging    PagingFlags `embed:""`
 }
 
 func (c *ClassroomGuardiansListCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -46,13 +48,23 @@ func (c *ClassroomGuardiansListCmd) Run(ctx context.Context, flags *RootFlags) e
 		call.InvitedEmailAddress(v)
 	}
 
+	if c.Paging.AllPages {
+		return wrapClassroomError(streamAllPages(ctx, c.Paging, c.Page, func(pageToken string) ([]*classroom.Guardian, string, error) {
+			resp, err := call.PageToken(pageToken).Do()
+			if err != nil {
+				return nil, "", err
+			}
+			return resp.Guardians, resp.NextPageToken, nil
+		}, classroomGuardiansTable))
+	}
+
 	resp, err := call.Do()
 	if err != nil {
 		return wrapClassroomError(err)
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"guardians":     resp.Guardians,
 			"nextPageToken": resp.NextPageToken,
 		})
@@ -65,19 +77,27 @@ func (c *ClassroomGuardiansListCmd) Run(ctx context.Context, flags *RootFlags) e
 
 	w, flush := tableWriter(ctx)
 	defer flush()
-	fmt.Fprintln(w, "GUARDIAN_ID\tEMAIL\tNAME")
+	fmt.Fprintln(w, classroomGuardiansTable.header)
 	for _, guardian := range resp.Guardians {
+		classroomGuardiansTable.row(w, guardian)
+	}
+	printNextPageHint(u, resp.NextPageToken)
+	return nil
+}
+
+var classroomGuardiansTable = pageTable[*classroom.Guardian]{
+	header: "GUARDIAN_ID\tEMAIL\tNAME",
+	row: func(w io.Writer, guardian *classroom.Guardian) {
 		if guardian == nil {
-			continue
+			return
 		}
 		fmt.Fprintf(w, "%s\t%s\t%s\n",
 			sanitizeTab(guardian.GuardianId),
 			sanitizeTab(profileEmail(guardian.GuardianProfile)),
 			sanitizeTab(profileName(guardian.GuardianProfile)),
 		)
-	}
-	printNextPageHint(u, resp.NextPageToken)
-	return nil
+	},
+	empty: "No guardians",
 }
 
 type ClassroomGuardiansGetCmd struct {
@@ -111,7 +131,7 @@ func (c *ClassroomGuardiansGetCmd) Run(ctx context.Context, flags *RootFlags) er
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"guardian": guardian})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"guardian": guardian})
 	}
 
 	u.Out().Printf("id\t%s", guardian.GuardianId)
@@ -156,7 +176,7 @@ func (c *ClassroomGuardiansDeleteCmd) Run(ctx context.Context, flags *RootFlags)
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"deleted":    true,
 			"studentId":  studentID,
 			"guardianId": guardianID,
@@ -175,11 +195,12 @@ type ClassroomGuardianInvitesCmd struct {
 }
 
 type ClassroomGuardianInvitesListCmd struct {
-	StudentID string `arg:"" name:"studentId" help:"Student ID"`
-	Email     string `name:"email" help:"Filter by invited email address"`
-	States    string `name:"state" help:"Invitation states filter (comma-separated: PENDING,COMPLETE)"`
-	Max       int64  `name:"max" aliases:"limit" help:"Max results" default:"100"`
-	Page      string `name:"page" help:"Page token"`
+	StudentID string      `arg:"" name:"studentId" help:"Student ID"`
+	Email     string      `name:"email" help:"Filter by invited email address"`
+	States    string      `name:"state" help:"Invitation states filter (comma-separated: PENDING,COMPLETE)"`
+	Max       int64       `name:"max" aliases:"limit" help:"Max results" default:"100"`
+	Page      string      `name:"page" help:"Page token"`
+	Paging    PagingFlags `embed:""`
 }
 
 func (c *ClassroomGuardianInvitesListCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -210,13 +231,23 @@ func (c *ClassroomGuardianInvitesListCmd) Run(ctx context.Context, flags *RootFl
 		call.States(upper...)
 	}
 
+	if c.Paging.AllPages {
+		return wrapClassroomError(streamAllPages(ctx, c.Paging, c.Page, func(pageToken string) ([]*classroom.GuardianInvitation, string, error) {
+			resp, err := call.PageToken(pageToken).Do()
+			if err != nil {
+				return nil, "", err
+			}
+			return resp.GuardianInvitations, resp.NextPageToken, nil
+		}, classroomGuardianInvitesTable))
+	}
+
 	resp, err := call.Do()
 	if err != nil {
 		return wrapClassroomError(err)
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"invitations":   resp.GuardianInvitations,
 			"nextPageToken": resp.NextPageToken,
 		})
@@ -229,10 +260,19 @@ func (c *ClassroomGuardianInvitesListCmd) Run(ctx context.Context, flags *RootFl
 
 	w, flush := tableWriter(ctx)
 	defer flush()
-	fmt.Fprintln(w, "INVITATION_ID\tEMAIL\tSTATE\tCREATED")
+	fmt.Fprintln(w, classroomGuardianInvitesTable.header)
 	for _, inv := range resp.GuardianInvitations {
+		classroomGuardianInvitesTable.row(w, inv)
+	}
+	printNextPageHint(u, resp.NextPageToken)
+	return nil
+}
+
+var classroomGuardianInvitesTable = pageTable[*classroom.GuardianInvitation]{
+	header: "INVITATION_ID\tEMAIL\tSTATE\tCREATED",
+	row: func(w io.Writer, inv *classroom.GuardianInvitation) {
 		if inv == nil {
-			continue
+			return
 		}
 		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
 			sanitizeTab(inv.InvitationId),
@@ -240,9 +280,8 @@ func (c *ClassroomGuardianInvitesListCmd) Run(ctx context.Context, flags *RootFl
 			sanitizeTab(inv.State),
 			sanitizeTab(inv.CreationTime),
 		)
-	}
-	printNextPageHint(u, resp.NextPageToken)
-	return nil
+	},
+	empty: "No guardian invitations",
 }
 
 type ClassroomGuardianInvitesGetCmd struct {
@@ -276,7 +315,7 @@ func (c *ClassroomGuardianInvitesGetCmd) Run(ctx context.Context, flags *RootFla
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"invitation": inv})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"invitation": inv})
 	}
 
 	u.Out().Printf("id\t%s", inv.InvitationId)
@@ -321,7 +360,7 @@ func (c *ClassroomGuardianInvitesCreateCmd) Run(ctx context.Context, flags *Root
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"invitation": created})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"invitation": created})
 	}
 	u.Out().Printf("id\t%s", created.InvitationId)
 	u.Out().Printf("student_id\t%s", created.StudentId)
@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -21,10 +22,11 @@ type ClassroomInvitationsCmd struct {
 }
 
 type ClassroomInvitationsListCmd struct {
-	CourseID string `name:"course" help:"Filter by course ID"`
-	UserID   string `name:"user" help:"Filter by user ID or email"`
-	Max      int64  `name:"max" aliases:"limit" help:"Max results" default:"100"`
-	Page     string `name:"page" help:"Page token"`
+	CourseID string      `name:"course" help:"Filter by course ID"`
+	UserID   string      `name:"user" help:"Filter by user ID or email"`
+	Max      int64       `name:"max" aliases:"limit" help:"Max results" default:"100"`
+	Page     string      `name:"page" help:"Page token"`
+	Paging   PagingFlags `embed:""`
 }
 
 func (c *ClassroomInvitationsListCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -47,13 +49,23 @@ func (c *ClassroomInvitationsListCmd) Run(ctx context.Context, flags *RootFlags)
 		call.UserId(v)
 	}
 
+	if c.Paging.AllPages {
+		return wrapClassroomError(streamAllPages(ctx, c.Paging, c.Page, func(pageToken string) ([]*classroom.Invitation, string, error) {
+			resp, err := call.PageToken(pageToken).Do()
+			if err != nil {
+				return nil, "", err
+			}
+			return resp.Invitations, resp.NextPageToken, nil
+		}, classroomInvitationsTable))
+	}
+
 	resp, err := call.Do()
 	if err != nil {
 		return wrapClassroomError(err)
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"invitations":   resp.Invitations,
 			"nextPageToken": resp.NextPageToken,
 		})
@@ -66,10 +78,19 @@ func (c *ClassroomInvitationsListCmd) Run(ctx context.Context, flags *RootFlags)
 
 	w, flush := tableWriter(ctx)
 	defer flush()
-	fmt.Fprintln(w, "ID\tCOURSE_ID\tUSER_ID\tROLE")
+	fmt.Fprintln(w, classroomInvitationsTable.header)
 	for _, inv := range resp.Invitations {
+		classroomInvitationsTable.row(w, inv)
+	}
+	printNextPageHint(u, resp.NextPageToken)
+	return nil
+}
+
+var classroomInvitationsTable = pageTable[*classroom.Invitation]{
+	header: "ID\tCOURSE_ID\tUSER_ID\tROLE",
+	row: func(w io.Writer, inv *classroom.Invitation) {
 		if inv == nil {
-			continue
+			return
 		}
 		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
 			sanitizeTab(inv.Id),
@@ -77,9 +98,8 @@ func (c *ClassroomInvitationsListCmd) Run(ctx context.Context, flags *RootFlags)
 			sanitizeTab(inv.UserId),
 			sanitizeTab(inv.Role),
 		)
-	}
-	printNextPageHint(u, resp.NextPageToken)
-	return nil
+	},
+	empty: "No invitations",
 }
 
 type ClassroomInvitationsGetCmd struct {
@@ -108,7 +128,7 @@ func (c *ClassroomInvitationsGetCmd) Run(ctx context.Context, flags *RootFlags)
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"invitation": inv})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"invitation": inv})
 	}
 
 	u.Out().Printf("id\t%s", inv.Id)
@@ -155,7 +175,7 @@ func (c *ClassroomInvitationsCreateCmd) Run(ctx context.Context, flags *RootFlag
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"invitation": created})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"invitation": created})
 	}
 	u.Out().Printf("id\t%s", created.Id)
 	u.Out().Printf("course_id\t%s", created.CourseId)
@@ -189,7 +209,7 @@ func (c *ClassroomInvitationsAcceptCmd) Run(ctx context.Context, flags *RootFlag
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"accepted":     true,
 			"invitationId": invitationID,
 		})
@@ -229,7 +249,7 @@ func (c *ClassroomInvitationsDeleteCmd) Run(ctx context.Context, flags *RootFlag
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"deleted":      true,
 			"invitationId": invitationID,
 		})
@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -21,13 +22,14 @@ type ClassroomMaterialsCmd struct {
 }
 
 type ClassroomMaterialsListCmd struct {
-	CourseID  string `arg:"" name:"courseId" help:"Course ID or alias"`
-	States    string `name:"state" help:"Material states filter (comma-separated: PUBLISHED,DRAFT,DELETED)"`
-	Topic     string `name:"topic" help:"Filter by topic ID"`
-	OrderBy   string `name:"order-by" help:"Order by (e.g., updateTime desc)"`
-	Max       int64  `name:"max" aliases:"limit" help:"Max results" default:"100"`
-	Page      string `name:"page" help:"Page token"`
-	ScanPages int    `name:"scan-pages" help:"Pages to scan when filtering by topic" default:"3"`
+	CourseID  string      `arg:"" name:"courseId" help:"Course ID or alias"`
+	States    string      `name:"state" help:"Material states filter (comma-separated: PUBLISHED,DRAFT,DELETED)"`
+	Topic     string      `name:"topic" help:"Filter by topic ID"`
+	OrderBy   string      `name:"order-by" help:"Order by (e.g., updateTime desc)"`
+	Max       int64       `name:"max" aliases:"limit" help:"Max results" default:"100"`
+	Page      string      `name:"page" help:"Page token"`
+	Paging    PagingFlags `embed:""`
+	ScanPages int         `name:"scan-pages" help:"Pages to scan when filtering by topic" default:"3"`
 }
 
 func (c *ClassroomMaterialsListCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -61,30 +63,32 @@ func (c *ClassroomMaterialsListCmd) Run(ctx context.Context, flags *RootFlags) e
 		return call.Do()
 	}
 
-	materials, nextPageToken, err := scanClassroomTopicPages(
-		c.Topic,
-		c.Page,
-		c.ScanPages,
-		func(page string) ([]*classroom.CourseWorkMaterial, string, error) {
-			resp, callErr := makeCall(page)
-			if callErr != nil {
-				return nil, "", callErr
-			}
-			return resp.CourseWorkMaterial, resp.NextPageToken, nil
-		},
-		func(material *classroom.CourseWorkMaterial) string {
-			if material == nil {
-				return ""
-			}
-			return material.TopicId
-		},
-	)
+	fetchPage := func(page string) ([]*classroom.CourseWorkMaterial, string, error) {
+		resp, callErr := makeCall(page)
+		if callErr != nil {
+			return nil, "", callErr
+		}
+		return resp.CourseWorkMaterial, resp.NextPageToken, nil
+	}
+	topicOf := func(material *classroom.CourseWorkMaterial) string {
+		if material == nil {
+			return ""
+		}
+		return material.TopicId
+	}
+	if c.Paging.AllPages {
+		return wrapClassroomError(streamAllPages(ctx, c.Paging, c.Page, func(page string) ([]*classroom.CourseWorkMaterial, string, error) {
+			return scanClassroomTopicPages(c.Topic, page, 1, fetchPage, topicOf)
+		}, classroomMaterialsTable))
+	}
+
+	materials, nextPageToken, err := scanClassroomTopicPages(c.Topic, c.Page, c.ScanPages, fetchPage, topicOf)
 	if err != nil {
 		return wrapClassroomError(err)
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"materials":     materials,
 			"nextPageToken": nextPageToken,
 		})
@@ -98,10 +102,19 @@ func (c *ClassroomMaterialsListCmd) Run(ctx context.Context, flags *RootFlags) e
 
 	w, flush := tableWriter(ctx)
 	defer flush()
-	fmt.Fprintln(w, "ID\tTITLE\tSTATE\tUPDATED")
+	fmt.Fprintln(w, classroomMaterialsTable.header)
 	for _, material := range materials {
+		classroomMaterialsTable.row(w, material)
+	}
+	printNextPageHint(u, nextPageToken)
+	return nil
+}
+
+var classroomMaterialsTable = pageTable[*classroom.CourseWorkMaterial]{
+	header: "ID\tTITLE\tSTATE\tUPDATED",
+	row: func(w io.Writer, material *classroom.CourseWorkMaterial) {
 		if material == nil {
-			continue
+			return
 		}
 		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
 			sanitizeTab(material.Id),
@@ -109,9 +122,8 @@ func (c *ClassroomMaterialsListCmd) Run(ctx context.Context, flags *RootFlags) e
 			sanitizeTab(material.State),
 			sanitizeTab(material.UpdateTime),
 		)
-	}
-	printNextPageHint(u, nextPageToken)
-	return nil
+	},
+	empty: "No materials",
 }
 
 type ClassroomMaterialsGetCmd struct {
@@ -145,7 +157,7 @@ func (c *ClassroomMaterialsGetCmd) Run(ctx context.Context, flags *RootFlags) er
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"material": material})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"material": material})
 	}
 
 	u.Out().Printf("id\t%s", material.Id)
@@ -211,7 +223,7 @@ func (c *ClassroomMaterialsCreateCmd) Run(ctx context.Context, flags *RootFlags)
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"material": created})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"material": created})
 	}
 	u.Out().Printf("id\t%s", created.Id)
 	u.Out().Printf("title\t%s", created.Title)
@@ -281,7 +293,7 @@ func (c *ClassroomMaterialsUpdateCmd) Run(ctx context.Context, flags *RootFlags)
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"material": updated})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"material": updated})
 	}
 	u.Out().Printf("id\t%s", updated.Id)
 	u.Out().Printf("title\t%s", updated.Title)
@@ -324,7 +336,7 @@ func (c *ClassroomMaterialsDeleteCmd) Run(ctx context.Context, flags *RootFlags)
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"deleted":    true,
 			"courseId":   courseID,
 			"materialId": materialID,
@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -20,9 +21,10 @@ type ClassroomStudentsCmd struct {
 }
 
 type ClassroomStudentsListCmd struct {
-	CourseID string `arg:"" name:"courseId" help:"Course ID or alias"`
-	Max      int64  `name:"max" aliases:"limit" help:"Max results" default:"100"`
-	Page     string `name:"page" help:"Page token"`
+	CourseID string      `arg:"" name:"courseId" help:"Course ID or alias"`
+	Max      int64       `name:"max" aliases:"limit" help:"Max results" default:"100"`
+	Page     string      `name:"page" help:"Page token"`
+	Paging   PagingFlags `embed:""`
 }
 
 func (c *ClassroomStudentsListCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -41,13 +43,24 @@ func (c *ClassroomStudentsListCmd) Run(ctx context.Context, flags *RootFlags) er
 		return wrapClassroomError(err)
 	}
 
-	resp, err := svc.Courses.Students.List(courseID).PageSize(c.Max).PageToken(c.Page).Context(ctx).Do()
+	call := svc.Courses.Students.List(courseID).PageSize(c.Max).PageToken(c.Page).Context(ctx)
+	if c.Paging.AllPages {
+		return wrapClassroomError(streamAllPages(ctx, c.Paging, c.Page, func(pageToken string) ([]*classroom.Student, string, error) {
+			resp, err := call.PageToken(pageToken).Do()
+			if err != nil {
+				return nil, "", err
+			}
+			return resp.Students, resp.NextPageToken, nil
+		}, classroomStudentsTable))
+	}
+
+	resp, err := call.Do()
 	if err != nil {
 		return wrapClassroomError(err)
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"students":      resp.Students,
 			"nextPageToken": resp.NextPageToken,
 		})
@@ -60,19 +73,27 @@ func (c *ClassroomStudentsListCmd) Run(ctx context.Context, flags *RootFlags) er
 
 	w, flush := tableWriter(ctx)
 	defer flush()
-	fmt.Fprintln(w, "USER_ID\tEMAIL\tNAME")
+	fmt.Fprintln(w, classroomStudentsTable.header)
 	for _, student := range resp.Students {
+		classroomStudentsTable.row(w, student)
+	}
+	printNextPageHint(u, resp.NextPageToken)
+	return nil
+}
+
+var classroomStudentsTable = pageTable[*classroom.Student]{
+	header: "USER_ID\tEMAIL\tNAME",
+	row: func(w io.Writer, student *classroom.Student) {
 		if student == nil {
-			continue
+			return
 		}
 		fmt.Fprintf(w, "%s\t%s\t%s\n",
 			sanitizeTab(student.UserId),
 			sanitizeTab(profileEmail(student.Profile)),
 			sanitizeTab(profileName(student.Profile)),
 		)
-	}
-	printNextPageHint(u, resp.NextPageToken)
-	return nil
+	},
+	empty: "No students",
 }
 
 type ClassroomStudentsGetCmd struct {
@@ -106,7 +127,7 @@ func (c *ClassroomStudentsGetCmd) Run(ctx context.Context, flags *RootFlags) err
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"student": student})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"student": student})
 	}
 
 	u.Out().Printf("user_id\t%s", student.UserId)
@@ -155,7 +176,7 @@ func (c *ClassroomStudentsAddCmd) Run(ctx context.Context, flags *RootFlags) err
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"student": created})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"student": created})
 	}
 	u.Out().Printf("user_id\t%s", created.UserId)
 	u.Out().Printf("email\t%s", profileEmail(created.Profile))
@@ -198,7 +219,7 @@ func (c *ClassroomStudentsRemoveCmd) Run(ctx context.Context, flags *RootFlags)
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"removed":  true,
 			"courseId": courseID,
 			"userId":   userID,
@@ -218,9 +239,10 @@ type ClassroomTeachersCmd struct {
 }
 
 type ClassroomTeachersListCmd struct {
-	CourseID string `arg:"" name:"courseId" help:"Course ID or alias"`
-	Max      int64  `name:"max" aliases:"limit" help:"Max results" default:"100"`
-	Page     string `name:"page" help:"Page token"`
+	CourseID string      `arg:"" name:"courseId" help:"Course ID or alias"`
+	Max      int64       `name:"max" aliases:"limit" help:"Max results" default:"100"`
+	Page     string      `name:"page" help:"Page token"`
+	Paging   PagingFlags `embed:""`
 }
 
 func (c *ClassroomTeachersListCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -239,13 +261,24 @@ func (c *ClassroomTeachersListCmd) Run(ctx context.Context, flags *RootFlags) er
 		return wrapClassroomError(err)
 	}
 
-	resp, err := svc.Courses.Teachers.List(courseID).PageSize(c.Max).PageToken(c.Page).Context(ctx).Do()
+	call := svc.Courses.Teachers.List(courseID).PageSize(c.Max).PageToken(c.Page).Context(ctx)
+	if c.Paging.AllPages {
+		return wrapClassroomError(streamAllPages(ctx, c.Paging, c.Page, func(pageToken string) ([]*classroom.Teacher, string, error) {
+			resp, err := call.PageToken(pageToken).Do()
+			if err != nil {
+				return nil, "", err
+			}
+			return resp.Teachers, resp.NextPageToken, nil
+		}, classroomTeachersTable))
+	}
+
+	resp, err := call.Do()
 	if err != nil {
 		return wrapClassroomError(err)
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"teachers":      resp.Teachers,
 			"nextPageToken": resp.NextPageToken,
 		})
@@ -258,19 +291,27 @@ func (c *ClassroomTeachersListCmd) Run(ctx context.Context, flags *RootFlags) er
 
 	w, flush := tableWriter(ctx)
 	defer flush()
-	fmt.Fprintln(w, "USER_ID\tEMAIL\tNAME")
+	fmt.Fprintln(w, classroomTeachersTable.header)
 	for _, teacher := range resp.Teachers {
+		classroomTeachersTable.row(w, teacher)
+	}
+	printNextPageHint(u, resp.NextPageToken)
+	return nil
+}
+
+var classroomTeachersTable = pageTable[*classroom.Teacher]{
+	header: "USER_ID\tEMAIL\tNAME",
+	row: func(w io.Writer, teacher *classroom.Teacher) {
 		if teacher == nil {
-			continue
+			return
 		}
 		fmt.Fprintf(w, "%s\t%s\t%s\n",
 			sanitizeTab(teacher.UserId),
 			sanitizeTab(profileEmail(teacher.Profile)),
 			sanitizeTab(profileName(teacher.Profile)),
 		)
-	}
-	printNextPageHint(u, resp.NextPageToken)
-	return nil
+	},
+	empty: "No teachers",
 }
 
 type ClassroomTeachersGetCmd struct {
@@ -304,7 +345,7 @@ func (c *ClassroomTeachersGetCmd) Run(ctx context.Context, flags *RootFlags) err
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"teacher": teacher})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"teacher": teacher})
 	}
 
 	u.Out().Printf("user_id\t%s", teacher.UserId)
@@ -345,7 +386,7 @@ func (c *ClassroomTeachersAddCmd) Run(ctx context.Context, flags *RootFlags) err
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"teacher": created})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"teacher": created})
 	}
 	u.Out().Printf("user_id\t%s", created.UserId)
 	u.Out().Printf("email\t%s", profileEmail(created.Profile))
@@ -388,7 +429,7 @@ func (c *ClassroomTeachersRemoveCmd) Run(ctx context.Context, flags *RootFlags)
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"removed":  true,
 			"courseId": courseID,
 			"userId":   userID,
@@ -453,7 +494,7 @@ func (c *ClassroomRosterCmd) Run(ctx context.Context, flags *RootFlags) error {
 			payload["teachers"] = teachersResp.Teachers
 			payload["teachersNextPageToken"] = teachersResp.NextPageToken
 		}
-		return outfmt.WriteJSON(os.Stdout, payload)
+		return outfmt.Write(ctx, os.Stdout, payload)
 	}
 
 	w, flush := tableWriter(ctx)
@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -22,13 +23,14 @@ type ClassroomSubmissionsCmd struct {
 }
 
 type ClassroomSubmissionsListCmd struct {
-	CourseID     string `arg:"" name:"courseId" help:"Course ID or alias"`
-	CourseworkID string `arg:"" name:"courseworkId" help:"Coursework ID"`
-	States       string `name:"state" help:"Submission states filter (comma-separated: NEW,CREATED,TURNED_IN,RETURNED,RECLAIMED_BY_STUDENT)"`
-	Late         string `name:"late" help:"Late filter: late|not-late"`
-	UserID       string `name:"user" help:"Filter by user ID or email"`
-	Max          int64  `name:"max" aliases:"limit" help:"Max results" default:"100"`
-	Page         string `name:"page" help:"Page token"`
+	CourseID     string      `arg:"" name:"courseId" help:"Course ID or alias"`
+	CourseworkID string      `arg:"" name:"courseworkId" help:"Coursework ID"`
+	States       string      `name:"state" help:"Submission states filter (comma-separated: NEW,CREATED,TURNED_IN,RETURNED,RECLAIMED_BY_STUDENT)"`
+	Late         string      `name:"late" help:"Late filter: late|not-late"`
+	UserID       string      `name:"user" help:"Filter by user ID or email"`
+	Max          int64       `name:"max" aliases:"limit" help:"Max results" default:"100"`
+	Page         string      `name:"page" help:"Page token"`
+	Paging       PagingFlags `embed:""`
 }
 
 func (c *ClassroomSubmissionsListCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -73,13 +75,23 @@ func (c *ClassroomSubmissionsListCmd) Run(ctx context.Context, flags *RootFlags)
 		}
 	}
 
+	if c.Paging.AllPages {
+		return wrapClassroomError(streamAllPages(ctx, c.Paging, c.Page, func(pageToken string) ([]*classroom.StudentSubmission, string, error) {
+			resp, err := call.PageToken(pageToken).Do()
+			if err != nil {
+				return nil, "", err
+			}
+			return resp.StudentSubmissions, resp.NextPageToken, nil
+		}, classroomSubmissionsTable))
+	}
+
 	resp, err := call.Do()
 	if err != nil {
 		return wrapClassroomError(err)
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"submissions":   resp.StudentSubmissions,
 			"nextPageToken": resp.NextPageToken,
 		})
@@ -92,10 +104,19 @@ func (c *ClassroomSubmissionsListCmd) Run(ctx context.Context, flags *RootFlags)
 
 	w, flush := tableWriter(ctx)
 	defer flush()
-	fmt.Fprintln(w, "ID\tUSER_ID\tSTATE\tLATE\tDRAFT\tASSIGNED\tUPDATED")
+	fmt.Fprintln(w, classroomSubmissionsTable.header)
 	for _, sub := range resp.StudentSubmissions {
+		classroomSubmissionsTable.row(w, sub)
+	}
+	printNextPageHint(u, resp.NextPageToken)
+	return nil
+}
+
+var classroomSubmissionsTable = pageTable[*classroom.StudentSubmission]{
+	header: "ID\tUSER_ID\tSTATE\tLATE\tDRAFT\tASSIGNED\tUPDATED",
+	row: func(w io.Writer, sub *classroom.StudentSubmission) {
 		if sub == nil {
-			continue
+			return
 		}
 		fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\t%s\t%s\n",
 			sanitizeTab(sub.Id),
@@ -106,9 +127,8 @@ func (c *ClassroomSubmissionsListCmd) Run(ctx context.Context, flags *RootFlags)
 			formatFloatValue(sub.AssignedGrade),
 			sanitizeTab(sub.UpdateTime),
 		)
-	}
-	printNextPageHint(u, resp.NextPageToken)
-	return nil
+	},
+	empty: "No submissions",
 }
 
 type ClassroomSubmissionsGetCmd struct {
@@ -147,7 +167,7 @@ func (c *ClassroomSubmissionsGetCmd) Run(ctx context.Context, flags *RootFlags)
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"submission": sub})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"submission": sub})
 	}
 
 	u.Out().Printf("id\t%s", sub.Id)
@@ -237,7 +257,7 @@ func submissionAction(ctx context.Context, flags *RootFlags, courseID, coursewor
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"ok":           true,
 			"courseId":     courseID,
 			"courseworkId": courseworkID,
@@ -313,7 +333,7 @@ func (c *ClassroomSubmissionsGradeCmd) Run(ctx context.Context, flags *RootFlags
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"submission": updated})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"submission": updated})
 	}
 	u.Out().Printf("id\t%s", updated.Id)
 	u.Out().Printf("draft_grade\t%s", formatFloatValue(updated.DraftGrade))
@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -21,9 +22,10 @@ type ClassroomTopicsCmd struct {
 }
 
 type ClassroomTopicsListCmd struct {
-	CourseID string `arg:"" name:"courseId" help:"Course ID or alias"`
-	Max      int64  `name:"max" aliases:"limit" help:"Max results" default:"100"`
-	Page     string `name:"page" help:"Page token"`
+	CourseID string      `arg:"" name:"courseId" help:"Course ID or alias"`
+	Max      int64       `name:"max" aliases:"limit" help:"Max results" default:"100"`
+	Page     string      `name:"page" help:"Page token"`
+	Paging   PagingFlags `embed:""`
 }
 
 func (c *ClassroomTopicsListCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -42,13 +44,24 @@ func (c *ClassroomTopicsListCmd) Run(ctx context.Context, flags *RootFlags) erro
 		return wrapClassroomError(err)
 	}
 
-	resp, err := svc.Courses.Topics.List(courseID).PageSize(c.Max).PageToken(c.Page).Context(ctx).Do()
+	call := svc.Courses.Topics.List(courseID).PageSize(c.Max).PageToken(c.Page).Context(ctx)
+	if c.Paging.AllPages {
+		return wrapClassroomError(streamAllPages(ctx, c.Paging, c.Page, func(pageToken string) ([]*classroom.Topic, string, error) {
+			resp, err := call.PageToken(pageToken).Do()
+			if err != nil {
+				return nil, "", err
+			}
+			return resp.Topic, resp.NextPageToken, nil
+		}, classroomTopicsTable))
+	}
+
+	resp, err := call.Do()
 	if err != nil {
 		return wrapClassroomError(err)
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"topics":        resp.Topic,
 			"nextPageToken": resp.NextPageToken,
 		})
@@ -61,19 +74,27 @@ func (c *ClassroomTopicsListCmd) Run(ctx context.Context, flags *RootFlags) erro
 
 	w, flush := tableWriter(ctx)
 	defer flush()
-	fmt.Fprintln(w, "TOPIC_ID\tNAME\tUPDATED")
+	fmt.Fprintln(w, classroomTopicsTable.header)
 	for _, topic := range resp.Topic {
+		classroomTopicsTable.row(w, topic)
+	}
+	printNextPageHint(u, resp.NextPageToken)
+	return nil
+}
+
+var classroomTopicsTable = pageTable[*classroom.Topic]{
+	header: "TOPIC_ID\tNAME\tUPDATED",
+	row: func(w io.Writer, topic *classroom.Topic) {
 		if topic == nil {
-			continue
+			return
 		}
 		fmt.Fprintf(w, "%s\t%s\t%s\n",
 			sanitizeTab(topic.TopicId),
 			sanitizeTab(topic.Name),
 			sanitizeTab(topic.UpdateTime),
 		)
-	}
-	printNextPageHint(u, resp.NextPageToken)
-	return nil
+	},
+	empty: "No topics",
 }
 
 type ClassroomTopicsGetCmd struct {
@@ -107,7 +128,7 @@ func (c *ClassroomTopicsGetCmd) Run(ctx context.Context, flags *RootFlags) error
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"topic": topic})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"topic": topic})
 	}
 
 	u.Out().Printf("id\t%s", topic.TopicId)
@@ -150,7 +171,7 @@ func (c *ClassroomTopicsCreateCmd) Run(ctx context.Context, flags *RootFlags) er
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"topic": created})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"topic": created})
 	}
 	u.Out().Printf("id\t%s", created.TopicId)
 	u.Out().Printf("name\t%s", created.Name)
@@ -194,7 +215,7 @@ func (c *ClassroomTopicsUpdateCmd) Run(ctx context.Context, flags *RootFlags) er
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"topic": updated})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"topic": updated})
 	}
 	u.Out().Printf("id\t%s", updated.TopicId)
 	u.Out().Printf("name\t%s", updated.Name)
@@ -236,7 +257,7 @@ func (c *ClassroomTopicsDeleteCmd) Run(ctx context.Context, flags *RootFlags) er
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"deleted":  true,
 			"courseId": courseID,
 			"topicId":  topicID,
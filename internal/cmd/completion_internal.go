@@ -7,6 +7,8 @@ import (
 	"sync"
 
 	"github.com/alecthomas/kong"
+
+	"github.com/steipete/gogcli/internal/config"
 )
 
 type completionFlag struct {
@@ -40,6 +42,7 @@ func completeWords(cword int, words []string) ([]string, error) {
 	}
 
 	start := completionStartIndex(words)
+	words, cword = expandCompletionAlias(root, words, start, cword)
 
 	node, terminatorIndex, needsValue := advanceCompletionNode(root, words, start, cword)
 	if needsValue {
@@ -64,6 +67,9 @@ func completeWords(cword int, words []string) ([]string, error) {
 		suggestions = append(suggestions, matchingFlags(node, current)...)
 	} else {
 		suggestions = append(suggestions, matchingCommands(node, current)...)
+		if node == root {
+			suggestions = append(suggestions, matchingAliases(root, current)...)
+		}
 		suggestions = append(suggestions, matchingFlags(node, current)...)
 	}
 	sort.Strings(suggestions)
@@ -82,6 +88,51 @@ func completionRootNode() (*completionNode, error) {
 	return completionRoot, completionRootErr
 }
 
+// expandCompletionAlias replaces a command alias before cword with the words
+// of its template, so the rest of the line completes like the real command.
+func expandCompletionAlias(root *completionNode, words []string, start int, cword int) ([]string, int) {
+	idx := -1
+	for i := start; i < cword && i < len(words); i++ {
+		word := words[i]
+		if word == "--" {
+			return words, cword
+		}
+		if strings.HasPrefix(word, "-") {
+			if flagToken, hasValue := splitFlagToken(word); !hasValue && root.flags[flagToken].takesValue {
+				i++
+			}
+			continue
+		}
+		idx = i
+		break
+	}
+	if idx < 0 {
+		return words, cword
+	}
+	if _, ok := root.children[words[idx]]; ok {
+		return words, cword
+	}
+
+	aliases, err := config.ListCommandAliases()
+	if err != nil {
+		return words, cword
+	}
+	template, ok := aliases[words[idx]]
+	if !ok {
+		return words, cword
+	}
+	expanded, err := splitScriptLine(template, func(string) (string, bool) { return "", true })
+	if err != nil {
+		return words, cword
+	}
+
+	out := make([]string, 0, len(words)+len(expanded))
+	out = append(out, words[:idx]...)
+	out = append(out, expanded...)
+	out = append(out, words[idx+1:]...)
+	return out, cword + len(expanded) - 1
+}
+
 func normalizeCword(cword int, wordCount int) int {
 	if cword < 0 {
 		cword = wordCount - 1
@@ -255,6 +306,23 @@ func matchingCommands(node *completionNode, prefix string) []string {
 	return results
 }
 
+func matchingAliases(root *completionNode, prefix string) []string {
+	aliases, err := config.ListCommandAliases()
+	if err != nil {
+		return nil
+	}
+	results := make([]string, 0, len(aliases))
+	for name := range aliases {
+		if _, builtin := root.children[name]; builtin {
+			continue
+		}
+		if strings.HasPrefix(name, prefix) {
+			results = append(results, name)
+		}
+	}
+	return results
+}
+
 func matchingFlags(node *completionNode, prefix string) []string {
 	results := make([]string, 0, len(node.flags))
 	for name := range node.flags {
@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// aliasArgsMarker stands in for $@ while a template is split, so the
+// arguments can be spliced in as separate words afterwards.
+const aliasArgsMarker = "\x00@\x00"
+
+type ConfigAliasCmd struct {
+	List  ConfigAliasListCmd  `cmd:"" name:"list" help:"List command aliases"`
+	Set   ConfigAliasSetCmd   `cmd:"" name:"set" help:"Set a command alias"`
+	Unset ConfigAliasUnsetCmd `cmd:"" name:"unset" help:"Remove a command alias"`
+}
+
+type ConfigAliasListCmd struct{}
+
+func (c *ConfigAliasListCmd) Run(ctx context.Context) error {
+	u := ui.FromContext(ctx)
+	aliases, err := config.ListCommandAliases()
+	if err != nil {
+		return err
+	}
+	if outfmt.IsJSON(ctx) {
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"aliases": aliases})
+	}
+	if len(aliases) == 0 {
+		u.Err().Println("No command aliases")
+		return nil
+	}
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "ALIAS\tCOMMAND")
+	for _, name := range sortedKeys(aliases) {
+		fmt.Fprintf(w, "%s\t%s\n", name, aliases[name])
+	}
+	return nil
+}
+
+type ConfigAliasSetCmd struct {
+	Name     string `arg:"" name:"name" help:"Alias name (no spaces)"`
+	Template string `arg:"" name:"command" help:"Command line to run, e.g. \"gmail search 'is:unread' --max $$1\" ($$1.. and $$@ are replaced by arguments)"`
+}
+
+func (c *ConfigAliasSetCmd) Run(ctx context.Context, kctx *kong.Context) error {
+	u := ui.FromContext(ctx)
+	name := config.NormalizeCommandAlias(c.Name)
+	if name == "" {
+		return usage("empty alias")
+	}
+	if strings.ContainsAny(name, " \t/\\=") || strings.HasPrefix(name, "-") || name == pluginCommand {
+		return usagef("invalid alias name %q", name)
+	}
+	if isBuiltinCommand(kctx.Model, name) {
+		return usagef("alias %q would shadow the built-in command", name)
+	}
+	template := strings.TrimSpace(c.Template)
+	if template == "" {
+		return usage("empty command")
+	}
+	if strings.HasPrefix(template, "gog ") {
+		template = strings.TrimSpace(strings.TrimPrefix(template, "gog "))
+	}
+	words, err := splitScriptLine(template, func(name string) (string, bool) {
+		_, err := strconv.Atoi(name)
+		return "", name == "@" || err == nil
+	})
+	if err != nil {
+		return usagef("invalid command: %v", err)
+	}
+	if len(words) == 0 {
+		return usage("empty command")
+	}
+	if words[0] == name {
+		return usage("alias must not call itself")
+	}
+
+	if err := config.SetCommandAlias(name, template); err != nil {
+		return err
+	}
+	if outfmt.IsJSON(ctx) {
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
+			"alias":   name,
+			"command": template,
+		})
+	}
+	u.Out().Printf("alias\t%s", name)
+	u.Out().Printf("command\t%s", template)
+	return nil
+}
+
+type ConfigAliasUnsetCmd struct {
+	Name string `arg:"" name:"name" help:"Alias name"`
+}
+
+func (c *ConfigAliasUnsetCmd) Run(ctx context.Context) error {
+	u := ui.FromContext(ctx)
+	name := config.NormalizeCommandAlias(c.Name)
+	if name == "" {
+		return usage("empty alias")
+	}
+	deleted, err := config.DeleteCommandAlias(name)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return usage("alias not found")
+	}
+	if outfmt.IsJSON(ctx) {
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
+			"deleted": true,
+			"alias":   name,
+		})
+	}
+	u.Out().Printf("deleted\ttrue")
+	u.Out().Printf("alias\t%s", name)
+	return nil
+}
+
+// expandCommandAlias replaces a user-defined alias at the command position
+// of args with its template. Built-in commands win over aliases, and the
+// expansion is not expanded again.
+func expandCommandAlias(model *kong.Application, args []string) ([]string, error) {
+	idx := firstCommandIndex(model, args)
+	if idx < 0 || isBuiltinCommand(model, args[idx]) {
+		return args, nil
+	}
+	aliases, err := config.ListCommandAliases()
+	if err != nil {
+		return nil, err
+	}
+	template, ok := aliases[args[idx]]
+	if !ok {
+		return args, nil
+	}
+
+	expanded, err := expandAliasTemplate(template, args[idx+1:])
+	if err != nil {
+		return nil, usagef("alias %q: %v", args[idx], err)
+	}
+	out := make([]string, 0, idx+len(expanded))
+	out = append(out, args[:idx]...)
+	return append(out, expanded...), nil
+}
+
+// expandAliasTemplate splits template like a gog run line. $1, $2, ...
+// take the matching argument and $@ all of them; arguments past the highest
+// $N are appended unless $@ is used.
+func expandAliasTemplate(template string, args []string) ([]string, error) {
+	used, need, spread := 0, 0, false
+	lookup := func(name string) (string, bool) {
+		if name == "@" {
+			spread = true
+			return aliasArgsMarker, true
+		}
+		n, err := strconv.Atoi(name)
+		if err != nil || n < 1 {
+			return "", false
+		}
+		need = max(need, n)
+		if n > len(args) {
+			return "", true
+		}
+		used = max(used, n)
+		return args[n-1], true
+	}
+
+	words, err := splitScriptLine(template, lookup)
+	if err != nil {
+		return nil, usage(err.Error())
+	}
+	if need > len(args) {
+		return nil, usagef("needs %d argument(s), got %d", need, len(args))
+	}
+
+	out := make([]string, 0, len(words)+len(args))
+	for _, w := range words {
+		switch {
+		case w == aliasArgsMarker:
+			out = append(out, args...)
+		case strings.Contains(w, aliasArgsMarker):
+			out = append(out, strings.ReplaceAll(w, aliasArgsMarker, strings.Join(args, " ")))
+		default:
+			out = append(out, w)
+		}
+	}
+	if !spread {
+		out = append(out, args[used:]...)
+	}
+	return out, nil
+}
+
+// aliasHelp lists command aliases for the root help page.
+func aliasHelp() string {
+	aliases, err := config.ListCommandAliases()
+	if err != nil || len(aliases) == 0 {
+		return ""
+	}
+	names := sortedKeys(aliases)
+	width := 0
+	for _, name := range names {
+		width = max(width, len(name))
+	}
+	var b strings.Builder
+	b.WriteString("Aliases:\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "  %-*s  %s\n", width, name, aliases[name])
+	}
+	return b.String()
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
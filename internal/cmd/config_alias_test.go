@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/steipete/gogcli/internal/config"
+)
+
+func TestExpandAliasTemplate(t *testing.T) {
+	cases := []struct {
+		template string
+		args     []string
+		want     []string
+	}{
+		{"gmail search 'in:inbox is:unread' --max 50", nil, []string{"gmail", "search", "in:inbox is:unread", "--max", "50"}},
+		{"calendar events --today", []string{"--all", "--json"}, []string{"calendar", "events", "--today", "--all", "--json"}},
+		{`gmail search "from:$1 newer_than:$2"`, []string{"bob", "7d", "--max", "5"}, []string{"gmail", "search", "from:bob newer_than:7d", "--max", "5"}},
+		{"drive search $@ --max 10", []string{"budget", "2026"}, []string{"drive", "search", "budget", "2026", "--max", "10"}},
+		{`gmail search "in:inbox $@"`, []string{"is:unread", "label:x"}, []string{"gmail", "search", "in:inbox is:unread label:x"}},
+	}
+	for _, tc := range cases {
+		got, err := expandAliasTemplate(tc.template, tc.args)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.template, err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("%s %q:\n got %q\nwant %q", tc.template, tc.args, got, tc.want)
+		}
+	}
+
+	if _, err := expandAliasTemplate("gmail get $2", []string{"only-one"}); err == nil || ExitCode(err) != 2 {
+		t.Fatalf("expected usage error for missing argument, got %v", err)
+	}
+}
+
+func setupAliasConfig(t *testing.T, aliases map[string]string) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	if err := config.WriteConfig(config.File{Aliases: aliases}); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+}
+
+func TestExecute_CommandAlias(t *testing.T) {
+	setupAliasConfig(t, map[string]string{
+		"utc":     "time now --timezone UTC",
+		"zone":    "time now --timezone $1",
+		"config":  "time now",
+		"broken":  "time now 'open",
+		"missing": "time now --timezone $1",
+	})
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--json", "utc"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	if !strings.Contains(out, `"timezone": "UTC"`) {
+		t.Fatalf("unexpected alias output: %q", out)
+	}
+
+	out = captureStdout(t, func() {
+		if err := Execute([]string{"zone", "Europe/Vienna", "--json"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	if !strings.Contains(out, `"timezone": "Europe/Vienna"`) {
+		t.Fatalf("unexpected positional output: %q", out)
+	}
+
+	// Built-in commands win over aliases.
+	out = captureStdout(t, func() {
+		if err := Execute([]string{"config", "path"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	if !strings.Contains(out, "config.json") {
+		t.Fatalf("expected built-in config path, got %q", out)
+	}
+
+	for _, args := range [][]string{{"broken"}, {"missing"}} {
+		var err error
+		_ = captureStderr(t, func() {
+			err = Execute(args)
+		})
+		if err == nil || ExitCode(err) != 2 || !strings.Contains(err.Error(), args[0]) {
+			t.Fatalf("%v: expected usage error, got %v", args, err)
+		}
+	}
+}
+
+func TestExecute_ConfigAliasSetListUnset(t *testing.T) {
+	setupAliasConfig(t, nil)
+
+	_ = captureStdout(t, func() {
+		if err := Execute([]string{"config", "alias", "set", "inbox", "gog gmail search 'in:inbox is:unread' --max 50"}); err != nil {
+			t.Fatalf("set: %v", err)
+		}
+	})
+	aliases, err := config.ListCommandAliases()
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if aliases["inbox"] != "gmail search 'in:inbox is:unread' --max 50" {
+		t.Fatalf("unexpected aliases: %#v", aliases)
+	}
+
+	for _, args := range [][]string{
+		{"config", "alias", "set", "gmail", "drive ls"},
+		{"config", "alias", "set", "loop", "loop --json"},
+		{"config", "alias", "set", "bad name", "drive ls"},
+		{"config", "alias", "set", "open", "drive get 'x"},
+	} {
+		var err error
+		_ = captureStderr(t, func() {
+			err = Execute(args)
+		})
+		if err == nil || ExitCode(err) != 2 {
+			t.Fatalf("%v: expected usage error, got %v", args, err)
+		}
+	}
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"config", "alias", "list", "--plain"}); err != nil {
+			t.Fatalf("list: %v", err)
+		}
+	})
+	if !strings.Contains(out, "inbox\tgmail search 'in:inbox is:unread' --max 50") {
+		t.Fatalf("unexpected list output: %q", out)
+	}
+
+	help := captureStdout(t, func() {
+		_ = Execute([]string{"--help"})
+	})
+	if !strings.Contains(help, "Aliases:") || !strings.Contains(help, "inbox") {
+		t.Fatalf("expected alias in help, got:\n%s", help)
+	}
+
+	_ = captureStdout(t, func() {
+		if err := Execute([]string{"config", "alias", "unset", "inbox"}); err != nil {
+			t.Fatalf("unset: %v", err)
+		}
+	})
+	_ = captureStderr(t, func() {
+		err = Execute([]string{"config", "alias", "unset", "inbox"})
+	})
+	if err == nil || ExitCode(err) != 2 {
+		t.Fatalf("expected missing alias error, got %v", err)
+	}
+}
+
+func TestCompleteWords_CommandAlias(t *testing.T) {
+	setupAliasConfig(t, map[string]string{"inbox": "gmail search 'is:unread'"})
+
+	got, err := completeWords(1, []string{"gog", "inb"})
+	if err != nil {
+		t.Fatalf("completeWords: %v", err)
+	}
+	if !slices.Contains(got, "inbox") {
+		t.Fatalf("expected alias suggestion, got %v", got)
+	}
+
+	got, err = completeWords(2, []string{"gog", "inbox", "--ma"})
+	if err != nil {
+		t.Fatalf("completeWords: %v", err)
+	}
+	if !slices.Contains(got, "--max") {
+		t.Fatalf("expected gmail search flags after alias, got %v", got)
+	}
+}
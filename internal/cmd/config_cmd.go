@@ -10,12 +10,14 @@ import (
 )
 
 type ConfigCmd struct {
-	Get   ConfigGetCmd   `cmd:"" help:"Get a config value"`
-	Keys  ConfigKeysCmd  `cmd:"" help:"List available config keys"`
-	Set   ConfigSetCmd   `cmd:"" help:"Set a config value"`
-	Unset ConfigUnsetCmd `cmd:"" help:"Unset a config value"`
-	List  ConfigListCmd  `cmd:"" help:"List all config values"`
-	Path  ConfigPathCmd  `cmd:"" help:"Print config file path"`
+	Get     ConfigGetCmd     `cmd:"" help:"Get a config value"`
+	Keys    ConfigKeysCmd    `cmd:"" help:"List available config keys"`
+	Set     ConfigSetCmd     `cmd:"" help:"Set a config value"`
+	Unset   ConfigUnsetCmd   `cmd:"" help:"Unset a config value"`
+	List    ConfigListCmd    `cmd:"" help:"List all config values"`
+	Path    ConfigPathCmd    `cmd:"" help:"Print config file path"`
+	Alias   ConfigAliasCmd   `cmd:"" help:"Manage command aliases"`
+	Profile ConfigProfileCmd `cmd:"" help:"Manage named profiles (--profile)"`
 }
 
 type ConfigGetCmd struct {
@@ -39,7 +41,7 @@ func (c *ConfigGetCmd) Run(ctx context.Context) error {
 	value := config.GetValue(cfg, key)
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, outfmt.KeyValuePayload(key.String(), value))
+		return outfmt.Write(ctx, os.Stdout, outfmt.KeyValuePayload(key.String(), value))
 	}
 	fmt.Fprintln(os.Stdout, formatConfigValue(value, spec.EmptyHint))
 	return nil
@@ -50,7 +52,7 @@ type ConfigKeysCmd struct{}
 func (c *ConfigKeysCmd) Run(ctx context.Context) error {
 	keys := config.KeyNames()
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, outfmt.KeysPayload(keys))
+		return outfmt.Write(ctx, os.Stdout, outfmt.KeysPayload(keys))
 	}
 	for _, key := range keys {
 		fmt.Fprintln(os.Stdout, key)
@@ -85,7 +87,7 @@ func (c *ConfigSetCmd) Run(ctx context.Context) error {
 	if outfmt.IsJSON(ctx) {
 		payload := outfmt.KeyValuePayload(key.String(), c.Value)
 		payload["saved"] = true
-		return outfmt.WriteJSON(os.Stdout, payload)
+		return outfmt.Write(ctx, os.Stdout, payload)
 	}
 	fmt.Fprintf(os.Stdout, "Set %s = %s\n", c.Key, c.Value)
 	return nil
@@ -117,7 +119,7 @@ func (c *ConfigUnsetCmd) Run(ctx context.Context) error {
 	if outfmt.IsJSON(ctx) {
 		payload := outfmt.KeyValuePayload(key.String(), "")
 		payload["removed"] = true
-		return outfmt.WriteJSON(os.Stdout, payload)
+		return outfmt.Write(ctx, os.Stdout, payload)
 	}
 	fmt.Fprintf(os.Stdout, "Unset %s\n", c.Key)
 	return nil
@@ -139,7 +141,7 @@ func (c *ConfigListCmd) Run(ctx context.Context) error {
 		for _, key := range keys {
 			payload[key.String()] = config.GetValue(cfg, key)
 		}
-		return outfmt.WriteJSON(os.Stdout, payload)
+		return outfmt.Write(ctx, os.Stdout, payload)
 	}
 
 	fmt.Fprintf(os.Stdout, "Config file: %s\n", path)
@@ -159,7 +161,7 @@ func (c *ConfigPathCmd) Run(ctx context.Context) error {
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, outfmt.PathPayload(path))
+		return outfmt.Write(ctx, os.Stdout, outfmt.PathPayload(path))
 	}
 	fmt.Fprintln(os.Stdout, path)
 	return nil
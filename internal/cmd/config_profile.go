@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// profileTimezone is the timezone of the profile selected for the current
+// command; resolveTimezone uses it after --timezone and GOG_TIMEZONE.
+var profileTimezone string
+
+// profileFlagEnv names the environment variable that takes precedence over
+// a profile for root flags the profile can set.
+var profileFlagEnv = map[string]string{
+	"account":         "GOG_ACCOUNT",
+	"client":          "GOG_CLIENT",
+	"enable-commands": "GOG_ENABLE_COMMANDS",
+}
+
+type ConfigProfileCmd struct {
+	List  ConfigProfileListCmd  `cmd:"" name:"list" help:"List profiles"`
+	Get   ConfigProfileGetCmd   `cmd:"" name:"get" help:"Show a profile"`
+	Set   ConfigProfileSetCmd   `cmd:"" name:"set" help:"Set a profile value (creates the profile)"`
+	Unset ConfigProfileUnsetCmd `cmd:"" name:"unset" help:"Remove a profile value, or the whole profile"`
+}
+
+type ConfigProfileListCmd struct{}
+
+func (c *ConfigProfileListCmd) Run(ctx context.Context) error {
+	u := ui.FromContext(ctx)
+	profiles, err := config.ListProfiles()
+	if err != nil {
+		return err
+	}
+	if outfmt.IsJSON(ctx) {
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"profiles": profiles})
+	}
+	if len(profiles) == 0 {
+		u.Err().Println("No profiles")
+		return nil
+	}
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "PROFILE\tACCOUNT\tCLIENT\tTIMEZONE\tOUTPUT")
+	for _, name := range names {
+		p := profiles[name]
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", name, p.Account, p.Client, p.Timezone, p.Output)
+	}
+	return nil
+}
+
+type ConfigProfileGetCmd struct {
+	Name string `arg:"" name:"name" help:"Profile name"`
+}
+
+func (c *ConfigProfileGetCmd) Run(ctx context.Context) error {
+	p, err := loadProfile(c.Name)
+	if err != nil {
+		return err
+	}
+	if outfmt.IsJSON(ctx) {
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
+			"name":    config.NormalizeProfileName(c.Name),
+			"profile": p,
+		})
+	}
+	u := ui.FromContext(ctx)
+	for _, kv := range p.Values() {
+		u.Out().Printf("%s\t%s", kv[0], kv[1])
+	}
+	return nil
+}
+
+type ConfigProfileSetCmd struct {
+	Name  string `arg:"" name:"name" help:"Profile name"`
+	Key   string `arg:"" name:"key" help:"account|client|timezone|output|enable_commands, or flags.<command>.<flag> (e.g. flags.gmail.search.max)"`
+	Value string `arg:"" name:"value" help:"Value to set"`
+}
+
+func (c *ConfigProfileSetCmd) Run(ctx context.Context, kctx *kong.Context) error {
+	u := ui.FromContext(ctx)
+	name := config.NormalizeProfileName(c.Name)
+	if name == "" || strings.ContainsAny(name, " \t") {
+		return usagef("invalid profile name %q", c.Name)
+	}
+	key, err := canonicalProfileKey(kctx.Model, c.Key)
+	if err != nil {
+		return err
+	}
+	value := strings.TrimSpace(c.Value)
+	if key == "output" {
+		if _, err := outfmt.ParseFormat(value); err != nil {
+			return newUsageError(err)
+		}
+	}
+
+	if err := config.SetProfileValue(name, key, value); err != nil {
+		return newUsageError(err)
+	}
+	if outfmt.IsJSON(ctx) {
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
+			"profile": name,
+			"key":     key,
+			"value":   value,
+		})
+	}
+	u.Out().Printf("profile\t%s", name)
+	u.Out().Printf("%s\t%s", key, value)
+	return nil
+}
+
+type ConfigProfileUnsetCmd struct {
+	Name string `arg:"" name:"name" help:"Profile name"`
+	Key  string `arg:"" optional:"" name:"key" help:"Key to remove (omit to delete the profile)"`
+}
+
+func (c *ConfigProfileUnsetCmd) Run(ctx context.Context, kctx *kong.Context) error {
+	u := ui.FromContext(ctx)
+	name := config.NormalizeProfileName(c.Name)
+	if _, err := loadProfile(name); err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(c.Key) == "" {
+		if _, err := config.DeleteProfile(name); err != nil {
+			return err
+		}
+		if outfmt.IsJSON(ctx) {
+			return outfmt.Write(ctx, os.Stdout, map[string]any{"deleted": true, "profile": name})
+		}
+		u.Out().Printf("deleted\ttrue")
+		u.Out().Printf("profile\t%s", name)
+		return nil
+	}
+
+	key, err := canonicalProfileKey(kctx.Model, c.Key)
+	if err != nil {
+		return err
+	}
+	if err := config.UnsetProfileValue(name, key); err != nil {
+		return err
+	}
+	if outfmt.IsJSON(ctx) {
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"profile": name, "unset": key})
+	}
+	u.Out().Printf("profile\t%s", name)
+	u.Out().Printf("unset\t%s", key)
+	return nil
+}
+
+// loadProfile returns the named profile; an empty name is the empty profile.
+func loadProfile(name string) (config.Profile, error) {
+	if strings.TrimSpace(name) == "" {
+		return config.Profile{}, nil
+	}
+	p, ok, err := config.GetProfile(name)
+	if err != nil {
+		return config.Profile{}, err
+	}
+	if !ok {
+		return config.Profile{}, usagef("unknown profile %q (see `gog config profile list`)", config.NormalizeProfileName(name))
+	}
+	return p, nil
+}
+
+// canonicalProfileKey validates key and rewrites command aliases in
+// flags.<command>.<flag> keys to the canonical command path.
+func canonicalProfileKey(model *kong.Application, key string) (string, error) {
+	key = strings.TrimSpace(key)
+	for _, k := range config.ProfileKeys {
+		if key == k {
+			return key, nil
+		}
+	}
+
+	command, flag, ok := config.ParseProfileFlagKey(key)
+	if !ok {
+		return "", usagef("unknown profile key %q (expected %s or flags.<command>.<flag>)", key, strings.Join(config.ProfileKeys, ", "))
+	}
+	node := findCommandNode(model.Node, strings.Fields(command))
+	if node == nil {
+		return "", usagef("unknown command %q", command)
+	}
+	for _, group := range node.AllFlags(false) {
+		for _, f := range group {
+			if f.Name == flag {
+				return config.ProfileFlagKey(commandNodePath(node), flag), nil
+			}
+		}
+	}
+	return "", usagef("unknown flag --%s for %q", flag, commandNodePath(node))
+}
+
+func findCommandNode(node *kong.Node, path []string) *kong.Node {
+	for _, name := range path {
+		var next *kong.Node
+		for _, child := range node.Children {
+			if child.Type != kong.CommandNode {
+				continue
+			}
+			if child.Name == name || slices.Contains(child.Aliases, name) {
+				next = child
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		node = next
+	}
+	return node
+}
+
+// commandNodePath returns the canonical command path of node, e.g.
+// "gmail search".
+func commandNodePath(node *kong.Node) string {
+	var words []string
+	for n := node; n != nil && n.Type == kong.CommandNode; n = n.Parent {
+		words = append([]string{n.Name}, words...)
+	}
+	return strings.Join(words, " ")
+}
+
+// profileResolver fills flags the command line left unset from the profile
+// selected with --profile/GOG_PROFILE: account, client and enable-commands
+// (unless their environment variable is set) and the profile's flag
+// defaults for the selected command and its parents.
+func profileResolver() kong.Resolver {
+	var (
+		last    *kong.Context
+		profile config.Profile
+	)
+	return kong.ResolverFunc(func(kctx *kong.Context, _ *kong.Path, flag *kong.Flag) (any, error) {
+		if kctx != last {
+			last = kctx
+			profile = selectedProfile(kctx)
+		}
+
+		var commands []string
+		for n := kctx.Selected(); n != nil && n.Type == kong.CommandNode; n = n.Parent {
+			commands = append(commands, commandNodePath(n))
+		}
+		for _, command := range commands {
+			if v, ok := profile.Flags[command][flag.Name]; ok {
+				return v, nil
+			}
+		}
+
+		if env, ok := profileFlagEnv[flag.Name]; ok && os.Getenv(env) != "" {
+			return nil, nil
+		}
+		var v string
+		switch flag.Name {
+		case "account":
+			v = profile.Account
+		case "client":
+			v = profile.Client
+		case "enable-commands":
+			v = profile.EnableCommands
+		}
+		if v == "" {
+			return nil, nil
+		}
+		return v, nil
+	})
+}
+
+// selectedProfile loads the profile named by the --profile flag of kctx.
+// Unknown profiles resolve to nothing here; execute reports them.
+func selectedProfile(kctx *kong.Context) config.Profile {
+	for _, f := range kctx.Model.Flags {
+		if f.Name != "profile" {
+			continue
+		}
+		name, _ := kctx.FlagValue(f).(string)
+		if strings.TrimSpace(name) == "" {
+			return config.Profile{}
+		}
+		p, _, _ := config.GetProfile(name)
+		return p
+	}
+	return config.Profile{}
+}
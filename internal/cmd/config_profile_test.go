@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steipete/gogcli/internal/config"
+)
+
+func setupProfileConfig(t *testing.T, profiles map[string]config.Profile) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("GOG_PROFILE", "")
+	t.Setenv("GOG_ENABLE_COMMANDS", "")
+	t.Cleanup(func() { profileTimezone = "" })
+
+	if err := config.WriteConfig(config.File{Profiles: profiles}); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+}
+
+func TestExecute_Profile_AppliesDefaults(t *testing.T) {
+	setupProfileConfig(t, map[string]config.Profile{
+		"work": {
+			Timezone: "Europe/Vienna",
+			Output:   "json",
+			Flags:    map[string]map[string]string{"time now": {"timezone": "Asia/Tokyo"}},
+		},
+	})
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--profile", "work", "time", "now"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	if !strings.Contains(out, `"timezone": "Asia/Tokyo"`) {
+		t.Fatalf("expected profile output and flag default, got %q", out)
+	}
+	loc, err := getConfiguredTimezone("")
+	if err != nil || loc == nil || loc.String() != "Europe/Vienna" {
+		t.Fatalf("expected profile timezone, got %v (%v)", loc, err)
+	}
+
+	// Command-line flags win over the profile.
+	t.Setenv("GOG_PROFILE", "work")
+	out = captureStdout(t, func() {
+		if err := Execute([]string{"time", "now", "--timezone", "UTC", "--plain"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	if strings.Contains(out, "{") || !strings.Contains(out, "UTC") {
+		t.Fatalf("expected plain UTC output, got %q", out)
+	}
+}
+
+func TestExecute_Profile_EnableCommands(t *testing.T) {
+	setupProfileConfig(t, map[string]config.Profile{"bot": {EnableCommands: "calendar"}})
+
+	var err error
+	_ = captureStderr(t, func() {
+		err = Execute([]string{"--profile", "bot", "time", "now"})
+	})
+	if err == nil || !strings.Contains(err.Error(), "not enabled") {
+		t.Fatalf("expected allowlist error, got %v", err)
+	}
+
+	// The environment takes precedence over the profile.
+	t.Setenv("GOG_ENABLE_COMMANDS", "time")
+	_ = captureStdout(t, func() {
+		if err := Execute([]string{"--profile", "bot", "time", "now"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+
+	_ = captureStderr(t, func() {
+		err = Execute([]string{"--profile", "nope", "time", "now"})
+	})
+	if err == nil || ExitCode(err) != 2 || !strings.Contains(err.Error(), "unknown profile") {
+		t.Fatalf("expected unknown profile error, got %v", err)
+	}
+}
+
+func TestExecute_ConfigProfileSetGetUnset(t *testing.T) {
+	setupProfileConfig(t, nil)
+
+	_ = captureStdout(t, func() {
+		for _, args := range [][]string{
+			{"config", "profile", "set", "ci", "client", "bot"},
+			{"config", "profile", "set", "ci", "output", "ndjson"},
+			{"config", "profile", "set", "ci", "flags.mail.search.max", "25"},
+		} {
+			if err := Execute(args); err != nil {
+				t.Fatalf("%v: %v", args, err)
+			}
+		}
+	})
+
+	p, ok, err := config.GetProfile("ci")
+	if err != nil || !ok {
+		t.Fatalf("get profile: ok=%v err=%v", ok, err)
+	}
+	if p.Client != "bot" || p.Output != "ndjson" || p.Flags["gmail search"]["max"] != "25" {
+		t.Fatalf("unexpected profile: %#v", p)
+	}
+
+	for _, args := range [][]string{
+		{"config", "profile", "set", "ci", "output", "xml"},
+		{"config", "profile", "set", "ci", "flags.gmail.search.nope", "1"},
+		{"config", "profile", "set", "ci", "flags.nope.max", "1"},
+		{"config", "profile", "set", "ci", "colour", "never"},
+	} {
+		var err error
+		_ = captureStderr(t, func() {
+			err = Execute(args)
+		})
+		if err == nil || ExitCode(err) != 2 {
+			t.Fatalf("%v: expected usage error, got %v", args, err)
+		}
+	}
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"config", "profile", "get", "ci"}); err != nil {
+			t.Fatalf("get: %v", err)
+		}
+	})
+	if !strings.Contains(out, "client\tbot") || !strings.Contains(out, "flags.gmail.search.max\t25") {
+		t.Fatalf("unexpected get output: %q", out)
+	}
+
+	_ = captureStdout(t, func() {
+		if err := Execute([]string{"config", "profile", "unset", "ci"}); err != nil {
+			t.Fatalf("unset: %v", err)
+		}
+	})
+	if _, ok, _ := config.GetProfile("ci"); ok {
+		t.Fatalf("expected profile to be deleted")
+	}
+}
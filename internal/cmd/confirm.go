@@ -14,7 +14,8 @@ import (
 )
 
 func confirmDestructive(ctx context.Context, flags *RootFlags, action string) error {
-	if flags.Force {
+	// Nothing is destroyed in dry-run mode; the transport only prints the call.
+	if flags.Force || flags.DryRun {
 		return nil
 	}
 
@@ -23,6 +24,12 @@ func confirmDestructive(ctx context.Context, flags *RootFlags, action string) er
 		return usagef("refusing to %s without --force (non-interactive)", action)
 	}
 
+	return promptConfirm(ctx, action)
+}
+
+// promptConfirm asks "Proceed to <action>?" and fails unless the answer is
+// yes.
+func promptConfirm(ctx context.Context, action string) error {
 	prompt := fmt.Sprintf("Proceed to %s? [y/N]: ", action)
 	line, readErr := input.PromptLine(ctx, prompt)
 	if readErr != nil && !errors.Is(readErr, os.ErrClosed) {
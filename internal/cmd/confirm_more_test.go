@@ -21,3 +21,9 @@ func TestConfirmDestructive_NoInput(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestConfirmDestructive_DryRun(t *testing.T) {
+	if err := confirmDestructive(context.Background(), &RootFlags{DryRun: true, NoInput: true}, "do thing"); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -19,8 +20,16 @@ const (
 )
 
 type ContactsListCmd struct {
-	Max  int64  `name:"max" aliases:"limit" help:"Max results" default:"100"`
-	Page string `name:"page" help:"Page token"`
+	Max    int64       `name:"max" aliases:"limit" help:"Max results" default:"100"`
+	Page   string      `name:"page" help:"Page token"`
+	Paging PagingFlags `embed:""`
+}
+
+type contactListItem struct {
+	Resource string `json:"resource"`
+	Name     string `json:"name,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Phone    string `json:"phone,omitempty"`
 }
 
 func (c *ContactsListCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -35,62 +44,72 @@ func (c *ContactsListCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 
-	resp, err := svc.People.Connections.List(peopleMeResource).
-		PersonFields(contactsReadMask).
-		PageSize(c.Max).
-		PageToken(c.Page).
-		Do()
-	if err != nil {
-		return err
-	}
-	if outfmt.IsJSON(ctx) {
-		type item struct {
-			Resource string `json:"resource"`
-			Name     string `json:"name,omitempty"`
-			Email    string `json:"email,omitempty"`
-			Phone    string `json:"phone,omitempty"`
+	fetch := func(pageToken string) ([]contactListItem, string, error) {
+		resp, err := svc.People.Connections.List(peopleMeResource).
+			PersonFields(contactsReadMask).
+			PageSize(c.Max).
+			PageToken(pageToken).
+			Do()
+		if err != nil {
+			return nil, "", err
 		}
-		items := make([]item, 0, len(resp.Connections))
+		items := make([]contactListItem, 0, len(resp.Connections))
 		for _, p := range resp.Connections {
 			if p == nil {
 				continue
 			}
-			items = append(items, item{
+			items = append(items, contactListItem{
 				Resource: p.ResourceName,
 				Name:     primaryName(p),
 				Email:    primaryEmail(p),
 				Phone:    primaryPhone(p),
 			})
 		}
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return items, resp.NextPageToken, nil
+	}
+	if c.Paging.AllPages {
+		return streamAllPages(ctx, c.Paging, c.Page, fetch, contactsListTable)
+	}
+
+	items, nextPageToken, err := fetch(c.Page)
+	if err != nil {
+		return err
+	}
+	if outfmt.IsJSON(ctx) {
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"contacts":      items,
-			"nextPageToken": resp.NextPageToken,
+			"nextPageToken": nextPageToken,
 		})
 	}
-	if len(resp.Connections) == 0 {
+	if len(items) == 0 {
 		u.Err().Println("No contacts")
 		return nil
 	}
 
 	w, flush := tableWriter(ctx)
 	defer flush()
-	fmt.Fprintln(w, "RESOURCE\tNAME\tEMAIL\tPHONE")
-	for _, p := range resp.Connections {
-		if p == nil {
-			continue
-		}
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
-			p.ResourceName,
-			sanitizeTab(primaryName(p)),
-			sanitizeTab(primaryEmail(p)),
-			sanitizeTab(primaryPhone(p)),
-		)
+	fmt.Fprintln(w, contactsListTable.header)
+	for _, it := range items {
+		contactsListTable.row(w, it)
 	}
 
-	printNextPageHint(u, resp.NextPageToken)
+	printNextPageHint(u, nextPageToken)
 	return nil
 }
 
+var contactsListTable = pageTable[contactListItem]{
+	header: "RESOURCE\tNAME\tEMAIL\tPHONE",
+	row: func(w io.Writer, it contactListItem) {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+			it.Resource,
+			sanitizeTab(it.Name),
+			sanitizeTab(it.Email),
+			sanitizeTab(it.Phone),
+		)
+	},
+	empty: "No contacts",
+}
+
 type ContactsGetCmd struct {
 	Identifier string `arg:"" name:"resourceName" help:"Resource name (people/...) or email"`
 }
@@ -140,7 +159,7 @@ func (c *ContactsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 		}
 		if p == nil {
 			if outfmt.IsJSON(ctx) {
-				return outfmt.WriteJSON(os.Stdout, map[string]any{"found": false})
+				return outfmt.Write(ctx, os.Stdout, map[string]any{"found": false})
 			}
 			u.Err().Println("Not found")
 			return nil
@@ -148,7 +167,7 @@ func (c *ContactsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"contact": p})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"contact": p})
 	}
 
 	u.Out().Printf("resource\t%s", p.ResourceName)
@@ -205,7 +224,7 @@ func (c *ContactsCreateCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"contact": created})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"contact": created})
 	}
 	u.Out().Printf("resource\t%s", created.ResourceName)
 	return nil
@@ -287,7 +306,7 @@ func (c *ContactsUpdateCmd) Run(ctx context.Context, kctx *kong.Context, flags *
 		return err
 	}
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"contact": updated})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"contact": updated})
 	}
 	u.Out().Printf("resource\t%s", updated.ResourceName)
 	return nil
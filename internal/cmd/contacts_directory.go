@@ -70,7 +70,7 @@ func (c *ContactsDirectoryListCmd) Run(ctx context.Context, flags *RootFlags) er
 				Email:    primaryEmail(p),
 			})
 		}
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"people":        items,
 			"nextPageToken": resp.NextPageToken,
 		})
@@ -148,7 +148,7 @@ func (c *ContactsDirectorySearchCmd) Run(ctx context.Context, flags *RootFlags)
 				Email:    primaryEmail(p),
 			})
 		}
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"people":        items,
 			"nextPageToken": resp.NextPageToken,
 		})
@@ -226,7 +226,7 @@ func (c *ContactsOtherListCmd) Run(ctx context.Context, flags *RootFlags) error
 				Phone:    primaryPhone(p),
 			})
 		}
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"contacts":      items,
 			"nextPageToken": resp.NextPageToken,
 		})
@@ -301,7 +301,7 @@ func (c *ContactsOtherSearchCmd) Run(ctx context.Context, flags *RootFlags) erro
 				Phone:    primaryPhone(p),
 			})
 		}
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"contacts": items})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"contacts": items})
 	}
 
 	if len(resp.Results) == 0 {
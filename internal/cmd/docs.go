@@ -89,7 +89,7 @@ func (c *DocsInfoCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			strFile:    file,
 			"document": doc,
 		})
@@ -151,7 +151,7 @@ func (c *DocsCreateCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{strFile: created})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{strFile: created})
 	}
 
 	u.Out().Printf("id\t%s", created.Id)
@@ -214,7 +214,7 @@ func (c *DocsCatCmd) Run(ctx context.Context, flags *RootFlags) error {
 	text := docsPlainText(doc, c.MaxBytes)
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"text": text})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"text": text})
 	}
 	_, err = io.WriteString(os.Stdout, text)
 	return err
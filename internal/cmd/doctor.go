@@ -0,0 +1,435 @@
+package cmd
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2/google"
+	ggoogleapi "google.golang.org/api/googleapi"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/errfmt"
+	"github.com/steipete/gogcli/internal/googleauth"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/secrets"
+)
+
+const (
+	doctorPass = "pass"
+	doctorWarn = "warn"
+	doctorFail = "fail"
+	doctorSkip = "skip"
+
+	doctorMaxClockSkew = time.Minute
+)
+
+var (
+	refreshAccessToken = googleauth.RefreshAccessToken
+	doctorBaseURL      = googleauth.BaseURL
+	doctorClockURL     = "https://www.googleapis.com/"
+
+	errKeyringOpenTimeout = errors.New("timed out")
+)
+
+// doctorProbes are cheap reads per service. Docs, Sheets and Groups have no
+// list call without an ID, so a 404 for a made-up ID proves the API is on.
+var doctorProbes = map[googleauth.Service]string{
+	googleauth.ServiceGmail:     "users/me/profile",
+	googleauth.ServiceCalendar:  "users/me/calendarList?maxResults=1",
+	googleauth.ServiceChat:      "spaces?pageSize=1",
+	googleauth.ServiceClassroom: "courses?pageSize=1",
+	googleauth.ServiceDrive:     "about?fields=user",
+	googleauth.ServiceDocs:      "documents/gog-doctor-probe",
+	googleauth.ServiceContacts:  "people/me?personFields=names",
+	googleauth.ServiceTasks:     "users/@me/lists?maxResults=1",
+	googleauth.ServicePeople:    "people/me?personFields=names",
+	googleauth.ServiceSheets:    "spreadsheets/gog-doctor-probe",
+	googleauth.ServiceGroups:    "groups/gog-doctor-probe",
+}
+
+// DoctorCmd checks the local setup end to end and reports what to fix.
+type DoctorCmd struct {
+	Timeout time.Duration `name:"timeout" help:"Timeout per network check and for opening the keyring" default:"15s"`
+}
+
+type doctorCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Hint   string `json:"hint,omitempty"`
+}
+
+type doctorReport struct {
+	checks []doctorCheck
+}
+
+func (r *doctorReport) add(name, status, detail, hint string) {
+	r.checks = append(r.checks, doctorCheck{Name: name, Status: status, Detail: detail, Hint: hint})
+}
+
+func (r *doctorReport) count(status string) int {
+	n := 0
+	for _, c := range r.checks {
+		if c.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+func (c *DoctorCmd) Run(ctx context.Context) error {
+	r := &doctorReport{}
+	client := &http.Client{Timeout: c.Timeout}
+
+	c.checkConfig(r)
+	serviceAccounts, _ := config.ListServiceAccountEmails()
+	c.checkCredentials(r, len(serviceAccounts) > 0)
+	tokens := c.checkKeyring(r)
+	for _, tok := range tokens {
+		c.checkAccount(ctx, r, client, tok)
+	}
+	c.checkClock(ctx, r, client)
+	c.checkServiceAccounts(r, serviceAccounts)
+
+	failed := r.count(doctorFail)
+	if outfmt.IsJSON(ctx) {
+		if err := outfmt.Write(ctx, os.Stdout, map[string]any{
+			"checks": r.checks,
+			"passed": r.count(doctorPass),
+			"warned": r.count(doctorWarn),
+			"failed": failed,
+		}); err != nil {
+			return err
+		}
+	} else {
+		w, flush := tableWriter(ctx)
+		fmt.Fprintln(w, "STATUS\tCHECK\tDETAIL")
+		for _, check := range r.checks {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", check.Status, check.Name, sanitizeTab(check.Detail))
+			if check.Hint != "" {
+				fmt.Fprintf(w, "\t\t→ %s\n", sanitizeTab(check.Hint))
+			}
+		}
+		flush()
+	}
+
+	if failed > 0 {
+		return &ExitError{Code: errfmt.ExitError, Err: fmt.Errorf("%d of %d checks failed", failed, len(r.checks))}
+	}
+	return nil
+}
+
+func (c *DoctorCmd) checkConfig(r *doctorReport) {
+	path, err := config.ConfigPath()
+	if err != nil {
+		r.add("config", doctorFail, err.Error(), "")
+		return
+	}
+	exists, err := config.ConfigExists()
+	if err != nil {
+		r.add("config", doctorFail, err.Error(), "")
+		return
+	}
+	if !exists {
+		r.add("config", doctorPass, path+" (not created yet, using defaults)", "")
+		return
+	}
+	if _, err := config.ReadConfig(); err != nil {
+		r.add("config", doctorFail, err.Error(), "fix the JSON5 syntax in "+path+" or move it away")
+		return
+	}
+	r.add("config", doctorPass, path, "")
+}
+
+func (c *DoctorCmd) checkCredentials(r *doctorReport, haveServiceAccounts bool) {
+	infos, err := config.ListClientCredentials()
+	if err != nil {
+		r.add("credentials", doctorFail, err.Error(), "")
+		return
+	}
+	if len(infos) == 0 {
+		status := doctorFail
+		if haveServiceAccounts {
+			status = doctorWarn
+		}
+		r.add("credentials", status, "no OAuth client credentials stored", "gog auth credentials <credentials.json>")
+		return
+	}
+	for _, info := range infos {
+		name := "credentials " + info.Client
+		if _, err := config.ReadClientCredentialsFor(info.Client); err != nil {
+			r.add(name, doctorFail, err.Error(), "download the OAuth client JSON again and run gog auth credentials --client "+info.Client+" <file>")
+			continue
+		}
+		r.add(name, doctorPass, info.Path, "")
+	}
+}
+
+// checkKeyring opens the keyring and lists tokens. Locked keyrings can block
+// forever on some desktops, so both run under the timeout.
+func (c *DoctorCmd) checkKeyring(r *doctorReport) []secrets.Token {
+	backend := ""
+	if info, err := secrets.ResolveKeyringBackendInfo(); err == nil {
+		backend = info.Value
+	}
+
+	type result struct {
+		tokens []secrets.Token
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		store, err := openSecretsStore()
+		if err != nil {
+			done <- result{err: err}
+			return
+		}
+		tokens, err := store.ListTokens()
+		done <- result{tokens: tokens, err: err}
+	}()
+
+	var res result
+	select {
+	case res = <-done:
+	case <-time.After(c.Timeout):
+		res.err = fmt.Errorf("%w after %s", errKeyringOpenTimeout, c.Timeout)
+	}
+	if res.err != nil {
+		r.add("keyring", doctorFail, fmt.Sprintf("%s: %v", backend, res.err), "unlock the keyring or set GOG_KEYRING_BACKEND=file (and GOG_KEYRING_PASSWORD for non-interactive runs)")
+		return nil
+	}
+	r.add("keyring", doctorPass, fmt.Sprintf("%s, %d token(s)", backend, len(res.tokens)), "")
+
+	sort.Slice(res.tokens, func(i, j int) bool { return res.tokens[i].Email < res.tokens[j].Email })
+	return res.tokens
+}
+
+// checkAccount refreshes the account's token, compares granted scopes with
+// the services it was added for and probes each service's API.
+func (c *DoctorCmd) checkAccount(ctx context.Context, r *doctorReport, client *http.Client, tok secrets.Token) {
+	email := tok.Email
+	reauth := "gog auth add " + email + " --force-consent"
+	if len(tok.Services) > 0 {
+		reauth += " --services " + strings.Join(tok.Services, ",")
+	}
+
+	services := make([]googleauth.Service, 0, len(tok.Services))
+	for _, s := range tok.Services {
+		if svc, err := googleauth.ParseService(s); err == nil {
+			services = append(services, svc)
+		}
+	}
+	required := tok.Scopes
+	if len(required) == 0 {
+		required, _ = googleauth.ScopesForServices(services)
+	}
+
+	access, err := refreshAccessToken(ctx, tok.Client, tok.RefreshToken, required, c.Timeout)
+	if err != nil {
+		hint := reauth
+		var credErr *config.CredentialsMissingError
+		if errors.As(err, &credErr) {
+			hint = "gog auth credentials --client " + tok.Client + " <credentials.json>"
+		}
+		r.add("token "+email, doctorFail, err.Error(), hint)
+		r.add("scopes "+email, doctorSkip, "token did not refresh", "")
+		return
+	}
+	r.add("token "+email, doctorPass, "client "+tok.Client, "")
+
+	if granted := googleauth.GrantedScopes(access); len(granted) == 0 {
+		r.add("scopes "+email, doctorSkip, "server did not report granted scopes", "")
+	} else if missing := missingScopes(required, granted); len(missing) > 0 {
+		r.add("scopes "+email, doctorFail, "not granted: "+strings.Join(missing, " "), reauth)
+	} else {
+		r.add("scopes "+email, doctorPass, fmt.Sprintf("%d scope(s) granted", len(granted)), "")
+	}
+
+	probed := map[string]bool{}
+	for _, svc := range services {
+		probe, ok := doctorProbes[svc]
+		if !ok {
+			continue
+		}
+		base, err := doctorBaseURL(svc)
+		if err != nil {
+			continue
+		}
+		u := base + probe
+		if probed[u] {
+			continue
+		}
+		probed[u] = true
+		status, detail, hint := probeAPI(ctx, client, u, access.AccessToken, svc)
+		r.add(fmt.Sprintf("api %s %s", email, svc), status, detail, hint)
+	}
+}
+
+// missingScopes returns required scopes absent from granted. Google reports
+// the OIDC shorthands "email" and "profile" by their userinfo URLs.
+func missingScopes(required, granted []string) []string {
+	normalize := func(s string) string {
+		switch s {
+		case "email":
+			return "https://www.googleapis.com/auth/userinfo.email"
+		case "profile":
+			return "https://www.googleapis.com/auth/userinfo.profile"
+		}
+		return s
+	}
+	have := make(map[string]bool, len(granted))
+	for _, s := range granted {
+		have[normalize(s)] = true
+	}
+	var out []string
+	for _, s := range required {
+		if !have[normalize(s)] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// probeAPI reports an API as disabled only for SERVICE_DISABLED /
+// accessNotConfigured; any other answer means the API itself is reachable.
+func probeAPI(ctx context.Context, client *http.Client, url, accessToken string, svc googleauth.Service) (string, string, string) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return doctorFail, err.Error(), ""
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return doctorFail, err.Error(), "check your network connection and retry"
+	}
+	defer resp.Body.Close()
+
+	err = ggoogleapi.CheckResponse(resp)
+	if err == nil {
+		return doctorPass, "enabled", ""
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	var gerr *ggoogleapi.Error
+	if !errors.As(err, &gerr) {
+		return doctorFail, err.Error(), ""
+	}
+	if activation, disabled := serviceDisabled(gerr); disabled {
+		hint := "enable the " + apiName(svc) + " for the OAuth client's Google Cloud project"
+		if activation != "" {
+			hint += ": " + activation
+		}
+		return doctorFail, apiName(svc) + " is disabled", hint
+	}
+	if gerr.Code == http.StatusUnauthorized {
+		return doctorFail, gerr.Message, "gog auth add <email> --force-consent"
+	}
+	return doctorPass, fmt.Sprintf("enabled (probe answered %d)", gerr.Code), ""
+}
+
+func serviceDisabled(gerr *ggoogleapi.Error) (string, bool) {
+	disabled := false
+	for _, e := range gerr.Errors {
+		if e.Reason == "accessNotConfigured" {
+			disabled = true
+		}
+	}
+	activation := ""
+	for _, d := range gerr.Details {
+		m, ok := d.(map[string]any)
+		if !ok || m["reason"] != "SERVICE_DISABLED" {
+			continue
+		}
+		disabled = true
+		if meta, ok := m["metadata"].(map[string]any); ok {
+			activation, _ = meta["activationUrl"].(string)
+		}
+	}
+	return activation, disabled
+}
+
+func apiName(svc googleauth.Service) string {
+	for _, info := range googleauth.ServicesInfo() {
+		if info.Service == svc && len(info.APIs) > 0 {
+			return info.APIs[0]
+		}
+	}
+	return string(svc) + " API"
+}
+
+// checkClock compares the local clock with the Date header of a Google
+// server; large skew breaks token expiry and service account assertions.
+func (c *DoctorCmd) checkClock(ctx context.Context, r *doctorReport, client *http.Client) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, doctorClockURL, nil)
+	if err != nil {
+		r.add("clock", doctorFail, err.Error(), "")
+		return
+	}
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		r.add("clock", doctorSkip, err.Error(), "check your network connection and retry")
+		return
+	}
+	_ = resp.Body.Close()
+	end := time.Now()
+
+	server, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		r.add("clock", doctorSkip, "server sent no usable Date header", "")
+		return
+	}
+	// The Date header has second precision.
+	skew := start.Add(end.Sub(start) / 2).Sub(server.Add(500 * time.Millisecond)).Round(time.Second)
+	detail := fmt.Sprintf("skew %s", skew)
+	if skew.Abs() > doctorMaxClockSkew {
+		r.add("clock", doctorFail, detail, "sync the system clock (enable NTP / automatic time)")
+		return
+	}
+	r.add("clock", doctorPass, detail, "")
+}
+
+func (c *DoctorCmd) checkServiceAccounts(r *doctorReport, emails []string) {
+	for _, email := range emails {
+		name := "service-account " + email
+		path, _, ok := bestServiceAccountPathAndMtime(normalizeEmail(email))
+		if !ok {
+			continue
+		}
+		if err := validateServiceAccountKey(path); err != nil {
+			r.add(name, doctorFail, err.Error(), "gog auth service-account set "+email+" --key <key.json>")
+			continue
+		}
+		r.add(name, doctorPass, path, "")
+	}
+}
+
+func validateServiceAccountKey(path string) error {
+	data, err := os.ReadFile(path) //nolint:gosec // path from config dir
+	if err != nil {
+		return fmt.Errorf("read service account key: %w", err)
+	}
+	if _, err := parseServiceAccountJSON(data); err != nil {
+		return err
+	}
+	cfg, err := google.JWTConfigFromJSON(data)
+	if err != nil {
+		return fmt.Errorf("parse service account: %w", err)
+	}
+	if strings.TrimSpace(cfg.Email) == "" {
+		return errors.New("invalid service account JSON: missing client_email")
+	}
+	if block, _ := pem.Decode(cfg.PrivateKey); block == nil {
+		return errors.New("invalid service account JSON: private_key is not a PEM key")
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/googleauth"
+	"github.com/steipete/gogcli/internal/secrets"
+)
+
+func TestMissingScopes(t *testing.T) {
+	got := missingScopes(
+		[]string{"email", "openid", "https://www.googleapis.com/auth/drive", "https://www.googleapis.com/auth/tasks"},
+		[]string{"openid", "https://www.googleapis.com/auth/userinfo.email", "https://www.googleapis.com/auth/drive"},
+	)
+	if len(got) != 1 || got[0] != "https://www.googleapis.com/auth/tasks" {
+		t.Fatalf("unexpected missing scopes %q", got)
+	}
+}
+
+func TestExecute_Doctor_JSON(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodHead:
+			w.Header().Set("Date", time.Now().Add(10*time.Minute).UTC().Format(http.TimeFormat))
+		case r.Header.Get("Authorization") != "Bearer ya29.good":
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		case strings.HasPrefix(r.URL.Path, "/gmail/"):
+			_, _ = w.Write([]byte(`{"emailAddress":"good@example.com"}`))
+		case strings.HasPrefix(r.URL.Path, "/drive/"):
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":{"code":403,"message":"Drive API has not been used","status":"PERMISSION_DENIED","details":[{"@type":"type.googleapis.com/google.rpc.ErrorInfo","reason":"SERVICE_DISABLED","metadata":{"activationUrl":"https://console.example/drive"}}]}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	origStore, origRefresh, origBase, origClock := openSecretsStore, refreshAccessToken, doctorBaseURL, doctorClockURL
+	t.Cleanup(func() {
+		openSecretsStore, refreshAccessToken, doctorBaseURL, doctorClockURL = origStore, origRefresh, origBase, origClock
+	})
+	openSecretsStore = func() (secrets.Store, error) {
+		return &fakeSecretsStore{tokens: []secrets.Token{
+			{Email: "good@example.com", Client: "default", Services: []string{"gmail", "drive"}, Scopes: []string{"openid", "https://www.googleapis.com/auth/drive", "https://www.googleapis.com/auth/gmail.modify"}, RefreshToken: "good"},
+			{Email: "bad@example.com", Client: "default", Services: []string{"gmail"}, RefreshToken: "revoked"},
+		}}, nil
+	}
+	refreshAccessToken = func(_ context.Context, _ string, refreshToken string, _ []string, _ time.Duration) (*oauth2.Token, error) {
+		if refreshToken != "good" {
+			return nil, errors.New("refresh access token: invalid_grant")
+		}
+		tok := &oauth2.Token{AccessToken: "ya29.good"}
+		return tok.WithExtra(map[string]any{"scope": "openid https://www.googleapis.com/auth/drive"}), nil
+	}
+	doctorBaseURL = func(svc googleauth.Service) (string, error) { return srv.URL + "/" + string(svc) + "/", nil }
+	doctorClockURL = srv.URL
+
+	if err := config.WriteClientCredentialsFor("default", config.ClientCredentials{ClientID: "id", ClientSecret: "secret"}); err != nil {
+		t.Fatalf("write credentials: %v", err)
+	}
+	saPath, err := config.ServiceAccountPath("sa@example.com")
+	if err != nil {
+		t.Fatalf("service account path: %v", err)
+	}
+	if err := os.WriteFile(saPath, []byte(`{"type":"service_account","client_email":"sa@p.iam.gserviceaccount.com","private_key":"nope"}`), 0o600); err != nil {
+		t.Fatalf("write service account: %v", err)
+	}
+
+	var runErr error
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			runErr = Execute([]string{"--json", "doctor", "--timeout", "2s"})
+		})
+	})
+	if runErr == nil || ExitCode(runErr) != 1 {
+		t.Fatalf("expected failed checks, got %v", runErr)
+	}
+
+	var report struct {
+		Checks []doctorCheck `json:"checks"`
+		Failed int           `json:"failed"`
+	}
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	got := map[string]doctorCheck{}
+	for _, c := range report.Checks {
+		got[c.Name] = c
+	}
+	want := map[string]string{
+		"config":                         doctorPass,
+		"credentials default":            doctorPass,
+		"keyring":                        doctorPass,
+		"token good@example.com":         doctorPass,
+		"scopes good@example.com":        doctorFail,
+		"api good@example.com gmail":     doctorPass,
+		"api good@example.com drive":     doctorFail,
+		"token bad@example.com":          doctorFail,
+		"clock":                          doctorFail,
+		"service-account sa@example.com": doctorFail,
+	}
+	for name, status := range want {
+		if got[name].Status != status {
+			t.Fatalf("%s: want %s, got %+v\n%s", name, status, got[name], out)
+		}
+	}
+	if !strings.Contains(got["scopes good@example.com"].Detail, "gmail.modify") {
+		t.Fatalf("expected missing gmail scope, got %+v", got["scopes good@example.com"])
+	}
+	if !strings.Contains(got["api good@example.com drive"].Hint, "https://console.example/drive") {
+		t.Fatalf("expected activation link, got %+v", got["api good@example.com drive"])
+	}
+	if !strings.Contains(got["token bad@example.com"].Hint, "gog auth add bad@example.com --force-consent") {
+		t.Fatalf("expected re-auth hint, got %+v", got["token bad@example.com"])
+	}
+	if report.Failed != 5 {
+		t.Fatalf("expected 5 failures, got %d\n%s", report.Failed, out)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -62,10 +63,11 @@ type DriveCmd struct {
 }
 
 type DriveLsCmd struct {
-	Max    int64  `name:"max" aliases:"limit" help:"Max results" default:"20"`
-	Page   string `name:"page" help:"Page token"`
-	Query  string `name:"query" help:"Drive query filter"`
-	Parent string `name:"parent" help:"Folder ID to list (default: root)"`
+	Max    int64       `name:"max" aliases:"limit" help:"Max results" default:"20"`
+	Page   string      `name:"page" help:"Page token"`
+	Paging PagingFlags `embed:""`
+	Query  string      `name:"query" help:"Drive query filter"`
+	Parent string      `name:"parent" help:"Folder ID to list (default: root)"`
 }
 
 func (c *DriveLsCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -85,56 +87,81 @@ func (c *DriveLsCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 
-	q := buildDriveListQuery(folderID, c.Query)
+	fetch := driveListPages(ctx, svc, buildDriveListQuery(folderID, c.Query), c.Max)
+	if c.Paging.AllPages {
+		return streamAllPages(ctx, c.Paging, c.Page, fetch, driveFilesTable("No files"))
+	}
 
-	resp, err := svc.Files.List().
-		Q(q).
-		PageSize(c.Max).
-		PageToken(c.Page).
-		OrderBy("modifiedTime desc").
-		SupportsAllDrives(true).
-		IncludeItemsFromAllDrives(true).
-		Fields("nextPageToken, files(id, name, mimeType, size, modifiedTime, parents, webViewLink)").
-		Context(ctx).
-		Do()
+	files, nextPageToken, err := fetch(c.Page)
 	if err != nil {
 		return err
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
-			"files":         resp.Files,
-			"nextPageToken": resp.NextPageToken,
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
+			"files":         files,
+			"nextPageToken": nextPageToken,
 		})
 	}
 
-	if len(resp.Files) == 0 {
+	if len(files) == 0 {
 		u.Err().Println("No files")
 		return nil
 	}
 
 	w, flush := tableWriter(ctx)
 	defer flush()
-	fmt.Fprintln(w, "ID\tNAME\tTYPE\tSIZE\tMODIFIED")
-	for _, f := range resp.Files {
-		fmt.Fprintf(
-			w,
-			"%s\t%s\t%s\t%s\t%s\n",
-			f.Id,
-			f.Name,
-			driveType(f.MimeType),
-			formatDriveSize(f.Size),
-			formatDateTime(f.ModifiedTime),
-		)
+	table := driveFilesTable("")
+	fmt.Fprintln(w, table.header)
+	for _, f := range files {
+		table.row(w, f)
 	}
-	printNextPageHint(u, resp.NextPageToken)
+	printNextPageHint(u, nextPageToken)
 	return nil
 }
 
+func driveListPages(ctx context.Context, svc *drive.Service, q string, pageSize int64) pageFetcher[*drive.File] {
+	return func(pageToken string) ([]*drive.File, string, error) {
+		resp, err := svc.Files.List().
+			Q(q).
+			PageSize(pageSize).
+			PageToken(pageToken).
+			OrderBy("modifiedTime desc").
+			SupportsAllDrives(true).
+			IncludeItemsFromAllDrives(true).
+			Fields("nextPageToken, files(id, name, mimeType, size, modifiedTime, parents, webViewLink)").
+			Context(ctx).
+			Do()
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Files, resp.NextPageToken, nil
+	}
+}
+
+func driveFilesTable(empty string) pageTable[*drive.File] {
+	return pageTable[*drive.File]{
+		header: "ID\tNAME\tTYPE\tSIZE\tMODIFIED",
+		row: func(w io.Writer, f *drive.File) {
+			fmt.Fprintf(
+				w,
+				"%s\t%s\t%s\t%s\t%s\n",
+				f.Id,
+				f.Name,
+				driveType(f.MimeType),
+				formatDriveSize(f.Size),
+				formatDateTime(f.ModifiedTime),
+			)
+		},
+		empty: empty,
+	}
+}
+
 type DriveSearchCmd struct {
-	Query []string `arg:"" name:"query" help:"Search query"`
-	Max   int64    `name:"max" aliases:"limit" help:"Max results" default:"20"`
-	Page  string   `name:"page" help:"Page token"`
+	Query  []string    `arg:"" name:"query" help:"Search query"`
+	Max    int64       `name:"max" aliases:"limit" help:"Max results" default:"20"`
+	Page   string      `name:"page" help:"Page token"`
+	Paging PagingFlags `embed:""`
 }
 
 func (c *DriveSearchCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -153,62 +180,58 @@ func (c *DriveSearchCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 
-	resp, err := svc.Files.List().
-		Q(buildDriveSearchQuery(query)).
-		PageSize(c.Max).
-		PageToken(c.Page).
-		OrderBy("modifiedTime desc").
-		SupportsAllDrives(true).
-		IncludeItemsFromAllDrives(true).
-		Fields("nextPageToken, files(id, name, mimeType, size, modifiedTime, parents, webViewLink)").
-		Context(ctx).
-		Do()
+	fetch := driveListPages(ctx, svc, buildDriveSearchQuery(query), c.Max)
+	if c.Paging.AllPages {
+		return streamAllPages(ctx, c.Paging, c.Page, fetch, driveFilesTable("No results"))
+	}
+
+	files, nextPageToken, err := fetch(c.Page)
 	if err != nil {
 		return err
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
-			"files":         resp.Files,
-			"nextPageToken": resp.NextPageToken,
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
+			"files":         files,
+			"nextPageToken": nextPageToken,
 		})
 	}
 
-	if len(resp.Files) == 0 {
+	if len(files) == 0 {
 		u.Err().Println("No results")
 		return nil
 	}
 
 	w, flush := tableWriter(ctx)
 	defer flush()
-	fmt.Fprintln(w, "ID\tNAME\tTYPE\tSIZE\tMODIFIED")
-	for _, f := range resp.Files {
-		fmt.Fprintf(
-			w,
-			"%s\t%s\t%s\t%s\t%s\n",
-			f.Id,
-			f.Name,
-			driveType(f.MimeType),
-			formatDriveSize(f.Size),
-			formatDateTime(f.ModifiedTime),
-		)
+	table := driveFilesTable("")
+	fmt.Fprintln(w, table.header)
+	for _, f := range files {
+		table.row(w, f)
 	}
-	printNextPageHint(u, resp.NextPageToken)
+	printNextPageHint(u, nextPageToken)
 	return nil
 }
 
 type DriveGetCmd struct {
-	FileID string `arg:"" name:"fileId" help:"File ID"`
+	FileIDs []string `arg:"" name:"fileId" help:"File ID(s); several IDs are fetched in one batch request"`
 }
 
+const driveGetFields = "id, name, mimeType, size, modifiedTime, createdTime, parents, webViewLink, description, starred"
+
 func (c *DriveGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 	u := ui.FromContext(ctx)
 	account, err := requireAccount(flags)
 	if err != nil {
 		return err
 	}
-	fileID := strings.TrimSpace(c.FileID)
-	if fileID == "" {
+	fileIDs := make([]string, 0, len(c.FileIDs))
+	for _, id := range c.FileIDs {
+		if id = strings.TrimSpace(id); id != "" {
+			fileIDs = append(fileIDs, id)
+		}
+	}
+	if len(fileIDs) == 0 {
 		return usage("empty fileId")
 	}
 
@@ -217,9 +240,27 @@ func (c *DriveGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 
-	f, err := svc.Files.Get(fileID).
+	if len(fileIDs) > 1 {
+		files, err := getDriveFiles(ctx, svc, openBatch(ctx, newDriveBatch, account), fileIDs)
+		if err != nil {
+			return err
+		}
+		if outfmt.IsJSON(ctx) {
+			return outfmt.Write(ctx, os.Stdout, map[string]any{"files": files})
+		}
+		w, flush := tableWriter(ctx)
+		defer flush()
+		table := driveFilesTable("")
+		fmt.Fprintln(w, table.header)
+		for _, f := range files {
+			table.row(w, f)
+		}
+		return nil
+	}
+
+	f, err := svc.Files.Get(fileIDs[0]).
 		SupportsAllDrives(true).
-		Fields("id, name, mimeType, size, modifiedTime, createdTime, parents, webViewLink, description, starred").
+		Fields(driveGetFields).
 		Context(ctx).
 		Do()
 	if err != nil {
@@ -227,7 +268,7 @@ func (c *DriveGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{strFile: f})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{strFile: f})
 	}
 
 	u.Out().Printf("id\t%s", f.Id)
@@ -246,6 +287,35 @@ func (c *DriveGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 	return nil
 }
 
+// getDriveFiles fetches metadata for several files, batched when possible.
+func getDriveFiles(ctx context.Context, svc *drive.Service, batch *googleapi.BatchClient, fileIDs []string) ([]*drive.File, error) {
+	files := make([]*drive.File, 0, len(fileIDs))
+	if batch == nil {
+		for _, id := range fileIDs {
+			f, err := svc.Files.Get(id).SupportsAllDrives(true).Fields(driveGetFields).Context(ctx).Do()
+			if err != nil {
+				return nil, fmt.Errorf("file %s: %w", id, err)
+			}
+			files = append(files, f)
+		}
+		return files, nil
+	}
+
+	query := url.Values{"supportsAllDrives": {"true"}, "fields": {driveGetFields}}
+	urls := make([]string, 0, len(fileIDs))
+	for _, id := range fileIDs {
+		urls = append(urls, batchURL(svc.BasePath, "files/"+url.PathEscape(id), query))
+	}
+	got, errs := batchGetJSON[drive.File](ctx, batch, urls)
+	for i, id := range fileIDs {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("file %s: %w", id, errs[i])
+		}
+		files = append(files, got[i])
+	}
+	return files, nil
+}
+
 type DriveDownloadCmd struct {
 	FileID string         `arg:"" name:"fileId" help:"File ID"`
 	Output OutputPathFlag `embed:""`
@@ -292,7 +362,7 @@ func (c *DriveDownloadCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"path": downloadedPath,
 			"size": size,
 		})
@@ -371,7 +441,7 @@ func (c *DriveUploadCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{strFile: created})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{strFile: created})
 	}
 
 	u.Out().Printf("id\t%s", created.Id)
@@ -422,7 +492,7 @@ func (c *DriveMkdirCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"folder": created})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"folder": created})
 	}
 
 	u.Out().Printf("id\t%s", created.Id)
@@ -461,7 +531,7 @@ func (c *DriveDeleteCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"deleted": true,
 			"id":      fileID,
 		})
@@ -517,9 +587,11 @@ func (c *DriveMoveCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if err != nil {
 		return err
 	}
+	recordUndo(flags, account, undoKindDriveMove, fmt.Sprintf("drive move %s to %s", fileID, parent),
+		undoDriveMove{FileID: fileID, AddParents: meta.Parents, RemoveParents: []string{parent}})
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{strFile: updated})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{strFile: updated})
 	}
 
 	u.Out().Printf("id\t%s", updated.Id)
@@ -552,6 +624,13 @@ func (c *DriveRenameCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 
+	// The previous name is only needed for the undo journal.
+	before, beforeErr := svc.Files.Get(fileID).
+		SupportsAllDrives(true).
+		Fields("id, name").
+		Context(ctx).
+		Do()
+
 	updated, err := svc.Files.Update(fileID, &drive.File{Name: newName}).
 		SupportsAllDrives(true).
 		Fields("id, name").
@@ -560,9 +639,13 @@ func (c *DriveRenameCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if err != nil {
 		return err
 	}
+	if beforeErr == nil && before.Name != "" && before.Name != newName {
+		recordUndo(flags, account, undoKindDriveRename, fmt.Sprintf("drive rename %s %q -> %q", fileID, before.Name, newName),
+			undoDriveRename{FileID: fileID, Name: before.Name})
+	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{strFile: updated})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{strFile: updated})
 	}
 
 	u.Out().Printf("id\t%s", updated.Id)
@@ -600,6 +683,14 @@ func (c *DriveShareCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return usage("invalid --role (expected reader|writer)")
 	}
 
+	share := outboundAction{Action: "share file", Public: c.Anyone}
+	if !c.Anyone {
+		share.Recipients = []string{c.Email}
+	}
+	if err := checkOutbound(ctx, flags, account, share); err != nil {
+		return err
+	}
+
 	svc, err := newDriveService(ctx, account)
 	if err != nil {
 		return err
@@ -630,7 +721,7 @@ func (c *DriveShareCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"link":         link,
 			"permissionId": created.Id,
 			"permission":   created,
@@ -676,7 +767,7 @@ func (c *DriveUnshareCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"removed":      true,
 			"fileId":       fileID,
 			"permissionId": permissionID,
@@ -727,7 +818,7 @@ func (c *DrivePermissionsCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"fileId":          fileID,
 			"permissions":     resp.Permissions,
 			"permissionCount": len(resp.Permissions),
@@ -789,7 +880,7 @@ func (c *DriveURLCmd) Run(ctx context.Context, flags *RootFlags) error {
 			}
 			urls = append(urls, map[string]string{"id": id, "url": link})
 		}
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"urls": urls})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"urls": urls})
 	}
 	return nil
 }
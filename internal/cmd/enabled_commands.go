@@ -1,30 +1,51 @@
 package cmd
 
 import (
+	"slices"
 	"strings"
 
 	"github.com/alecthomas/kong"
 )
 
+// enforceEnabledCommands checks the selected command against a comma-separated
+// rule list. Entries are dotted command paths that also match subcommands
+// ("gmail", "gmail.search", "calendar.*"); a leading "!" denies instead of
+// allows. Deny entries win; with any allow entry present, the command must
+// match one of them. "*" or "all" allows everything.
 func enforceEnabledCommands(kctx *kong.Context, enabled string) error {
 	enabled = strings.TrimSpace(enabled)
 	if enabled == "" {
 		return nil
 	}
-	allow := parseEnabledCommands(enabled)
-	if len(allow) == 0 {
+	rules := parseEnabledCommands(enabled)
+	if len(rules) == 0 {
 		return nil
 	}
-	if allow["*"] || allow["all"] {
+	path := selectedCommandPath(kctx)
+	if len(path) == 0 {
 		return nil
 	}
-	cmd := strings.Fields(kctx.Command())
-	if len(cmd) == 0 {
-		return nil
+	name := commandPathName(path)
+
+	hasAllow, allowed := false, false
+	for rule := range rules {
+		pattern, deny := strings.CutPrefix(rule, "!")
+		if pattern == "all" {
+			pattern = "*"
+		}
+		if !deny {
+			hasAllow = true
+		}
+		if !matchCommandPattern(pattern, path) {
+			continue
+		}
+		if deny {
+			return usagef("command %q is disabled by %q", name, rule)
+		}
+		allowed = true
 	}
-	top := strings.ToLower(cmd[0])
-	if !allow[top] {
-		return usagef("command %q is not enabled (set --enable-commands to allow it)", top)
+	if hasAllow && !allowed {
+		return usagef("command %q is not enabled (set --enable-commands to allow it)", name)
 	}
 	return nil
 }
@@ -40,3 +61,48 @@ func parseEnabledCommands(value string) map[string]bool {
 	}
 	return out
 }
+
+// commandSegment is one command of the selected path with its aliases.
+type commandSegment struct {
+	name    string
+	aliases []string
+}
+
+// selectedCommandPath returns the command nodes of kctx from the top; a
+// plugin is a single segment named after the plugin.
+func selectedCommandPath(kctx *kong.Context) []commandSegment {
+	var path []commandSegment
+	for n := kctx.Selected(); n != nil && n.Type == kong.CommandNode; n = n.Parent {
+		path = append([]commandSegment{{name: n.Name, aliases: n.Aliases}}, path...)
+	}
+	if len(path) > 0 && path[0].name == pluginCommand {
+		return []commandSegment{{name: strings.ToLower(pluginNameFromArgs(kctx.Args))}}
+	}
+	return path
+}
+
+func commandPathName(path []commandSegment) string {
+	names := make([]string, 0, len(path))
+	for _, seg := range path {
+		names = append(names, seg.name)
+	}
+	return strings.Join(names, ".")
+}
+
+// matchCommandPattern reports whether the dotted pattern matches path or one
+// of its parents; "*" matches any single command.
+func matchCommandPattern(pattern string, path []commandSegment) bool {
+	if pattern == "*" {
+		return true
+	}
+	parts := strings.Split(pattern, ".")
+	if len(parts) > len(path) {
+		return false
+	}
+	for i, part := range parts {
+		if part != "*" && part != path[i].name && !slices.Contains(path[i].aliases, part) {
+			return false
+		}
+	}
+	return true
+}
@@ -1,6 +1,9 @@
 package cmd
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestParseEnabledCommands(t *testing.T) {
 	allow := parseEnabledCommands("calendar, tasks ,Gmail")
@@ -8,3 +11,46 @@ func TestParseEnabledCommands(t *testing.T) {
 		t.Fatalf("unexpected allow map: %#v", allow)
 	}
 }
+
+func TestEnforceEnabledCommands_Paths(t *testing.T) {
+	parser, _, err := newParser("test")
+	if err != nil {
+		t.Fatalf("newParser: %v", err)
+	}
+
+	cases := []struct {
+		rules string
+		args  []string
+		ok    bool
+	}{
+		{"gmail", []string{"gmail", "search", "x"}, true},
+		{"gmail.search", []string{"gmail", "search", "x"}, true},
+		{"gmail.search", []string{"gmail", "send"}, false},
+		{"mail.search", []string{"gmail", "search", "x"}, true},
+		{"calendar.*", []string{"calendar", "events"}, true},
+		{"calendar.*", []string{"time", "now"}, false},
+		{"!drive.delete", []string{"drive", "ls"}, true},
+		{"!drive.delete", []string{"drive", "delete", "f1"}, false},
+		{"drive,!drive.delete", []string{"drive", "delete", "f1"}, false},
+		{"*,!gmail.send", []string{"gmail", "send"}, false},
+		{"all", []string{"gmail", "send"}, true},
+	}
+	for _, tc := range cases {
+		kctx, err := parser.Parse(tc.args)
+		if err != nil {
+			t.Fatalf("parse %v: %v", tc.args, err)
+		}
+		err = enforceEnabledCommands(kctx, tc.rules)
+		if (err == nil) != tc.ok {
+			t.Fatalf("%q %v: got %v, want ok=%v", tc.rules, tc.args, err, tc.ok)
+		}
+		if err != nil && ExitCode(err) != 2 {
+			t.Fatalf("%q %v: expected usage error, got %v", tc.rules, tc.args, err)
+		}
+	}
+
+	kctx, _ := parser.Parse([]string{"drive", "delete", "f1"})
+	if err := enforceEnabledCommands(kctx, "!drive.delete"); err == nil || !strings.Contains(err.Error(), `"drive.delete" is disabled`) {
+		t.Fatalf("unexpected deny error: %v", err)
+	}
+}
@@ -11,11 +11,13 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"google.golang.org/api/drive/v3"
 	"google.golang.org/api/option"
 
 	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/googleapi"
 	"github.com/steipete/gogcli/internal/outfmt"
 	"github.com/steipete/gogcli/internal/ui"
 )
@@ -414,3 +416,165 @@ func TestExecute_DriveDownload_GoogleSlides_PPTX(t *testing.T) {
 		t.Fatalf("file mismatch: err=%v body=%q", err, string(b))
 	}
 }
+
+func TestExecute_DriveLs_OutputFormatCSV(t *testing.T) {
+	origNew := newDriveService
+	t.Cleanup(func() { newDriveService = origNew })
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"files": []map[string]any{
+				{"id": "f1", "name": "Report, final", "mimeType": "application/pdf"},
+				{"id": "f2", "name": "Notes", "mimeType": "text/plain"},
+			},
+			"nextPageToken": "next",
+		})
+	}))
+	defer srv.Close()
+
+	svc, err := drive.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newDriveService = func(context.Context, string) (*drive.Service, error) { return svc, nil }
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--output-format", "csv", "--account", "a@b.com", "drive", "ls"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+
+	want := "id,mimeType,name\nf1,application/pdf,\"Report, final\"\nf2,text/plain,Notes\n"
+	if out != want {
+		t.Fatalf("unexpected csv:\n%s", out)
+	}
+}
+
+func TestExecute_OutputFormat_Invalid(t *testing.T) {
+	_ = captureStderr(t, func() {
+		err := Execute([]string{"--output-format", "xml", "--account", "a@b.com", "drive", "ls"})
+		if err == nil || ExitCode(err) != 2 {
+			t.Fatalf("expected usage error, got %v", err)
+		}
+	})
+}
+
+func TestExecute_Cache_Invalid(t *testing.T) {
+	_ = captureStderr(t, func() {
+		err := Execute([]string{"--cache", "forever", "--account", "a@b.com", "drive", "ls"})
+		if err == nil || ExitCode(err) != 2 {
+			t.Fatalf("expected usage error, got %v", err)
+		}
+	})
+}
+
+func TestExecute_DriveLs_Template(t *testing.T) {
+	origNew := newDriveService
+	t.Cleanup(func() { newDriveService = origNew })
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"files": []map[string]any{
+				{"id": "f1", "name": "Report", "modifiedTime": "2025-12-12T14:37:47Z"},
+				{"id": "f2", "name": "Notes", "modifiedTime": "2025-12-13T09:00:00Z"},
+			},
+		})
+	}))
+	defer srv.Close()
+
+	svc, err := drive.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newDriveService = func(context.Context, string) (*drive.Service, error) { return svc, nil }
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--template", `{{.id}}\t{{date .modifiedTime "2006-01-02"}}`, "--account", "a@b.com", "drive", "ls"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	if out != "f1\t2025-12-12\nf2\t2025-12-13\n" {
+		t.Fatalf("unexpected template output: %q", out)
+	}
+
+	out = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--select", "name", "--output-format", "csv", "--account", "a@b.com", "drive", "ls"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	if out != "name\nReport\nNotes\n" {
+		t.Fatalf("unexpected select output: %q", out)
+	}
+}
+
+func TestExecute_Template_PlainConflict(t *testing.T) {
+	_ = captureStderr(t, func() {
+		err := Execute([]string{"--plain", "--template", "{{.id}}", "--account", "a@b.com", "drive", "ls"})
+		if err == nil || ExitCode(err) != 2 {
+			t.Fatalf("expected usage error, got %v", err)
+		}
+	})
+}
+
+type circuitOpenTransport struct{}
+
+func (circuitOpenTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, &googleapi.CircuitBreakerError{Service: "drive", RetryAfter: 12 * time.Second}
+}
+
+func TestExecute_CircuitBreakerOpen_JSONError(t *testing.T) {
+	origNew := newDriveService
+	t.Cleanup(func() { newDriveService = origNew })
+
+	svc, err := drive.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(&http.Client{Transport: circuitOpenTransport{}}),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newDriveService = func(context.Context, string) (*drive.Service, error) { return svc, nil }
+
+	errOut := captureStderr(t, func() {
+		err := Execute([]string{"--json", "--account", "a@b.com", "drive", "get", "f1"})
+		if ExitCode(err) != 8 {
+			t.Fatalf("expected exit code 8, got %d (%v)", ExitCode(err), err)
+		}
+	})
+
+	var parsed struct {
+		Error struct {
+			Code              string `json:"code"`
+			Message           string `json:"message"`
+			ExitCode          int    `json:"exitCode"`
+			Service           string `json:"service"`
+			RetryAfterSeconds int    `json:"retryAfterSeconds"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(errOut), &parsed); err != nil {
+		t.Fatalf("json parse: %v\nstderr=%q", err, errOut)
+	}
+	e := parsed.Error
+	if e.Code != "circuit_open" || e.ExitCode != 8 || e.Service != "drive" || e.RetryAfterSeconds != 12 {
+		t.Fatalf("unexpected error envelope: %#v", e)
+	}
+	if e.Message != "circuit breaker for drive is open, too many recent failures - retry in 12s" {
+		t.Fatalf("unexpected message: %q", e.Message)
+	}
+}
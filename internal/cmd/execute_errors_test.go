@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestExecute_EarlyErrors_JSONEnvelope(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("GOG_JSON", "")
+	t.Setenv("GOG_OUTPUT_FORMAT", "")
+
+	cases := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"parse", []string{"--json", "nosuchcmd"}, "unexpected argument nosuchcmd"},
+		{"parse output-format", []string{"--output-format", "json", "nosuchcmd"}, "unexpected argument nosuchcmd"},
+		{"cache", []string{"--json", "--cache", "bogus", "time", "now"}, `invalid cache mode "bogus"`},
+		{"enable-commands", []string{"--json", "--enable-commands", "gmail", "time", "now"}, `"time.now" is not enabled`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			errOut := captureStderr(t, func() {
+				if err := Execute(tc.args); ExitCode(err) != 2 {
+					t.Fatalf("expected exit code 2, got %d (%v)", ExitCode(err), err)
+				}
+			})
+
+			var parsed struct {
+				Error struct {
+					Code    string `json:"code"`
+					Message string `json:"message"`
+				} `json:"error"`
+			}
+			if err := json.Unmarshal([]byte(errOut), &parsed); err != nil {
+				t.Fatalf("json parse: %v\nstderr=%q", err, errOut)
+			}
+			if parsed.Error.Code != "usage" || !strings.Contains(parsed.Error.Message, tc.want) {
+				t.Fatalf("unexpected envelope: %#v", parsed.Error)
+			}
+		})
+	}
+}
+
+func TestExecute_EarlyErrors_Text(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("GOG_JSON", "")
+	t.Setenv("GOG_OUTPUT_FORMAT", "")
+
+	for _, args := range [][]string{
+		{"--output-format", "bogus", "time", "now"},
+		{"--cache", "bogus", "time", "now"},
+	} {
+		errOut := captureStderr(t, func() {
+			if err := Execute(args); ExitCode(err) != 2 {
+				t.Fatalf("%v: expected exit code 2, got %d (%v)", args, ExitCode(err), err)
+			}
+		})
+		if !strings.Contains(errOut, "bogus") || strings.HasPrefix(errOut, "{") {
+			t.Fatalf("%v: expected a message, got %q", args, errOut)
+		}
+	}
+}
+
+func TestJSONRequestedInArgs(t *testing.T) {
+	t.Setenv("GOG_JSON", "")
+	t.Setenv("GOG_OUTPUT_FORMAT", "")
+
+	cases := map[string]bool{
+		"--json gmail":                true,
+		"--json=false gmail":          false,
+		"--output-format=yaml x":      true,
+		"--output-format tsv x":       false,
+		"--select id drive ls":        true,
+		"gmail search -- --json":      false,
+		"--json --plain gmail search": false,
+	}
+	for in, want := range cases {
+		if got := jsonRequestedInArgs(strings.Fields(in)); got != want {
+			t.Fatalf("%q: want %t, got %t", in, want, got)
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package cmd
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/steipete/gogcli/internal/errfmt"
+)
 
 type ExitError struct {
 	Code int
@@ -21,6 +25,15 @@ func (e *ExitError) Unwrap() error {
 	return e.Err
 }
 
+// silentExit reports an ExitError without a message, used when the failure
+// has already been reported (e.g. by a plugin).
+func silentExit(err error) bool {
+	var ee *ExitError
+	return errors.As(err, &ee) && ee != nil && ee.Err == nil
+}
+
+// ExitCode maps err to the process exit code: explicit ExitError codes win,
+// everything else goes through the errfmt taxonomy.
 func ExitCode(err error) int {
 	if err == nil {
 		return 0
@@ -32,5 +45,5 @@ func ExitCode(err error) int {
 		}
 		return ee.Code
 	}
-	return 1
+	return errfmt.ExitCode(err)
 }
@@ -3,6 +3,8 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
 	"regexp"
 	"strings"
@@ -57,12 +59,13 @@ type GmailSettingsCmd struct {
 }
 
 type GmailSearchCmd struct {
-	Query    []string `arg:"" name:"query" help:"Search query"`
-	Max      int64    `name:"max" aliases:"limit" help:"Max results" default:"10"`
-	Page     string   `name:"page" help:"Page token"`
-	Oldest   bool     `name:"oldest" help:"Show first message date instead of last"`
-	Timezone string   `name:"timezone" short:"z" help:"Output timezone (IANA name, e.g. America/New_York, UTC). Default: local"`
-	Local    bool     `name:"local" help:"Use local timezone (default behavior, useful to override --timezone)"`
+	Query    []string    `arg:"" name:"query" help:"Search query"`
+	Max      int64       `name:"max" aliases:"limit" help:"Max results" default:"10"`
+	Page     string      `name:"page" help:"Page token"`
+	Paging   PagingFlags `embed:""`
+	Oldest   bool        `name:"oldest" help:"Show first message date instead of last"`
+	Timezone string      `name:"timezone" short:"z" help:"Output timezone (IANA name, e.g. America/New_York, UTC). Default: local"`
+	Local    bool        `name:"local" help:"Use local timezone (default behavior, useful to override --timezone)"`
 }
 
 func (c *GmailSearchCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -81,16 +84,6 @@ func (c *GmailSearchCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 
-	resp, err := svc.Users.Threads.List("me").
-		Q(query).
-		MaxResults(c.Max).
-		PageToken(c.Page).
-		Context(ctx).
-		Do()
-	if err != nil {
-		return err
-	}
-
 	idToName, err := fetchLabelIDToName(svc)
 	if err != nil {
 		return err
@@ -101,16 +94,38 @@ func (c *GmailSearchCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 
-	// Fetch thread details concurrently (fixes N+1 query pattern)
-	items, err := fetchThreadDetails(ctx, svc, resp.Threads, idToName, c.Oldest, loc)
+	batch := openBatch(ctx, newGmailBatch, account)
+
+	fetch := func(pageToken string) ([]threadItem, string, error) {
+		resp, err := svc.Users.Threads.List("me").
+			Q(query).
+			MaxResults(c.Max).
+			PageToken(pageToken).
+			Context(ctx).
+			Do()
+		if err != nil {
+			return nil, "", err
+		}
+		// Fetch thread details concurrently (fixes N+1 query pattern)
+		items, err := fetchThreadDetails(ctx, svc, batch, resp.Threads, idToName, c.Oldest, loc)
+		if err != nil {
+			return nil, "", err
+		}
+		return items, resp.NextPageToken, nil
+	}
+	if c.Paging.AllPages {
+		return streamAllPages(ctx, c.Paging, c.Page, fetch, gmailThreadsTable)
+	}
+
+	items, nextPageToken, err := fetch(c.Page)
 	if err != nil {
 		return err
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"threads":       items,
-			"nextPageToken": resp.NextPageToken,
+			"nextPageToken": nextPageToken,
 		})
 	}
 
@@ -122,16 +137,24 @@ func (c *GmailSearchCmd) Run(ctx context.Context, flags *RootFlags) error {
 	w, flush := tableWriter(ctx)
 	defer flush()
 
-	fmt.Fprintln(w, "ID\tDATE\tFROM\tSUBJECT\tLABELS\tTHREAD")
+	fmt.Fprintln(w, gmailThreadsTable.header)
 	for _, it := range items {
+		gmailThreadsTable.row(w, it)
+	}
+	printNextPageHint(u, nextPageToken)
+	return nil
+}
+
+var gmailThreadsTable = pageTable[threadItem]{
+	header: "ID\tDATE\tFROM\tSUBJECT\tLABELS\tTHREAD",
+	row: func(w io.Writer, it threadItem) {
 		threadInfo := "-"
 		if it.MessageCount > 1 {
 			threadInfo = fmt.Sprintf("[%d msgs]", it.MessageCount)
 		}
 		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", it.ID, it.Date, it.From, it.Subject, strings.Join(it.Labels, ","), threadInfo)
-	}
-	printNextPageHint(u, resp.NextPageToken)
-	return nil
+	},
+	empty: "No results",
 }
 
 func firstMessage(t *gmail.Thread) *gmail.Message {
@@ -330,13 +353,17 @@ type threadItem struct {
 }
 
 // fetchThreadDetails fetches thread metadata concurrently with bounded parallelism.
-// This eliminates N+1 queries by fetching all threads in parallel.
+// This eliminates N+1 queries by fetching all threads in parallel, or in a
+// single batch call when batch is non-nil.
 // When oldest is false (default), the date shown is from the last message in the thread.
 // When oldest is true, the date shown is from the first message in the thread.
-func fetchThreadDetails(ctx context.Context, svc *gmail.Service, threads []*gmail.Thread, idToName map[string]string, oldest bool, loc *time.Location) ([]threadItem, error) {
+func fetchThreadDetails(ctx context.Context, svc *gmail.Service, batch *googleapi.BatchClient, threads []*gmail.Thread, idToName map[string]string, oldest bool, loc *time.Location) ([]threadItem, error) {
 	if len(threads) == 0 {
 		return nil, nil
 	}
+	if batch != nil && len(threads) > 1 {
+		return fetchThreadDetailsBatch(ctx, svc, batch, threads, idToName, oldest, loc)
+	}
 
 	const maxConcurrency = 10 // Limit parallel requests to avoid rate limiting
 	sem := make(chan struct{}, maxConcurrency)
@@ -378,32 +405,7 @@ func fetchThreadDetails(ctx context.Context, svc *gmail.Service, threads []*gmai
 				return
 			}
 
-			item := threadItem{ID: threadID, MessageCount: len(thread.Messages)}
-			if first := firstMessage(thread); first != nil {
-				item.From = sanitizeTab(headerValue(first.Payload, "From"))
-				item.Subject = sanitizeTab(headerValue(first.Payload, "Subject"))
-				if len(first.LabelIds) > 0 {
-					names := make([]string, 0, len(first.LabelIds))
-					for _, lid := range first.LabelIds {
-						if n, ok := idToName[lid]; ok {
-							names = append(names, n)
-						} else {
-							names = append(names, lid)
-						}
-					}
-					item.Labels = names
-				}
-			}
-			// Date from newest message by default, oldest if --oldest
-			dateMsg := newestMessageByDate(thread)
-			if oldest {
-				dateMsg = oldestMessageByDate(thread)
-			}
-			if dateMsg != nil {
-				item.Date = formatGmailDateInLocation(headerValue(dateMsg.Payload, "Date"), loc)
-			}
-
-			results <- result{index: idx, item: item}
+			results <- result{index: idx, item: newThreadItem(threadID, thread, idToName, oldest, loc)}
 		}(i, t.Id)
 	}
 
@@ -450,3 +452,54 @@ func fetchThreadDetails(ctx context.Context, svc *gmail.Service, threads []*gmai
 	}
 	return items, nil
 }
+
+func fetchThreadDetailsBatch(ctx context.Context, svc *gmail.Service, batch *googleapi.BatchClient, threads []*gmail.Thread, idToName map[string]string, oldest bool, loc *time.Location) ([]threadItem, error) {
+	ids := make([]string, 0, len(threads))
+	urls := make([]string, 0, len(threads))
+	query := url.Values{"format": {"metadata"}, "metadataHeaders": {"From", "Subject", "Date"}}
+	for _, t := range threads {
+		if t.Id == "" {
+			continue
+		}
+		ids = append(ids, t.Id)
+		urls = append(urls, batchURL(svc.BasePath, "gmail/v1/users/me/threads/"+url.PathEscape(t.Id), query))
+	}
+
+	got, errs := batchGetJSON[gmail.Thread](ctx, batch, urls)
+	items := make([]threadItem, 0, len(ids))
+	for i, id := range ids {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		items = append(items, newThreadItem(id, got[i], idToName, oldest, loc))
+	}
+	return items, nil
+}
+
+func newThreadItem(threadID string, thread *gmail.Thread, idToName map[string]string, oldest bool, loc *time.Location) threadItem {
+	item := threadItem{ID: threadID, MessageCount: len(thread.Messages)}
+	if first := firstMessage(thread); first != nil {
+		item.From = sanitizeTab(headerValue(first.Payload, "From"))
+		item.Subject = sanitizeTab(headerValue(first.Payload, "Subject"))
+		if len(first.LabelIds) > 0 {
+			names := make([]string, 0, len(first.LabelIds))
+			for _, lid := range first.LabelIds {
+				if n, ok := idToName[lid]; ok {
+					names = append(names, n)
+				} else {
+					names = append(names, lid)
+				}
+			}
+			item.Labels = names
+		}
+	}
+	// Date from newest message by default, oldest if --oldest
+	dateMsg := newestMessageByDate(thread)
+	if oldest {
+		dateMsg = oldestMessageByDate(thread)
+	}
+	if dateMsg != nil {
+		item.Date = formatGmailDateInLocation(headerValue(dateMsg.Payload, "Date"), loc)
+	}
+	return item
+}
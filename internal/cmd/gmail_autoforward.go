@@ -37,7 +37,7 @@ func (c *GmailAutoForwardGetCmd) Run(ctx context.Context, flags *RootFlags) erro
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"autoForwarding": autoForward})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"autoForwarding": autoForward})
 	}
 
 	u.Out().Printf("enabled\t%t", autoForward.Enabled)
@@ -116,7 +116,7 @@ func (c *GmailAutoForwardUpdateCmd) Run(ctx context.Context, kctx *kong.Context,
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"autoForwarding": updated})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"autoForwarding": updated})
 	}
 
 	u.Out().Println("Auto-forwarding settings updated successfully")
@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 
 	"google.golang.org/api/gmail/v1"
@@ -40,7 +41,7 @@ func (c *GmailBatchDeleteCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"deleted": c.MessageIDs,
 			"count":   len(c.MessageIDs),
 		})
@@ -82,6 +83,9 @@ func (c *GmailBatchModifyCmd) Run(ctx context.Context, flags *RootFlags) error {
 	addIDs := resolveLabelIDs(addLabels, idMap)
 	removeIDs := resolveLabelIDs(removeLabels, idMap)
 
+	// The previous labels are only needed for the undo journal.
+	before, beforeErr := snapshotMessageLabels(ctx, svc, openBatch(ctx, newGmailBatch, account), c.MessageIDs)
+
 	err = svc.Users.Messages.BatchModify("me", &gmail.BatchModifyMessagesRequest{
 		Ids:            c.MessageIDs,
 		AddLabelIds:    addIDs,
@@ -90,9 +94,13 @@ func (c *GmailBatchModifyCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if err != nil {
 		return err
 	}
+	if beforeErr == nil {
+		recordUndo(flags, account, undoKindGmailMessagesLabels, fmt.Sprintf("gmail batch modify (%d messages)", len(c.MessageIDs)),
+			gmailLabelsInverse(c.MessageIDs, before, addIDs, removeIDs))
+	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"modified":      c.MessageIDs,
 			"count":         len(c.MessageIDs),
 			"addedLabels":   addIDs,
@@ -62,7 +62,7 @@ func (c *GmailDraftsListCmd) Run(ctx context.Context, flags *RootFlags) error {
 			}
 			items = append(items, item{ID: d.Id, MessageID: msgID, ThreadID: threadID})
 		}
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"drafts":        items,
 			"nextPageToken": resp.NextPageToken,
 		})
@@ -113,7 +113,7 @@ func (c *GmailDraftsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 	if draft.Message == nil {
 		if outfmt.IsJSON(ctx) {
-			return outfmt.WriteJSON(os.Stdout, map[string]any{"draft": draft})
+			return outfmt.Write(ctx, os.Stdout, map[string]any{"draft": draft})
 		}
 		u.Err().Println("Empty draft")
 		return nil
@@ -133,7 +133,7 @@ func (c *GmailDraftsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 			}
 			out["downloaded"] = attachmentDownloadDraftOutputs(downloads)
 		}
-		return outfmt.WriteJSON(os.Stdout, out)
+		return outfmt.Write(ctx, os.Stdout, out)
 	}
 
 	u.Out().Printf("Draft-ID: %s", draft.Id)
@@ -201,7 +201,7 @@ func (c *GmailDraftsDeleteCmd) Run(ctx context.Context, flags *RootFlags) error
 		return err
 	}
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"deleted": true, "draftId": draftID})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"deleted": true, "draftId": draftID})
 	}
 	u.Out().Printf("deleted\ttrue")
 	u.Out().Printf("draft_id\t%s", draftID)
@@ -223,17 +223,38 @@ func (c *GmailDraftsSendCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return usage("empty draftId")
 	}
 
+	guard, err := loadOutboundGuard(account)
+	if err != nil {
+		return err
+	}
+
 	svc, err := newGmailService(ctx, account)
 	if err != nil {
 		return err
 	}
 
+	if guard != nil {
+		draft, getErr := svc.Users.Drafts.Get("me", draftID).Format("metadata").Do()
+		if getErr != nil {
+			return getErr
+		}
+		var recipients []string
+		if draft.Message != nil {
+			for _, h := range []string{"To", "Cc", "Bcc"} {
+				recipients = append(recipients, headerValue(draft.Message.Payload, h))
+			}
+		}
+		if err := guard.Check(ctx, flags, outboundAction{Action: "send draft", Recipients: recipients}); err != nil {
+			return err
+		}
+	}
+
 	msg, err := svc.Users.Drafts.Send("me", &gmail.Draft{Id: draftID}).Do()
 	if err != nil {
 		return err
 	}
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"messageId": msg.Id,
 			"threadId":  msg.ThreadId,
 		})
@@ -348,7 +369,7 @@ func writeDraftResult(ctx context.Context, u *ui.UI, draft *gmail.Draft, threadI
 		threadID = draft.Message.ThreadId
 	}
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"draftId":  draft.Id,
 			"message":  draft.Message,
 			"threadId": threadID,
@@ -40,7 +40,7 @@ func (c *GmailForwardingListCmd) Run(ctx context.Context, flags *RootFlags) erro
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"forwardingAddresses": resp.ForwardingAddresses})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"forwardingAddresses": resp.ForwardingAddresses})
 	}
 
 	if len(resp.ForwardingAddresses) == 0 {
@@ -85,7 +85,7 @@ func (c *GmailForwardingGetCmd) Run(ctx context.Context, flags *RootFlags) error
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"forwardingAddress": address})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"forwardingAddress": address})
 	}
 
 	u.Out().Printf("forwarding_email\t%s", address.ForwardingEmail)
@@ -123,7 +123,7 @@ func (c *GmailForwardingCreateCmd) Run(ctx context.Context, flags *RootFlags) er
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"forwardingAddress": created})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"forwardingAddress": created})
 	}
 
 	u.Out().Println("Forwarding address created successfully")
@@ -160,7 +160,7 @@ func (c *GmailForwardingDeleteCmd) Run(ctx context.Context, flags *RootFlags) er
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"success":         true,
 			"forwardingEmail": forwardingEmail,
 		})
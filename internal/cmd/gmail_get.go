@@ -96,7 +96,7 @@ func (c *GmailGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 				payload["attachments"] = attachmentOutputs(attachments)
 			}
 		}
-		return outfmt.WriteJSON(os.Stdout, payload)
+		return outfmt.Write(ctx, os.Stdout, payload)
 	}
 
 	u.Out().Printf("id\t%s", msg.Id)
@@ -46,7 +46,7 @@ func (c *GmailHistoryCmd) Run(ctx context.Context, flags *RootFlags) error {
 
 	ids := collectHistoryMessageIDs(resp)
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"historyId":     formatHistoryID(resp.HistoryId),
 			"messages":      ids,
 			"nextPageToken": resp.NextPageToken,
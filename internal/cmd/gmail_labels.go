@@ -52,7 +52,7 @@ func (c *GmailLabelsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"label": l})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"label": l})
 	}
 	u := ui.FromContext(ctx)
 	u.Out().Printf("id\t%s", l.Id)
@@ -97,7 +97,7 @@ func (c *GmailLabelsCreateCmd) Run(ctx context.Context, flags *RootFlags) error
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"label": label})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"label": label})
 	}
 	u.Out().Printf("Created label: %s (id: %s)", label.Name, label.Id)
 	return nil
@@ -130,7 +130,7 @@ func (c *GmailLabelsListCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"labels": resp.Labels})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"labels": resp.Labels})
 	}
 	if len(resp.Labels) == 0 {
 		u.Err().Println("No labels")
@@ -203,7 +203,7 @@ func (c *GmailLabelsModifyCmd) Run(ctx context.Context, flags *RootFlags) error
 		}
 	}
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"results": results})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"results": results})
 	}
 	return nil
 }
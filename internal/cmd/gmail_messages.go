@@ -3,6 +3,8 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
 	"strings"
 	"sync"
@@ -10,6 +12,7 @@ import (
 
 	"google.golang.org/api/gmail/v1"
 
+	"github.com/steipete/gogcli/internal/googleapi"
 	"github.com/steipete/gogcli/internal/outfmt"
 	"github.com/steipete/gogcli/internal/ui"
 )
@@ -21,12 +24,13 @@ type GmailMessagesCmd struct {
 }
 
 type GmailMessagesSearchCmd struct {
-	Query       []string `arg:"" name:"query" help:"Search query"`
-	Max         int64    `name:"max" aliases:"limit" help:"Max results" default:"10"`
-	Page        string   `name:"page" help:"Page token"`
-	Timezone    string   `name:"timezone" short:"z" help:"Output timezone (IANA name, e.g. America/New_York, UTC). Default: local"`
-	Local       bool     `name:"local" help:"Use local timezone (default behavior, useful to override --timezone)"`
-	IncludeBody bool     `name:"include-body" help:"Include decoded message body (JSON is full; text output is truncated)"`
+	Query       []string    `arg:"" name:"query" help:"Search query"`
+	Max         int64       `name:"max" aliases:"limit" help:"Max results" default:"10"`
+	Page        string      `name:"page" help:"Page token"`
+	Paging      PagingFlags `embed:""`
+	Timezone    string      `name:"timezone" short:"z" help:"Output timezone (IANA name, e.g. America/New_York, UTC). Default: local"`
+	Local       bool        `name:"local" help:"Use local timezone (default behavior, useful to override --timezone)"`
+	IncludeBody bool        `name:"include-body" help:"Include decoded message body (JSON is full; text output is truncated)"`
 }
 
 func (c *GmailMessagesSearchCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -45,17 +49,6 @@ func (c *GmailMessagesSearchCmd) Run(ctx context.Context, flags *RootFlags) erro
 		return err
 	}
 
-	resp, err := svc.Users.Messages.List("me").
-		Q(query).
-		MaxResults(c.Max).
-		PageToken(c.Page).
-		Fields("messages(id,threadId),nextPageToken").
-		Context(ctx).
-		Do()
-	if err != nil {
-		return err
-	}
-
 	idToName, err := fetchLabelIDToName(svc)
 	if err != nil {
 		return err
@@ -66,15 +59,39 @@ func (c *GmailMessagesSearchCmd) Run(ctx context.Context, flags *RootFlags) erro
 		return err
 	}
 
-	items, err := fetchMessageDetails(ctx, svc, resp.Messages, idToName, loc, c.IncludeBody)
+	batch := openBatch(ctx, newGmailBatch, account)
+
+	fetch := func(pageToken string) ([]messageItem, string, error) {
+		resp, err := svc.Users.Messages.List("me").
+			Q(query).
+			MaxResults(c.Max).
+			PageToken(pageToken).
+			Fields("messages(id,threadId),nextPageToken").
+			Context(ctx).
+			Do()
+		if err != nil {
+			return nil, "", err
+		}
+		items, err := fetchMessageDetails(ctx, svc, batch, resp.Messages, idToName, loc, c.IncludeBody)
+		if err != nil {
+			return nil, "", err
+		}
+		return items, resp.NextPageToken, nil
+	}
+	table := gmailMessagesTable(c.IncludeBody)
+	if c.Paging.AllPages {
+		return streamAllPages(ctx, c.Paging, c.Page, fetch, table)
+	}
+
+	items, nextPageToken, err := fetch(c.Page)
 	if err != nil {
 		return err
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"messages":      items,
-			"nextPageToken": resp.NextPageToken,
+			"nextPageToken": nextPageToken,
 		})
 	}
 
@@ -86,24 +103,31 @@ func (c *GmailMessagesSearchCmd) Run(ctx context.Context, flags *RootFlags) erro
 	w, flush := tableWriter(ctx)
 	defer flush()
 
-	if c.IncludeBody {
-		fmt.Fprintln(w, "ID\tTHREAD\tDATE\tFROM\tSUBJECT\tLABELS\tBODY")
-	} else {
-		fmt.Fprintln(w, "ID\tTHREAD\tDATE\tFROM\tSUBJECT\tLABELS")
-	}
+	fmt.Fprintln(w, table.header)
 	for _, it := range items {
-		body := ""
-		if c.IncludeBody {
-			body = sanitizeMessageBody(it.Body)
+		table.row(w, it)
+	}
+	printNextPageHint(u, nextPageToken)
+	return nil
+}
+
+func gmailMessagesTable(includeBody bool) pageTable[messageItem] {
+	if includeBody {
+		return pageTable[messageItem]{
+			header: "ID\tTHREAD\tDATE\tFROM\tSUBJECT\tLABELS\tBODY",
+			row: func(w io.Writer, it messageItem) {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", it.ID, it.ThreadID, it.Date, it.From, it.Subject, strings.Join(it.Labels, ","), sanitizeMessageBody(it.Body))
+			},
+			empty: "No results",
 		}
-		if c.IncludeBody {
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", it.ID, it.ThreadID, it.Date, it.From, it.Subject, strings.Join(it.Labels, ","), body)
-		} else {
+	}
+	return pageTable[messageItem]{
+		header: "ID\tTHREAD\tDATE\tFROM\tSUBJECT\tLABELS",
+		row: func(w io.Writer, it messageItem) {
 			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", it.ID, it.ThreadID, it.Date, it.From, it.Subject, strings.Join(it.Labels, ","))
-		}
+		},
+		empty: "No results",
 	}
-	printNextPageHint(u, resp.NextPageToken)
-	return nil
 }
 
 type messageItem struct {
@@ -116,10 +140,13 @@ type messageItem struct {
 	Body     string   `json:"body,omitempty"`
 }
 
-func fetchMessageDetails(ctx context.Context, svc *gmail.Service, messages []*gmail.Message, idToName map[string]string, loc *time.Location, includeBody bool) ([]messageItem, error) {
+func fetchMessageDetails(ctx context.Context, svc *gmail.Service, batch *googleapi.BatchClient, messages []*gmail.Message, idToName map[string]string, loc *time.Location, includeBody bool) ([]messageItem, error) {
 	if len(messages) == 0 {
 		return nil, nil
 	}
+	if batch != nil && len(messages) > 1 {
+		return fetchMessageDetailsBatch(ctx, svc, batch, messages, idToName, loc, includeBody)
+	}
 
 	const maxConcurrency = 10
 	sem := make(chan struct{}, maxConcurrency)
@@ -164,31 +191,7 @@ func fetchMessageDetails(ctx context.Context, svc *gmail.Service, messages []*gm
 				return
 			}
 
-			item := messageItem{
-				ID:       messageID,
-				ThreadID: msg.ThreadId,
-			}
-
-			item.From = sanitizeTab(headerValue(msg.Payload, "From"))
-			item.Subject = sanitizeTab(headerValue(msg.Payload, "Subject"))
-			item.Date = formatGmailDateInLocation(headerValue(msg.Payload, "Date"), loc)
-			if includeBody {
-				item.Body = bestBodyText(msg.Payload)
-			}
-
-			if len(msg.LabelIds) > 0 {
-				names := make([]string, 0, len(msg.LabelIds))
-				for _, lid := range msg.LabelIds {
-					if n, ok := idToName[lid]; ok {
-						names = append(names, n)
-					} else {
-						names = append(names, lid)
-					}
-				}
-				item.Labels = names
-			}
-
-			results <- result{index: idx, messageID: messageID, item: item}
+			results <- result{index: idx, messageID: messageID, item: newMessageItem(messageID, msg, idToName, loc, includeBody)}
 		}(i, m.Id)
 	}
 
@@ -222,6 +225,64 @@ func fetchMessageDetails(ctx context.Context, svc *gmail.Service, messages []*gm
 	return items, nil
 }
 
+func fetchMessageDetailsBatch(ctx context.Context, svc *gmail.Service, batch *googleapi.BatchClient, messages []*gmail.Message, idToName map[string]string, loc *time.Location, includeBody bool) ([]messageItem, error) {
+	query := url.Values{"format": {"full"}}
+	if !includeBody {
+		query = url.Values{
+			"format":          {"metadata"},
+			"metadataHeaders": {"From", "Subject", "Date"},
+			"fields":          {"id,threadId,labelIds,payload(headers)"},
+		}
+	}
+
+	ids := make([]string, 0, len(messages))
+	urls := make([]string, 0, len(messages))
+	for _, m := range messages {
+		if m == nil || m.Id == "" {
+			continue
+		}
+		ids = append(ids, m.Id)
+		urls = append(urls, batchURL(svc.BasePath, "gmail/v1/users/me/messages/"+url.PathEscape(m.Id), query))
+	}
+
+	got, errs := batchGetJSON[gmail.Message](ctx, batch, urls)
+	items := make([]messageItem, 0, len(ids))
+	for i, id := range ids {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("message %s: %w", id, errs[i])
+		}
+		items = append(items, newMessageItem(id, got[i], idToName, loc, includeBody))
+	}
+	return items, nil
+}
+
+func newMessageItem(messageID string, msg *gmail.Message, idToName map[string]string, loc *time.Location, includeBody bool) messageItem {
+	item := messageItem{
+		ID:       messageID,
+		ThreadID: msg.ThreadId,
+	}
+
+	item.From = sanitizeTab(headerValue(msg.Payload, "From"))
+	item.Subject = sanitizeTab(headerValue(msg.Payload, "Subject"))
+	item.Date = formatGmailDateInLocation(headerValue(msg.Payload, "Date"), loc)
+	if includeBody {
+		item.Body = bestBodyText(msg.Payload)
+	}
+
+	if len(msg.LabelIds) > 0 {
+		names := make([]string, 0, len(msg.LabelIds))
+		for _, lid := range msg.LabelIds {
+			if n, ok := idToName[lid]; ok {
+				names = append(names, n)
+			} else {
+				names = append(names, lid)
+			}
+		}
+		item.Labels = names
+	}
+	return item
+}
+
 func sanitizeMessageBody(body string) string {
 	if body == "" {
 		return ""
@@ -40,6 +40,9 @@ func (c *GmailMessagesForwardCmd) Run(ctx context.Context, flags *RootFlags) err
 	if to == "" {
 		return usage("--to is required")
 	}
+	if err := checkOutbound(ctx, flags, account, outboundAction{Action: "forward mail", Recipients: []string{to}}); err != nil {
+		return err
+	}
 
 	svc, err := newGmailService(ctx, account)
 	if err != nil {
@@ -120,7 +123,7 @@ func (c *GmailMessagesForwardCmd) Run(ctx context.Context, flags *RootFlags) err
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"sent":        sent.Id,
 			"threadId":    sent.ThreadId,
 			"to":          to,
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 	"os"
 
 	"google.golang.org/api/gmail/v1"
@@ -63,6 +64,9 @@ func (c *GmailMessagesModifyCmd) Run(ctx context.Context, flags *RootFlags) erro
 		}
 	}
 
+	// The previous labels are only needed for the undo journal.
+	before, beforeErr := snapshotMessageLabels(ctx, svc, openBatch(ctx, newGmailBatch, account), c.IDs)
+
 	err = svc.Users.Messages.BatchModify("me", &gmail.BatchModifyMessagesRequest{
 		Ids:            c.IDs,
 		AddLabelIds:    addIDs,
@@ -71,9 +75,13 @@ func (c *GmailMessagesModifyCmd) Run(ctx context.Context, flags *RootFlags) erro
 	if err != nil {
 		return err
 	}
+	if beforeErr == nil {
+		recordUndo(flags, account, undoKindGmailMessagesLabels, fmt.Sprintf("gmail messages modify (%d messages)", len(c.IDs)),
+			gmailLabelsInverse(c.IDs, before, addIDs, removeIDs))
+	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"modified":      c.IDs,
 			"count":         len(c.IDs),
 			"addedLabels":   addIDs,
@@ -159,6 +159,12 @@ func (c *GmailSendCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	bccRecipients := splitCSV(c.Bcc)
+	if err := checkOutbound(ctx, flags, account, outboundAction{
+		Action:     "send mail",
+		Recipients: append(append(append([]string{}, toRecipients...), ccRecipients...), bccRecipients...),
+	}); err != nil {
+		return err
+	}
 
 	atts := make([]mailAttachment, 0, len(c.Attach))
 	for _, p := range c.Attach {
@@ -324,7 +330,7 @@ func writeSendResults(ctx context.Context, u *ui.UI, fromAddr string, results []
 			if results[0].TrackingID != "" {
 				resp["tracking_id"] = results[0].TrackingID
 			}
-			return outfmt.WriteJSON(os.Stdout, resp)
+			return outfmt.Write(ctx, os.Stdout, resp)
 		}
 
 		items := make([]map[string]any, 0, len(results))
@@ -342,7 +348,7 @@ func writeSendResults(ctx context.Context, u *ui.UI, fromAddr string, results []
 			}
 			items = append(items, item)
 		}
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"messages": items})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"messages": items})
 	}
 
 	if len(results) == 1 {
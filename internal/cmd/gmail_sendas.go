@@ -46,7 +46,7 @@ func (c *GmailSendAsListCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"sendAs": resp.SendAs})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"sendAs": resp.SendAs})
 	}
 
 	if len(resp.SendAs) == 0 {
@@ -102,7 +102,7 @@ func (c *GmailSendAsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"sendAs": sa})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"sendAs": sa})
 	}
 
 	u.Out().Printf("send_as_email\t%s", sa.SendAsEmail)
@@ -154,7 +154,7 @@ func (c *GmailSendAsCreateCmd) Run(ctx context.Context, flags *RootFlags) error
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"sendAs": created})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"sendAs": created})
 	}
 
 	u.Out().Printf("send_as_email\t%s", created.SendAsEmail)
@@ -189,7 +189,7 @@ func (c *GmailSendAsVerifyCmd) Run(ctx context.Context, flags *RootFlags) error
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"email":   sendAsEmail,
 			"message": "Verification email sent",
 		})
@@ -225,7 +225,7 @@ func (c *GmailSendAsDeleteCmd) Run(ctx context.Context, flags *RootFlags) error
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"email":   sendAsEmail,
 			"deleted": true,
 		})
@@ -289,7 +289,7 @@ func (c *GmailSendAsUpdateCmd) Run(ctx context.Context, kctx *kong.Context, flag
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"sendAs": updated})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"sendAs": updated})
 	}
 
 	u.Out().Printf("Updated send-as alias: %s", updated.SendAsEmail)
@@ -108,7 +108,7 @@ func (c *GmailThreadGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 				downloadedFiles = append(downloadedFiles, attachmentDownloadSummaries(downloads)...)
 			}
 		}
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"thread":     thread,
 			"downloaded": downloadedFiles,
 		})
@@ -209,6 +209,9 @@ func (c *GmailThreadModifyCmd) Run(ctx context.Context, flags *RootFlags) error
 	addIDs := resolveLabelIDs(addLabels, idMap)
 	removeIDs := resolveLabelIDs(removeLabels, idMap)
 
+	// The previous labels are only needed for the undo journal.
+	messageIDs, before, beforeErr := snapshotThreadLabels(ctx, svc, threadID)
+
 	// Use Gmail's Threads.Modify API
 	_, err = svc.Users.Threads.Modify("me", threadID, &gmail.ModifyThreadRequest{
 		AddLabelIds:    addIDs,
@@ -217,9 +220,13 @@ func (c *GmailThreadModifyCmd) Run(ctx context.Context, flags *RootFlags) error
 	if err != nil {
 		return err
 	}
+	if beforeErr == nil {
+		recordUndo(flags, account, undoKindGmailThreadLabels, fmt.Sprintf("gmail thread modify %s", threadID),
+			gmailLabelsInverse(messageIDs, before, addIDs, removeIDs))
+	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"modified":      threadID,
 			"addedLabels":   addIDs,
 			"removedLabels": removeIDs,
@@ -260,7 +267,7 @@ func (c *GmailThreadAttachmentsCmd) Run(ctx context.Context, flags *RootFlags) e
 
 	if thread == nil || len(thread.Messages) == 0 {
 		if outfmt.IsJSON(ctx) {
-			return outfmt.WriteJSON(os.Stdout, map[string]any{
+			return outfmt.Write(ctx, os.Stdout, map[string]any{
 				"threadId":    threadID,
 				"attachments": []any{},
 			})
@@ -300,7 +307,7 @@ func (c *GmailThreadAttachmentsCmd) Run(ctx context.Context, flags *RootFlags) e
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"threadId":    threadID,
 			"attachments": allAttachments,
 		})
@@ -344,7 +351,7 @@ func (c *GmailURLCmd) Run(ctx context.Context, flags *RootFlags) error {
 				"url": fmt.Sprintf("https://mail.google.com/mail/?authuser=%s#all/%s", url.QueryEscape(account), id),
 			})
 		}
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"urls": urls})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"urls": urls})
 	}
 	for _, id := range c.ThreadIDs {
 		threadURL := fmt.Sprintf("https://mail.google.com/mail/?authuser=%s#all/%s", url.QueryEscape(account), id)
@@ -72,7 +72,7 @@ func (c *GmailTrackOpensCmd) queryByTrackingID(ctx context.Context, cfg *trackin
 		if err := json.Unmarshal(body, &anyJSON); err != nil {
 			return fmt.Errorf("decode response: %w", err)
 		}
-		return outfmt.WriteJSON(os.Stdout, anyJSON)
+		return outfmt.Write(ctx, os.Stdout, anyJSON)
 	}
 
 	var result struct {
@@ -171,7 +171,7 @@ func (c *GmailTrackOpensCmd) queryAdmin(ctx context.Context, cfg *tracking.Confi
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, result)
+		return outfmt.Write(ctx, os.Stdout, result)
 	}
 
 	if len(result.Opens) == 0 {
@@ -38,7 +38,7 @@ func (c *GmailVacationGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"vacation": vacation})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"vacation": vacation})
 	}
 
 	u.Out().Printf("enable_auto_reply\t%t", vacation.EnableAutoReply)
@@ -144,7 +144,7 @@ func (c *GmailVacationUpdateCmd) Run(ctx context.Context, kctx *kong.Context, fl
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"vacation": updated})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"vacation": updated})
 	}
 
 	u.Out().Println("Vacation responder updated successfully")
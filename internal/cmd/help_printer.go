@@ -47,11 +47,27 @@ func helpPrinter(options kong.HelpOptions, ctx *kong.Context) error {
 
 	out := rewriteCommandSummaries(buf.String(), ctx.Selected())
 	out = injectBuildLine(out)
+	if ctx.Selected() == nil {
+		out = injectHelpSection(out, aliasHelp())
+		out = injectHelpSection(out, pluginHelp(discoverPlugins()))
+	}
 	out = colorizeHelp(out, helpProfile(origStdout, helpColorMode(ctx.Args)))
 	_, err := io.WriteString(origStdout, out)
 	return err
 }
 
+// injectHelpSection adds a section (aliases, plugins) before the closing
+// "Run ..." hint.
+func injectHelpSection(out string, section string) string {
+	if section == "" {
+		return out
+	}
+	if i := strings.LastIndex(out, "\nRun \""); i >= 0 {
+		return out[:i+1] + section + "\n" + out[i+1:]
+	}
+	return out + "\n" + section
+}
+
 func injectBuildLine(out string) string {
 	v := strings.TrimSpace(version)
 	if v == "" {
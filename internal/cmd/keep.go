@@ -29,9 +29,10 @@ type KeepCmd struct {
 }
 
 type KeepListCmd struct {
-	Max    int64  `name:"max" help:"Max results" default:"100"`
-	Page   string `name:"page" help:"Page token"`
-	Filter string `name:"filter" help:"Filter expression (e.g. 'create_time > \"2024-01-01T00:00:00Z\"')"`
+	Max    int64       `name:"max" help:"Max results" default:"100"`
+	Page   string      `name:"page" help:"Page token"`
+	Paging PagingFlags `embed:""`
+	Filter string      `name:"filter" help:"Filter expression (e.g. 'create_time > \"2024-01-01T00:00:00Z\"')"`
 }
 
 func (c *KeepListCmd) Run(ctx context.Context, flags *RootFlags, keep *KeepCmd) error {
@@ -42,41 +43,60 @@ func (c *KeepListCmd) Run(ctx context.Context, flags *RootFlags, keep *KeepCmd)
 		return err
 	}
 
-	call := svc.Notes.List().PageSize(c.Max).PageToken(c.Page)
+	fetch := func(pageToken string) ([]*keepapi.Note, string, error) {
+		call := svc.Notes.List().PageSize(c.Max).PageToken(pageToken)
+
+		if c.Filter != "" {
+			call = call.Filter(c.Filter)
+		}
 
-	if c.Filter != "" {
-		call = call.Filter(c.Filter)
+		resp, err := call.Do()
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Notes, resp.NextPageToken, nil
+	}
+	if c.Paging.AllPages {
+		return streamAllPages(ctx, c.Paging, c.Page, fetch, keepNotesTable)
 	}
 
-	resp, err := call.Do()
+	notes, nextPageToken, err := fetch(c.Page)
 	if err != nil {
 		return err
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
-			"notes":         resp.Notes,
-			"nextPageToken": resp.NextPageToken,
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
+			"notes":         notes,
+			"nextPageToken": nextPageToken,
 		})
 	}
 
-	if len(resp.Notes) == 0 {
+	if len(notes) == 0 {
 		u.Err().Println("No notes")
 		return nil
 	}
 
 	w, flush := tableWriter(ctx)
 	defer flush()
-	fmt.Fprintln(w, "NAME\tTITLE\tUPDATED")
-	for _, n := range resp.Notes {
+	fmt.Fprintln(w, keepNotesTable.header)
+	for _, n := range notes {
+		keepNotesTable.row(w, n)
+	}
+	printNextPageHint(u, nextPageToken)
+	return nil
+}
+
+var keepNotesTable = pageTable[*keepapi.Note]{
+	header: "NAME\tTITLE\tUPDATED",
+	row: func(w io.Writer, n *keepapi.Note) {
 		title := n.Title
 		if title == "" {
 			title = noteSnippet(n)
 		}
 		fmt.Fprintf(w, "%s\t%s\t%s\n", n.Name, title, n.UpdateTime)
-	}
-	printNextPageHint(u, resp.NextPageToken)
-	return nil
+	},
+	empty: "No notes",
 }
 
 func noteSnippet(n *keepapi.Note) string {
@@ -144,7 +164,7 @@ func (c *KeepSearchCmd) Run(ctx context.Context, flags *RootFlags, keep *KeepCmd
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"notes": allNotes,
 			"query": c.Query,
 			"count": len(allNotes),
@@ -193,7 +213,7 @@ func (c *KeepGetCmd) Run(ctx context.Context, flags *RootFlags, keep *KeepCmd) e
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"note": note})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"note": note})
 	}
 
 	u.Out().Printf("name\t%s", note.Name)
@@ -264,7 +284,7 @@ func (c *KeepAttachmentCmd) Run(ctx context.Context, flags *RootFlags, keep *Kee
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"downloaded": true,
 			"path":       outPath,
 			"bytes":      written,
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/errfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// outboundGuard applies policy.outbound from config.json to one account. A
+// nil guard (no policy) allows everything.
+type outboundGuard struct {
+	policy  config.OutboundPolicy
+	account string
+}
+
+// outboundAction is one outbound operation: mail, a chat message, event
+// invitations or a Drive permission.
+type outboundAction struct {
+	Action     string   // e.g. "send mail"
+	Recipients []string // addresses, "Name <addr>" and comma lists are fine
+	Public     bool     // public "anyone" Drive link
+}
+
+func loadOutboundGuard(account string) (*outboundGuard, error) {
+	policy, err := loadPolicy()
+	if err != nil {
+		return nil, err
+	}
+	if policy.Outbound == nil {
+		return nil, nil //nolint:nilnil // nil guard means no outbound policy
+	}
+	return &outboundGuard{policy: *policy.Outbound, account: account}, nil
+}
+
+// checkOutbound loads the policy for account and checks a.
+func checkOutbound(ctx context.Context, flags *RootFlags, account string, a outboundAction) error {
+	guard, err := loadOutboundGuard(account)
+	if err != nil {
+		return err
+	}
+	return guard.Check(ctx, flags, a)
+}
+
+// Check fails with a UserFacingError when a violates the policy, and asks
+// for confirmation before reaching external domains if the policy wants
+// that. --force overrides both only with allow_force.
+func (g *outboundGuard) Check(ctx context.Context, flags *RootFlags, a outboundAction) error {
+	if g == nil {
+		return nil
+	}
+	forced := flags.Force && g.policy.AllowForce
+	forceHint := " (--force is not allowed by policy)"
+	if g.policy.AllowForce {
+		forceHint = " (use --force to override)"
+	}
+
+	recipients := parseEmailAddresses(strings.Join(a.Recipients, ","))
+	if violations := g.violations(recipients, a.Public); len(violations) > 0 {
+		if !forced {
+			return errfmt.NewUserFacingError(fmt.Sprintf("outbound policy blocks %s: %s%s", a.Action, strings.Join(violations, "; "), forceHint), nil)
+		}
+		ui.FromContext(ctx).Err().Printf("warning: outbound policy overridden by --force: %s", strings.Join(violations, "; "))
+	}
+
+	if !g.policy.ConfirmExternal || forced || flags.DryRun {
+		return nil
+	}
+	external := g.external(recipients)
+	if len(external) == 0 {
+		return nil
+	}
+	action := fmt.Sprintf("%s to external recipients %s", a.Action, strings.Join(external, ", "))
+	if flags.NoInput || !term.IsTerminal(int(os.Stdin.Fd())) {
+		return errfmt.NewUserFacingError(fmt.Sprintf("outbound policy requires confirmation to %s (non-interactive)%s", action, forceHint), nil)
+	}
+	return promptConfirm(ctx, action)
+}
+
+func (g *outboundGuard) violations(recipients []string, public bool) []string {
+	var out []string
+	if public && g.policy.BlockPublicLinks {
+		out = append(out, `public "anyone" links are not allowed`)
+	}
+	if limit := g.policy.MaxRecipients; limit > 0 && len(recipients) > limit {
+		out = append(out, fmt.Sprintf("%d recipients exceed the maximum of %d", len(recipients), limit))
+	}
+	for _, r := range recipients {
+		domain := emailDomain(r)
+		switch {
+		case matchesDomain(domain, g.policy.BlockedDomains):
+			out = append(out, fmt.Sprintf("%s is in a blocked domain", r))
+		case len(g.policy.AllowedDomains) > 0 && !matchesDomain(domain, g.policy.AllowedDomains):
+			out = append(out, fmt.Sprintf("%s is not in an allowed domain", r))
+		}
+	}
+	return out
+}
+
+func (g *outboundGuard) external(recipients []string) []string {
+	internal := g.policy.InternalDomains
+	if len(internal) == 0 {
+		internal = []string{emailDomain(g.account)}
+	}
+	var out []string
+	for _, r := range recipients {
+		if !matchesDomain(emailDomain(r), internal) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func emailDomain(email string) string {
+	_, domain, _ := strings.Cut(strings.ToLower(strings.TrimSpace(email)), "@")
+	return domain
+}
+
+// matchesDomain reports whether domain is one of domains or a subdomain of
+// one; entries may be written as "example.com", "@example.com" or
+// "*.example.com".
+func matchesDomain(domain string, domains []string) bool {
+	if domain == "" {
+		return false
+	}
+	for _, d := range domains {
+		d = strings.ToLower(strings.TrimSpace(d))
+		d = strings.TrimPrefix(strings.TrimPrefix(d, "*."), "@")
+		if d != "" && (domain == d || strings.HasSuffix(domain, "."+d)) {
+			return true
+		}
+	}
+	return false
+}
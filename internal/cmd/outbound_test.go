@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+func TestOutboundGuard_Check(t *testing.T) {
+	guard := &outboundGuard{account: "me@corp.com", policy: config.OutboundPolicy{
+		AllowedDomains:   []string{"corp.com", "*.partner.io"},
+		BlockedDomains:   []string{"@legal.partner.io"},
+		MaxRecipients:    2,
+		BlockPublicLinks: true,
+	}}
+
+	cases := []struct {
+		action outboundAction
+		want   string
+	}{
+		{outboundAction{Action: "send mail", Recipients: []string{"a@corp.com", "Bob <b@eu.partner.io>"}}, ""},
+		{outboundAction{Action: "send mail", Recipients: []string{"a@corp.com, b@corp.com", "c@corp.com"}}, "3 recipients exceed the maximum of 2"},
+		{outboundAction{Action: "send mail", Recipients: []string{"x@gmail.com"}}, "x@gmail.com is not in an allowed domain"},
+		{outboundAction{Action: "send mail", Recipients: []string{"y@legal.partner.io"}}, "y@legal.partner.io is in a blocked domain"},
+		{outboundAction{Action: "share file", Public: true}, `public "anyone" links are not allowed`},
+		{outboundAction{Action: "share file", Recipients: []string{"a@notcorp.com"}}, "not in an allowed domain"},
+	}
+	for _, tc := range cases {
+		err := guard.Check(context.Background(), &RootFlags{}, tc.action)
+		if tc.want == "" {
+			if err != nil {
+				t.Fatalf("%+v: unexpected error %v", tc.action, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.want) || !strings.Contains(err.Error(), "--force is not allowed") {
+			t.Fatalf("%+v: expected %q, got %v", tc.action, tc.want, err)
+		}
+	}
+
+	blocked := outboundAction{Action: "send mail", Recipients: []string{"x@gmail.com"}}
+	if err := guard.Check(context.Background(), &RootFlags{Force: true}, blocked); err == nil {
+		t.Fatalf("expected --force to be ignored without allow_force")
+	}
+	guard.policy.AllowForce = true
+	u, err := ui.New(ui.Options{Stdout: io.Discard, Stderr: io.Discard, Color: "never"})
+	if err != nil {
+		t.Fatalf("ui.New: %v", err)
+	}
+	if err := guard.Check(ui.WithUI(context.Background(), u), &RootFlags{Force: true}, blocked); err != nil {
+		t.Fatalf("expected --force override, got %v", err)
+	}
+
+	var nilGuard *outboundGuard
+	if err := nilGuard.Check(context.Background(), &RootFlags{}, blocked); err != nil {
+		t.Fatalf("nil guard: %v", err)
+	}
+}
+
+func TestOutboundGuard_ConfirmExternal(t *testing.T) {
+	guard := &outboundGuard{account: "me@corp.com", policy: config.OutboundPolicy{ConfirmExternal: true}}
+
+	internal := outboundAction{Action: "send mail", Recipients: []string{"a@corp.com", "b@eng.corp.com"}}
+	if err := guard.Check(context.Background(), &RootFlags{NoInput: true}, internal); err != nil {
+		t.Fatalf("internal recipients: %v", err)
+	}
+
+	external := outboundAction{Action: "send mail", Recipients: []string{"a@corp.com", "x@gmail.com"}}
+	err := guard.Check(context.Background(), &RootFlags{NoInput: true}, external)
+	if err == nil || !strings.Contains(err.Error(), "requires confirmation") || !strings.Contains(err.Error(), "x@gmail.com") {
+		t.Fatalf("expected confirmation error, got %v", err)
+	}
+	if err := guard.Check(context.Background(), &RootFlags{NoInput: true, DryRun: true}, external); err != nil {
+		t.Fatalf("dry-run should not prompt: %v", err)
+	}
+
+	guard.policy.InternalDomains = []string{"gmail.com", "corp.com"}
+	if err := guard.Check(context.Background(), &RootFlags{NoInput: true}, external); err != nil {
+		t.Fatalf("internal_domains: %v", err)
+	}
+}
+
+func TestExecute_OutboundPolicy_BlocksPublicShare(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("GOG_READ_ONLY", "")
+
+	if err := config.WriteConfig(config.File{Policy: &config.Policy{
+		Outbound: &config.OutboundPolicy{BlockPublicLinks: true},
+	}}); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	var err error
+	_ = captureStderr(t, func() {
+		err = Execute([]string{"--account", "a@b.com", "--force", "drive", "share", "f1", "--anyone"})
+	})
+	if err == nil || ExitCode(err) != 1 || !strings.Contains(err.Error(), "outbound policy blocks share file") {
+		t.Fatalf("expected outbound policy error, got %v (%d)", err, ExitCode(err))
+	}
+}
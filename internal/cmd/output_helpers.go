@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"os"
 	"text/tabwriter"
@@ -24,3 +26,35 @@ func printNextPageHint(u *ui.UI, nextPageToken string) {
 	}
 	u.Err().Printf("# Next page: --page %s", nextPageToken)
 }
+
+// withOutputTransform parses --select/--template into ctx. Both operate on the
+// JSON payload, so they switch the default table output to JSON mode.
+func withOutputTransform(ctx context.Context, mode outfmt.Mode, flags *RootFlags) (context.Context, outfmt.Mode, error) {
+	var t outfmt.Transform
+	if flags.Select != "" {
+		fields, err := outfmt.ParseSelect(flags.Select)
+		if err != nil {
+			return ctx, mode, err
+		}
+		t.Fields = fields
+	}
+	if flags.Template != "" {
+		tmpl, err := outfmt.ParseTemplate(flags.Template)
+		if err != nil {
+			return ctx, mode, err
+		}
+		if f := mode.ResolvedFormat(); f.Structured() && f != outfmt.FormatJSON {
+			return ctx, mode, fmt.Errorf("cannot combine --template and --output-format=%s", f)
+		}
+		t.Template = tmpl
+	}
+	if len(t.Fields) == 0 && t.Template == nil {
+		return ctx, mode, nil
+	}
+
+	if mode.Plain {
+		return ctx, mode, errors.New("cannot combine --plain with --select/--template")
+	}
+	mode.JSON = true
+	return outfmt.WithTransform(ctx, t), mode, nil
+}
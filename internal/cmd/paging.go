@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// PagingFlags lets a list command follow nextPageToken on its own instead of
+// printing a "# Next page" hint.
+type PagingFlags struct {
+	AllPages bool  `name:"all-pages" help:"Fetch every page by following nextPageToken; streams results (NDJSON with --json)"`
+	MaxTotal int64 `name:"max-total" help:"With --all-pages: stop after this many results overall (0 = no cap)" default:"0"`
+}
+
+// pageFetcher fetches a single page and returns its items and the next token.
+type pageFetcher[T any] func(pageToken string) ([]T, string, error)
+
+// pageTable describes the text output of a streamed list.
+type pageTable[T any] struct {
+	header string
+	row    func(io.Writer, T)
+	empty  string
+}
+
+// streamAllPages keeps fetching from startToken until the API stops returning
+// a nextPageToken (or paging.MaxTotal items were written). Structured output
+// is one record per item; text output writes the header once and flushes the
+// table after every page.
+func streamAllPages[T any](ctx context.Context, paging PagingFlags, startToken string, fetch pageFetcher[T], table pageTable[T]) error {
+	u := ui.FromContext(ctx)
+
+	var rw *outfmt.RecordWriter
+	var w io.Writer
+	flush := func() {}
+	if outfmt.IsJSON(ctx) {
+		rw = outfmt.NewRecordWriter(ctx, os.Stdout)
+	} else {
+		w, flush = tableWriter(ctx)
+	}
+
+	var total int64
+	token := startToken
+	for {
+		items, next, err := fetch(token)
+		if err != nil {
+			flush()
+			return err
+		}
+
+		for i, it := range items {
+			if paging.MaxTotal > 0 && total >= paging.MaxTotal {
+				flush()
+				printTruncatedPageHint(u, paging.MaxTotal, token, i)
+				return nil
+			}
+			if rw != nil {
+				if err := rw.Write(it); err != nil {
+					return err
+				}
+			} else {
+				if total == 0 && table.header != "" {
+					fmt.Fprintln(w, table.header)
+				}
+				table.row(w, it)
+			}
+			total++
+		}
+		flush()
+
+		if next == "" || (paging.MaxTotal > 0 && total >= paging.MaxTotal) {
+			if next != "" {
+				printNextPageHint(u, next)
+			}
+			break
+		}
+		token = next
+	}
+
+	if total == 0 && rw == nil && u != nil && table.empty != "" {
+		u.Err().Println(table.empty)
+	}
+	return nil
+}
+
+// printTruncatedPageHint tells the caller that --max-total cut a page short
+// and how to pick up the remaining results of that page.
+func printTruncatedPageHint(u *ui.UI, maxTotal int64, pageToken string, written int) {
+	if u == nil {
+		return
+	}
+	if pageToken == "" {
+		u.Err().Printf("# Stopped at --max-total %d partway through the first page after %d of its results", maxTotal, written)
+		return
+	}
+	u.Err().Printf("# Stopped at --max-total %d partway through a page; resume with --page %s and skip the first %d of its results", maxTotal, pageToken, written)
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+func fakePages(pages map[string][]string, next map[string]string, calls *[]string) pageFetcher[string] {
+	return func(pageToken string) ([]string, string, error) {
+		*calls = append(*calls, pageToken)
+		return pages[pageToken], next[pageToken], nil
+	}
+}
+
+func TestStreamAllPages_JSONWritesNDJSON(t *testing.T) {
+	var calls []string
+	fetch := fakePages(
+		map[string][]string{"": {"a", "b"}, "p2": {"c"}},
+		map[string]string{"": "p2"},
+		&calls,
+	)
+	ctx := outfmt.WithMode(context.Background(), outfmt.Mode{JSON: true})
+
+	out := captureStdout(t, func() {
+		if err := streamAllPages(ctx, PagingFlags{AllPages: true}, "", fetch, pageTable[string]{}); err != nil {
+			t.Fatalf("streamAllPages: %v", err)
+		}
+	})
+	if out != "\"a\"\n\"b\"\n\"c\"\n" {
+		t.Fatalf("unexpected output: %q", out)
+	}
+	if strings.Join(calls, ",") != ",p2" {
+		t.Fatalf("unexpected page calls: %v", calls)
+	}
+}
+
+func TestStreamAllPages_TextMaxTotal(t *testing.T) {
+	var calls []string
+	fetch := fakePages(
+		map[string][]string{"": {"a", "b"}, "p2": {"c", "d"}, "p3": {"e"}},
+		map[string]string{"": "p2", "p2": "p3"},
+		&calls,
+	)
+	table := pageTable[string]{
+		header: "VALUE",
+		row:    func(w io.Writer, s string) { fmt.Fprintln(w, s) },
+		empty:  "No values",
+	}
+
+	var out string
+	errOut := captureStderr(t, func() {
+		u, err := ui.New(ui.Options{Stdout: io.Discard, Stderr: os.Stderr, Color: "never"})
+		if err != nil {
+			t.Fatalf("ui.New: %v", err)
+		}
+		ctx := ui.WithUI(outfmt.WithMode(context.Background(), outfmt.Mode{Plain: true}), u)
+		out = captureStdout(t, func() {
+			if err := streamAllPages(ctx, PagingFlags{AllPages: true, MaxTotal: 4}, "", fetch, table); err != nil {
+				t.Fatalf("streamAllPages: %v", err)
+			}
+		})
+	})
+	if out != "VALUE\na\nb\nc\nd\n" {
+		t.Fatalf("unexpected output: %q", out)
+	}
+	if strings.Join(calls, ",") != ",p2" {
+		t.Fatalf("expected to stop after the cap, got calls %v", calls)
+	}
+	if !strings.Contains(errOut, "--page p3") {
+		t.Fatalf("expected resume hint, got %q", errOut)
+	}
+}
+
+func TestStreamAllPages_MaxTotalMidPage(t *testing.T) {
+	for _, tc := range []struct {
+		max     int64
+		out     string
+		wantErr string
+	}{
+		{3, "\"a\"\n\"b\"\n\"c\"\n", "resume with --page p2 and skip the first 1 of its results"},
+		{1, "\"a\"\n", "partway through the first page after 1 of its results"},
+	} {
+		var calls []string
+		fetch := fakePages(
+			map[string][]string{"": {"a", "b"}, "p2": {"c", "d"}},
+			map[string]string{"": "p2"},
+			&calls,
+		)
+
+		var out string
+		errOut := captureStderr(t, func() {
+			u, err := ui.New(ui.Options{Stdout: io.Discard, Stderr: os.Stderr, Color: "never"})
+			if err != nil {
+				t.Fatalf("ui.New: %v", err)
+			}
+			ctx := ui.WithUI(outfmt.WithMode(context.Background(), outfmt.Mode{JSON: true}), u)
+			out = captureStdout(t, func() {
+				if err := streamAllPages(ctx, PagingFlags{AllPages: true, MaxTotal: tc.max}, "", fetch, pageTable[string]{}); err != nil {
+					t.Fatalf("streamAllPages: %v", err)
+				}
+			})
+		})
+		if out != tc.out {
+			t.Fatalf("max %d: unexpected output: %q", tc.max, out)
+		}
+		if !strings.Contains(errOut, tc.wantErr) {
+			t.Fatalf("max %d: expected truncation notice, got %q", tc.max, errOut)
+		}
+	}
+}
+
+func TestExecute_DriveLs_AllPages(t *testing.T) {
+	origNew := newDriveService
+	t.Cleanup(func() { newDriveService = origNew })
+
+	var tokens []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("pageToken")
+		tokens = append(tokens, token)
+		w.Header().Set("Content-Type", "application/json")
+		if token == "" {
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"files":         []map[string]any{{"id": "f1", "name": "One"}},
+				"nextPageToken": "p2",
+			})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"files": []map[string]any{{"id": "f2", "name": "Two"}},
+		})
+	}))
+	defer srv.Close()
+
+	svc, err := drive.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newDriveService = func(context.Context, string) (*drive.Service, error) { return svc, nil }
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", "a@b.com", "drive", "ls", "--all-pages"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+
+	if out != "{\"id\":\"f1\",\"name\":\"One\"}\n{\"id\":\"f2\",\"name\":\"Two\"}\n" {
+		t.Fatalf("unexpected output: %q", out)
+	}
+	if strings.Join(tokens, ",") != ",p2" {
+		t.Fatalf("unexpected page tokens: %v", tokens)
+	}
+}
@@ -37,7 +37,7 @@ func (c *PeopleMeCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"person": person})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"person": person})
 	}
 
 	name := ""
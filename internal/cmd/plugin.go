@@ -0,0 +1,299 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/steipete/gogcli/internal/authclient"
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// pluginCommand is the hidden command that unknown top-level commands are
+// rewritten to when a gog-<name> executable exists on PATH.
+const (
+	pluginCommand = "__plugin"
+	pluginPrefix  = "gog-"
+)
+
+var pluginAccessToken = googleapi.AccessToken
+
+// PluginCmd runs an external gog-<name> executable with the resolved
+// account, client and output mode in its environment.
+type PluginCmd struct {
+	Name string   `arg:"" name:"name" help:"Plugin name"`
+	Args []string `arg:"" optional:"" name:"args" help:"Arguments passed to the plugin"`
+}
+
+type plugin struct {
+	Name string
+	Path string
+}
+
+func (c *PluginCmd) Run(ctx context.Context, flags *RootFlags) error {
+	path, ok := findPlugin(c.Name)
+	if !ok {
+		return usagef("unknown command %q", c.Name)
+	}
+
+	env, err := pluginEnv(ctx, flags)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, path, c.Args...) //nolint:gosec // plugin path comes from PATH lookup
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = env
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			// The plugin has reported its own error.
+			return &ExitError{Code: exitErr.ExitCode()}
+		}
+		return fmt.Errorf("run plugin %s: %w", c.Name, err)
+	}
+	return nil
+}
+
+// pluginEnv passes the invocation context to a plugin:
+//
+//	GOG_ACCOUNT, GOG_CLIENT              resolved account and OAuth client
+//	GOG_PROFILE                          selected profile, if any
+//	GOG_ACCESS_TOKEN, ..._EXPIRY         short-lived access token (RFC3339 expiry)
+//	GOG_OUTPUT_FORMAT, GOG_JSON, GOG_PLAIN
+//	GOG_DRY_RUN                          1 with --dry-run
+//	GOG_BIN                              path of this gog binary
+//
+// The refresh token is never exposed. Without a resolvable account the
+// plugin runs without GOG_ACCOUNT and a token; when the token cannot be
+// minted (offline, revoked, locked keyring) it runs without the token after
+// a warning.
+func pluginEnv(ctx context.Context, flags *RootFlags) ([]string, error) {
+	set := map[string]string{}
+
+	mode := outfmt.FromContext(ctx)
+	set["GOG_OUTPUT_FORMAT"] = string(mode.ResolvedFormat())
+	set["GOG_JSON"] = boolEnv(mode.JSON)
+	set["GOG_PLAIN"] = boolEnv(mode.Plain)
+	set["GOG_DRY_RUN"] = boolEnv(flags.DryRun)
+	if v := strings.TrimSpace(flags.Profile); v != "" {
+		set["GOG_PROFILE"] = v
+	}
+	if exe, err := os.Executable(); err == nil {
+		set["GOG_BIN"] = exe
+	}
+
+	if account, err := requireAccount(flags); err == nil {
+		client, err := authclient.ResolveClient(ctx, account)
+		if err != nil {
+			return nil, err
+		}
+		set["GOG_ACCOUNT"] = account
+		set["GOG_CLIENT"] = client
+		if tok, err := pluginAccessToken(ctx, account); err != nil {
+			if u := ui.FromContext(ctx); u != nil {
+				u.Err().Printf("warning: running plugin without GOG_ACCESS_TOKEN: %v", err)
+			}
+		} else {
+			set["GOG_ACCESS_TOKEN"] = tok.AccessToken
+			if !tok.Expiry.IsZero() {
+				set["GOG_ACCESS_TOKEN_EXPIRY"] = tok.Expiry.UTC().Format(time.RFC3339)
+			}
+		}
+	}
+
+	env := make([]string, 0, len(os.Environ())+len(set))
+	for _, kv := range os.Environ() {
+		k, _, _ := strings.Cut(kv, "=")
+		if _, ok := set[k]; ok || k == "GOG_ACCESS_TOKEN" || k == "GOG_ACCESS_TOKEN_EXPIRY" {
+			continue
+		}
+		env = append(env, kv)
+	}
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		env = append(env, k+"="+set[k])
+	}
+	return env, nil
+}
+
+func boolEnv(v bool) string {
+	if v {
+		return "1"
+	}
+	return ""
+}
+
+// rewritePluginArgs turns `gog [flags] <name> args...` into
+// `gog [flags] __plugin <name> args...` when <name> is not a built-in
+// command and a gog-<name> executable is on PATH.
+func rewritePluginArgs(model *kong.Application, args []string) []string {
+	idx := firstCommandIndex(model, args)
+	if idx < 0 {
+		return args
+	}
+	name := args[idx]
+	if isBuiltinCommand(model, name) {
+		return args
+	}
+	if _, ok := findPlugin(name); !ok {
+		return args
+	}
+
+	out := make([]string, 0, len(args)+2)
+	out = append(out, args[:idx]...)
+	out = append(out, pluginCommand, name)
+	if idx+1 < len(args) {
+		// Keep plugin flags away from the gog parser.
+		out = append(out, "--")
+		out = append(out, args[idx+1:]...)
+	}
+	return out
+}
+
+// firstCommandIndex skips root flags (and their values) and returns the
+// index of the first positional argument, or -1.
+func firstCommandIndex(model *kong.Application, args []string) int {
+	valueFlags := map[string]bool{}
+	for _, f := range model.Flags {
+		if f.IsBool() || f.IsCounter() {
+			continue
+		}
+		valueFlags["--"+f.Name] = true
+		if f.Short != 0 {
+			valueFlags["-"+string(f.Short)] = true
+		}
+	}
+
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch {
+		case a == "--":
+			return -1
+		case strings.HasPrefix(a, "-"):
+			if !strings.Contains(a, "=") && valueFlags[a] {
+				i++
+			}
+		default:
+			return i
+		}
+	}
+	return -1
+}
+
+func isBuiltinCommand(model *kong.Application, name string) bool {
+	for _, child := range model.Children {
+		if child.Name == name {
+			return true
+		}
+		for _, alias := range child.Aliases {
+			if alias == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func findPlugin(name string) (string, bool) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, "-") {
+		return "", false
+	}
+	path, err := exec.LookPath(pluginPrefix + name)
+	if err != nil {
+		return "", false
+	}
+	return path, true
+}
+
+// discoverPlugins lists gog-<name> executables on PATH; the first match
+// for a name wins, as with exec.LookPath.
+func discoverPlugins() []plugin {
+	seen := map[string]bool{}
+	var out []plugin
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			name, ok := strings.CutPrefix(e.Name(), pluginPrefix)
+			if !ok || e.IsDir() {
+				continue
+			}
+			if runtime.GOOS == "windows" {
+				name = strings.TrimSuffix(name, filepath.Ext(name))
+			}
+			if name == "" || seen[name] {
+				continue
+			}
+			path := filepath.Join(dir, e.Name())
+			if !isExecutable(path) {
+				continue
+			}
+			seen[name] = true
+			out = append(out, plugin{Name: name, Path: path})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	return info.Mode().Perm()&0o111 != 0
+}
+
+// pluginNameFromArgs returns the plugin name of rewritten args.
+func pluginNameFromArgs(args []string) string {
+	for i, a := range args {
+		if a == pluginCommand && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// pluginHelp lists discovered plugins for the root help page.
+func pluginHelp(plugins []plugin) string {
+	if len(plugins) == 0 {
+		return ""
+	}
+	width := 0
+	for _, p := range plugins {
+		width = max(width, len(p.Name))
+	}
+	var b strings.Builder
+	b.WriteString("Plugins:\n")
+	for _, p := range plugins {
+		fmt.Fprintf(&b, "  %-*s  %s\n", width, p.Name, p.Path)
+	}
+	return b.String()
+}
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func installTestPlugin(t *testing.T, name string, script string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell plugins need a POSIX shell")
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "gog-"+name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o700); err != nil { //nolint:gosec // test plugin must be executable
+		t.Fatalf("write plugin: %v", err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return path
+}
+
+func TestRewritePluginArgs(t *testing.T) {
+	installTestPlugin(t, "triage", "exit 0\n")
+
+	parser, _, err := newParser("test")
+	if err != nil {
+		t.Fatalf("newParser: %v", err)
+	}
+
+	cases := []struct {
+		in   []string
+		want []string
+	}{
+		{[]string{"triage"}, []string{"__plugin", "triage"}},
+		{[]string{"--account", "a@b.com", "--json", "triage", "--max", "5"}, []string{"--account", "a@b.com", "--json", "__plugin", "triage", "--", "--max", "5"}},
+		{[]string{"--account=a@b.com", "triage", "inbox"}, []string{"--account=a@b.com", "__plugin", "triage", "--", "inbox"}},
+		{[]string{"drive", "ls"}, []string{"drive", "ls"}},
+		{[]string{"mail", "search"}, []string{"mail", "search"}},
+		{[]string{"unknown"}, []string{"unknown"}},
+		{[]string{"--account", "triage", "drive"}, []string{"--account", "triage", "drive"}},
+	}
+	for _, tc := range cases {
+		if got := rewritePluginArgs(parser.Model, tc.in); !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("%q: got %q want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestExecute_Plugin_EnvAndExitCode(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "env.txt")
+	installTestPlugin(t, "report", `echo "args=$*" > "`+out+`"
+env | grep '^GOG_' >> "`+out+`"
+exit 4
+`)
+
+	orig := pluginAccessToken
+	t.Cleanup(func() { pluginAccessToken = orig })
+	pluginAccessToken = func(_ context.Context, email string) (*oauth2.Token, error) {
+		if email != "a@b.com" {
+			t.Fatalf("unexpected account %q", email)
+		}
+		return &oauth2.Token{AccessToken: "ya29.test", RefreshToken: "1//secret", Expiry: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}, nil
+	}
+	t.Setenv("GOG_ACCESS_TOKEN", "stale")
+
+	var err error
+	stderr := captureStderr(t, func() {
+		err = Execute([]string{"--account", "a@b.com", "--json", "report", "--week", "42"})
+	})
+	if ExitCode(err) != 4 {
+		t.Fatalf("expected plugin exit code 4, got %v (%d)", err, ExitCode(err))
+	}
+	if strings.TrimSpace(stderr) != "" {
+		t.Fatalf("expected no error output from gog, got %q", stderr)
+	}
+
+	data, rerr := os.ReadFile(out)
+	if rerr != nil {
+		t.Fatalf("read env: %v", rerr)
+	}
+	got := string(data)
+	for _, want := range []string{
+		"args=--week 42",
+		"GOG_ACCOUNT=a@b.com",
+		"GOG_CLIENT=default",
+		"GOG_ACCESS_TOKEN=ya29.test",
+		"GOG_ACCESS_TOKEN_EXPIRY=2026-01-01T12:00:00Z",
+		"GOG_OUTPUT_FORMAT=json",
+		"GOG_JSON=1",
+	} {
+		if !strings.Contains(got, want+"\n") {
+			t.Fatalf("missing %q in plugin env:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "secret") || strings.Contains(got, "stale") {
+		t.Fatalf("plugin env leaks tokens:\n%s", got)
+	}
+}
+
+func TestExecute_Plugin_RunsWithoutToken(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "env.txt")
+	installTestPlugin(t, "report", `env | grep '^GOG_' > "`+out+`"`+"\n")
+
+	orig := pluginAccessToken
+	t.Cleanup(func() { pluginAccessToken = orig })
+	pluginAccessToken = func(context.Context, string) (*oauth2.Token, error) {
+		return nil, errors.New("token refresh failed")
+	}
+
+	var err error
+	stderr := captureStderr(t, func() {
+		err = Execute([]string{"--account", "a@b.com", "report", "--help"})
+	})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if !strings.Contains(stderr, "without GOG_ACCESS_TOKEN: token refresh failed") {
+		t.Fatalf("expected warning, got %q", stderr)
+	}
+
+	data, rerr := os.ReadFile(out)
+	if rerr != nil {
+		t.Fatalf("plugin did not run: %v", rerr)
+	}
+	if got := string(data); !strings.Contains(got, "GOG_ACCOUNT=a@b.com\n") || strings.Contains(got, "GOG_ACCESS_TOKEN") {
+		t.Fatalf("unexpected plugin env:\n%s", got)
+	}
+}
+
+func TestExecute_Plugin_EnabledCommands(t *testing.T) {
+	ran := filepath.Join(t.TempDir(), "ran")
+	installTestPlugin(t, "triage", `touch "`+ran+`"`+"\n")
+
+	var err error
+	_ = captureStderr(t, func() {
+		err = Execute([]string{"--enable-commands", "gmail", "triage"})
+	})
+	if err == nil || !strings.Contains(err.Error(), `"triage" is not enabled`) {
+		t.Fatalf("expected allowlist error, got %v", err)
+	}
+	if _, statErr := os.Stat(ran); statErr == nil {
+		t.Fatalf("plugin ran despite allowlist")
+	}
+}
+
+func TestExecute_Help_ListsPlugins(t *testing.T) {
+	path := installTestPlugin(t, "weekly-report", "exit 0\n")
+
+	out := captureStdout(t, func() {
+		_ = Execute([]string{"--help"})
+	})
+	if !strings.Contains(out, "Plugins:") || !strings.Contains(out, "weekly-report") || !strings.Contains(out, path) {
+		t.Fatalf("expected plugin in help, got:\n%s", out)
+	}
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/googleapi"
+)
+
+// mutatingVerbs are command names that change state in Google or in gog's
+// local config and keyring.
+var mutatingVerbs = map[string]bool{
+	"accept": true, "add": true, "append": true, "archive": true, "clear": true,
+	"copy": true, "create": true, "delete": true, "done": true, "format": true,
+	"forward": true, "grade": true, "import": true, "join": true, "leave": true,
+	"mkdir": true, "modify": true, "move": true, "reclaim": true, "remove": true,
+	"rename": true, "renew": true, "reply": true, "respond": true, "return": true,
+	"send": true, "set": true, "share": true, "start": true, "stop": true,
+	"turn-in": true, "unarchive": true, "undo": true, "unset": true, "unshare": true,
+	"update": true, "upload": true, "verify": true,
+}
+
+// mutatingCommands are mutating commands whose name is not a verb.
+var mutatingCommands = map[string]bool{
+	"auth.keep":                 true,
+	"auth.keyring.migrate":      true,
+	"auth.manage":               true,
+	"calendar.focus-time":       true,
+	"calendar.out-of-office":    true,
+	"calendar.working-location": true,
+	"gmail.track.setup":         true,
+}
+
+// enforceReadOnly blocks mutating commands and plugins, which get a raw
+// access token. gog api is left to googleapi.ReadOnlyTransport.
+func enforceReadOnly(kctx *kong.Context) error {
+	path := selectedCommandPath(kctx)
+	if len(path) == 0 {
+		return nil
+	}
+	name := commandPathName(path)
+	if kctx.Selected().Name == pluginCommand || mutatingCommands[name] || mutatingVerbs[path[len(path)-1].name] {
+		return &googleapi.ReadOnlyError{Command: name}
+	}
+	return nil
+}
+
+// policyExemptCommands keep working when config.json cannot be read, so the
+// file can still be inspected and fixed.
+var policyExemptCommands = map[string]bool{
+	"config": true, "doctor": true, "version": true, "help": true,
+}
+
+// enforcePolicy applies --enable-commands, the allowlist of a gog run
+// script, the config policy and read-only mode to the selected command. It
+// reports whether read-only mode is on. An unreadable config.json is
+// reported as a warning and leaves the command rules and read-only mode of
+// the policy unset; outbound checks still fail on it.
+func enforcePolicy(kctx *kong.Context, cli *CLI, opts executeOptions) (bool, error) {
+	policy, err := loadPolicy()
+	if err != nil {
+		if path := selectedCommandPath(kctx); len(path) == 0 || !policyExemptCommands[path[0].name] {
+			fmt.Fprintf(os.Stderr, "warning: ignoring config policy: %v\n", err)
+		}
+		policy = config.Policy{}
+	}
+	for _, rules := range []string{cli.EnableCommands, opts.enabledCommands, policy.CommandRules()} {
+		if err := enforceEnabledCommands(kctx, rules); err != nil {
+			return false, err
+		}
+	}
+	readOnly := cli.ReadOnly || policy.ReadOnly
+	if readOnly {
+		if err := enforceReadOnly(kctx); err != nil {
+			return false, err
+		}
+	}
+	return readOnly, nil
+}
+
+// loadPolicy returns the policy section of config.json. Unlike other config
+// lookups it fails when the config cannot be read.
+func loadPolicy() (config.Policy, error) {
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		return config.Policy{}, err
+	}
+	if cfg.Policy == nil {
+		return config.Policy{}, nil
+	}
+	return *cfg.Policy, nil
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steipete/gogcli/internal/config"
+)
+
+func TestExecute_ReadOnly(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("GOG_READ_ONLY", "")
+
+	for _, args := range [][]string{
+		{"--read-only", "config", "set", "timezone", "UTC"},
+		{"--read-only", "gmail", "send", "--to", "a@b.com", "--subject", "x", "--body", "y"},
+		{"--read-only", "calendar", "focus-time", "--from", "2026-01-01T10:00:00Z", "--to", "2026-01-01T11:00:00Z"},
+	} {
+		var err error
+		_ = captureStderr(t, func() {
+			err = Execute(args)
+		})
+		if err == nil || ExitCode(err) != 5 || !strings.Contains(err.Error(), "read-only") {
+			t.Fatalf("%v: expected read-only error, got %v (%d)", args, err, ExitCode(err))
+		}
+	}
+
+	t.Setenv("GOG_READ_ONLY", "true")
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"config", "path"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	if !strings.Contains(out, "config.json") {
+		t.Fatalf("unexpected output: %q", out)
+	}
+}
+
+func TestExecute_ConfigPolicy(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("GOG_READ_ONLY", "")
+
+	if err := config.WriteConfig(config.File{Policy: &config.Policy{
+		ReadOnly: true,
+		Allow:    []string{"config", "time"},
+		Deny:     []string{"config.path"},
+	}}); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	_ = captureStdout(t, func() {
+		if err := Execute([]string{"config", "list"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"config", "path"}, "is disabled"},
+		{[]string{"--enable-commands", "*", "drive", "ls"}, "not enabled"},
+		{[]string{"config", "unset", "timezone"}, "read-only"},
+	} {
+		var err error
+		_ = captureStderr(t, func() {
+			err = Execute(tc.args)
+		})
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%v: expected %q error, got %v", tc.args, tc.want, err)
+		}
+	}
+}
+
+func TestExecute_UnreadableConfigPolicy(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("GOG_READ_ONLY", "")
+
+	path, err := config.ConfigPath()
+	if err != nil {
+		t.Fatalf("config path: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("{not json"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	for _, tc := range []struct {
+		args []string
+		warn bool
+	}{
+		{[]string{"version"}, false},
+		{[]string{"config", "path"}, false},
+		{[]string{"time", "now"}, true},
+	} {
+		var errOut string
+		_ = captureStdout(t, func() {
+			errOut = captureStderr(t, func() {
+				if err := Execute(tc.args); err != nil {
+					t.Fatalf("%v: Execute: %v", tc.args, err)
+				}
+			})
+		})
+		if got := strings.Contains(errOut, "ignoring config policy"); got != tc.warn {
+			t.Fatalf("%v: unexpected stderr %q", tc.args, errOut)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/steipete/gogcli/internal/authclient"
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/internal/googleauth"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+var runIncrementalAuth = func(ctx context.Context, client string, email string, services []string) error {
+	cmd := &AuthAddCmd{Email: email, ServicesCSV: strings.Join(services, ","), DriveScope: storedDriveScope(client, email), Incremental: true}
+	_, _, err := cmd.authorize(ctx, client)
+	return err
+}
+
+// storedDriveScope keeps the Drive scope mode the account was added with, so
+// re-authorizing never widens readonly or file access to full Drive.
+func storedDriveScope(client string, email string) string {
+	store, err := openSecretsStore()
+	if err != nil {
+		return string(googleauth.DriveScopeFull)
+	}
+	tok, err := store.GetToken(client, email)
+	if err != nil {
+		return string(googleauth.DriveScopeFull)
+	}
+	if mode := googleauth.DriveScopeModeOf(tok.Scopes); mode != "" {
+		return string(mode)
+	}
+	return string(googleauth.DriveScopeFull)
+}
+
+// reauthorizeOnScopeError handles a command that failed because the stored
+// OAuth token lacks scopes. On a TTY it offers to grant only the missing
+// services and runs the command again; otherwise it returns an
+// InsufficientScopesError that prints the exact gog auth add command. A
+// command that already changed data is never re-run, since that would
+// repeat the change.
+func reauthorizeOnScopeError(ctx context.Context, flags *RootFlags, tracker *googleapi.ScopeTracker, err error, quiet bool, retry func() error) error {
+	scopeErr := scopeErrorFor(ctx, tracker.Rejections(), err)
+	if scopeErr == nil {
+		return err
+	}
+
+	if tracker.Mutated() {
+		scopeErr.Partial = true
+		return scopeErr
+	}
+	if quiet || flags.NoInput || !term.IsTerminal(int(os.Stdin.Fd())) {
+		return scopeErr
+	}
+
+	u := ui.FromContext(ctx)
+	u.Err().Printf("%s has not granted gog access to %s.", scopeErr.Email, strings.Join(scopeErr.Services, ","))
+	if promptConfirm(ctx, fmt.Sprintf("authorize %s in the browser and retry", strings.Join(scopeErr.Services, ","))) != nil {
+		return scopeErr
+	}
+
+	if authErr := runIncrementalAuth(ctx, scopeErr.Client, scopeErr.Email, scopeErr.Services); authErr != nil {
+		return authErr
+	}
+
+	return retry()
+}
+
+// scopeErrorFor compares the scopes of the last rejected request with the
+// scopes stored with the account's OAuth token. It returns nil when the
+// account does not use a refresh token or already holds every scope (for
+// example when an admin blocks the API), since re-authorizing cannot help.
+func scopeErrorFor(ctx context.Context, rejections []googleapi.ScopeRejection, err error) *googleapi.InsufficientScopesError {
+	if err == nil || len(rejections) == 0 {
+		return nil
+	}
+
+	r := rejections[len(rejections)-1]
+	email := normalizeEmail(r.Email)
+	if _, _, ok := bestServiceAccountPathAndMtime(email); ok {
+		return nil
+	}
+	if _, ok, adcErr := config.ReadADCAccount(email); adcErr != nil || ok {
+		return nil
+	}
+
+	client, clientErr := authclient.ResolveClient(ctx, email)
+	if clientErr != nil {
+		return nil
+	}
+	store, storeErr := openSecretsStore()
+	if storeErr != nil {
+		return nil
+	}
+	tok, tokErr := store.GetToken(client, email)
+	if tokErr != nil {
+		return nil
+	}
+
+	granted := make(map[string]bool, len(tok.Scopes))
+	for _, s := range tok.Scopes {
+		granted[s] = true
+	}
+	requested := make(map[string]bool, len(r.Scopes))
+	for _, s := range r.Scopes {
+		requested[s] = true
+	}
+	grantedServices := make(map[string]bool, len(tok.Services))
+	for _, s := range tok.Services {
+		grantedServices[s] = true
+	}
+
+	var missing []string
+	for _, label := range strings.Split(r.Service, ",") {
+		svc, parseErr := googleauth.ParseService(label)
+		if parseErr != nil || svc == googleauth.ServiceKeep {
+			continue
+		}
+		if len(tok.Scopes) == 0 {
+			// Tokens stored before scopes were recorded only list services.
+			if !grantedServices[string(svc)] {
+				missing = append(missing, string(svc))
+			}
+			continue
+		}
+		scopes, scopesErr := googleauth.Scopes(svc)
+		if scopesErr != nil {
+			continue
+		}
+		for _, s := range scopes {
+			if requested[s] && !granted[s] {
+				missing = append(missing, string(svc))
+				break
+			}
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return &googleapi.InsufficientScopesError{Email: email, Client: client, Services: missing, Cause: err}
+}
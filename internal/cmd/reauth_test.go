@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/internal/googleauth"
+	"github.com/steipete/gogcli/internal/secrets"
+)
+
+func TestScopeErrorFor_MissingService(t *testing.T) {
+	useTestHome(t)
+
+	origOpen := openSecretsStore
+	t.Cleanup(func() { openSecretsStore = origOpen })
+
+	gmailScopes, _ := googleauth.Scopes(googleauth.ServiceGmail)
+	driveScopes, _ := googleauth.Scopes(googleauth.ServiceDrive)
+
+	store := newMemSecretsStore()
+	_ = store.SetToken(config.DefaultClientName, "a@b.com", secrets.Token{
+		Email:        "a@b.com",
+		Services:     []string{"gmail"},
+		Scopes:       gmailScopes,
+		RefreshToken: "rt",
+	})
+	openSecretsStore = func() (secrets.Store, error) { return store, nil }
+
+	cause := errors.New("googleapi: Error 403: insufficient scopes")
+	got := scopeErrorFor(context.Background(), []googleapi.ScopeRejection{
+		{Service: "drive", Email: "A@b.com", Scopes: driveScopes},
+	}, cause)
+	if got == nil {
+		t.Fatalf("expected scope error")
+	}
+	if got.Email != "a@b.com" || !slices.Equal(got.Services, []string{"drive"}) || !errors.Is(got, cause) {
+		t.Fatalf("unexpected error: %#v", got)
+	}
+	if cmd := got.Command(); cmd != "gog auth add a@b.com --services drive --incremental" {
+		t.Fatalf("unexpected command: %q", cmd)
+	}
+
+	// Scopes already granted: the 403 has another cause, so no hint.
+	if got := scopeErrorFor(context.Background(), []googleapi.ScopeRejection{
+		{Service: "gmail", Email: "a@b.com", Scopes: gmailScopes},
+	}, cause); got != nil {
+		t.Fatalf("expected nil, got %#v", got)
+	}
+}
+
+func TestReauthorizeOnScopeError_NoInput(t *testing.T) {
+	useTestHome(t)
+
+	origOpen := openSecretsStore
+	origRun := runIncrementalAuth
+	t.Cleanup(func() {
+		openSecretsStore = origOpen
+		runIncrementalAuth = origRun
+	})
+
+	store := newMemSecretsStore()
+	_ = store.SetToken(config.DefaultClientName, "a@b.com", secrets.Token{
+		Email:        "a@b.com",
+		Services:     []string{"gmail"},
+		RefreshToken: "rt",
+	})
+	openSecretsStore = func() (secrets.Store, error) { return store, nil }
+	runIncrementalAuth = func(context.Context, string, string, []string) error {
+		t.Fatalf("unexpected re-authorization")
+		return nil
+	}
+
+	_, tracker := googleapi.WithScopeTracker(context.Background())
+	cause := errors.New("forbidden")
+	err := reauthorizeOnScopeError(context.Background(), &RootFlags{NoInput: true}, tracker, cause, false, func() error {
+		t.Fatalf("unexpected retry")
+		return nil
+	})
+	if !errors.Is(err, cause) {
+		t.Fatalf("expected original error without rejections, got %v", err)
+	}
+}
+
+func TestAuthAddCmd_IncrementalKeepsGrantedServices(t *testing.T) {
+	useTestHome(t)
+
+	origAuth := authorizeGoogle
+	origOpen := openSecretsStore
+	origKeychain := ensureKeychainAccess
+	origFetch := fetchAuthorizedEmail
+	t.Cleanup(func() {
+		authorizeGoogle = origAuth
+		openSecretsStore = origOpen
+		ensureKeychainAccess = origKeychain
+		fetchAuthorizedEmail = origFetch
+	})
+
+	ensureKeychainAccess = func() error { return nil }
+
+	gmailScopes, _ := googleauth.Scopes(googleauth.ServiceGmail)
+	store := newMemSecretsStore()
+	_ = store.SetToken(config.DefaultClientName, "user@example.com", secrets.Token{
+		Email:        "user@example.com",
+		Services:     []string{"gmail"},
+		Scopes:       gmailScopes,
+		RefreshToken: "old",
+	})
+	openSecretsStore = func() (secrets.Store, error) { return store, nil }
+
+	var gotScopes []string
+	authorizeGoogle = func(_ context.Context, opts googleauth.AuthorizeOptions) (string, error) {
+		gotScopes = opts.Scopes
+		return "new", nil
+	}
+	fetchAuthorizedEmail = func(context.Context, string, string, []string, time.Duration) (string, error) {
+		return "user@example.com", nil
+	}
+
+	_ = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"auth", "add", "user@example.com", "--services", "drive", "--incremental"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+
+	for _, s := range gmailScopes {
+		if !slices.Contains(gotScopes, s) {
+			t.Fatalf("expected granted scope %q to be requested again, got %v", s, gotScopes)
+		}
+	}
+
+	tok, err := store.GetToken(config.DefaultClientName, "user@example.com")
+	if err != nil {
+		t.Fatalf("GetToken: %v", err)
+	}
+	if strings.Join(tok.Services, ",") != "drive,gmail" || tok.RefreshToken != "new" {
+		t.Fatalf("unexpected token: %#v", tok)
+	}
+}
+
+func TestRunIncrementalAuth_KeepsDriveScopeMode(t *testing.T) {
+	useTestHome(t)
+
+	origAuth := authorizeGoogle
+	origOpen := openSecretsStore
+	origKeychain := ensureKeychainAccess
+	origFetch := fetchAuthorizedEmail
+	t.Cleanup(func() {
+		authorizeGoogle = origAuth
+		openSecretsStore = origOpen
+		ensureKeychainAccess = origKeychain
+		fetchAuthorizedEmail = origFetch
+	})
+
+	ensureKeychainAccess = func() error { return nil }
+
+	store := newMemSecretsStore()
+	_ = store.SetToken(config.DefaultClientName, "user@example.com", secrets.Token{
+		Email:        "user@example.com",
+		Services:     []string{"drive"},
+		Scopes:       []string{"https://www.googleapis.com/auth/drive.readonly"},
+		RefreshToken: "old",
+	})
+	openSecretsStore = func() (secrets.Store, error) { return store, nil }
+
+	var gotScopes []string
+	authorizeGoogle = func(_ context.Context, opts googleauth.AuthorizeOptions) (string, error) {
+		gotScopes = opts.Scopes
+		return "new", nil
+	}
+	fetchAuthorizedEmail = func(context.Context, string, string, []string, time.Duration) (string, error) {
+		return "user@example.com", nil
+	}
+
+	if err := runIncrementalAuth(context.Background(), config.DefaultClientName, "user@example.com", []string{"sheets"}); err != nil {
+		t.Fatalf("runIncrementalAuth: %v", err)
+	}
+	if slices.Contains(gotScopes, "https://www.googleapis.com/auth/drive") || !slices.Contains(gotScopes, "https://www.googleapis.com/auth/drive.readonly") {
+		t.Fatalf("expected the readonly Drive scope to be kept, got %v", gotScopes)
+	}
+}
@@ -2,16 +2,20 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"strings"
 
 	"github.com/alecthomas/kong"
 
 	"github.com/steipete/gogcli/internal/authclient"
 	"github.com/steipete/gogcli/internal/config"
 	"github.com/steipete/gogcli/internal/errfmt"
+	"github.com/steipete/gogcli/internal/googleapi"
 	"github.com/steipete/gogcli/internal/googleauth"
 	"github.com/steipete/gogcli/internal/outfmt"
 	"github.com/steipete/gogcli/internal/secrets"
@@ -27,11 +31,18 @@ type RootFlags struct {
 	Color          string `help:"Color output: auto|always|never" default:"${color}"`
 	Account        string `help:"Account email for API commands (gmail/calendar/chat/classroom/drive/docs/slides/contacts/tasks/people/sheets)"`
 	Client         string `help:"OAuth client name (selects stored credentials + token bucket)" default:"${client}"`
+	Profile        string `help:"Named config profile with account, client, timezone, output and flag defaults" default:"${profile}"`
 	EnableCommands string `help:"Comma-separated list of enabled top-level commands (restricts CLI)" default:"${enabled_commands}"`
 	JSON           bool   `help:"Output JSON to stdout (best for scripting)" default:"${json}"`
 	Plain          bool   `help:"Output stable, parseable text to stdout (TSV; no colors)" default:"${plain}"`
+	OutputFormat   string `name:"output-format" help:"Output format: table|tsv|json|ndjson|csv|yaml (json/ndjson/csv/yaml render the --json payload)" default:"${output_format}"`
+	Select         string `name:"select" help:"Comma-separated JSON fields to keep per record (dotted paths, e.g. id,payload.mimeType; implies --json)"`
+	Template       string `name:"template" help:"Go text/template rendered per record (e.g. '{{.id}}\\t{{.name}}'; funcs: date, join, truncate, json, upper, lower)"`
+	Cache          string `name:"cache" help:"HTTP response cache for API reads: off|read|refresh" default:"${cache}"`
+	DryRun         bool   `name:"dry-run" help:"Print mutating API calls (POST/PUT/PATCH/DELETE) instead of sending them"`
 	Force          bool   `help:"Skip confirmations for destructive commands"`
 	NoInput        bool   `help:"Never prompt; fail instead (useful for CI)"`
+	ReadOnly       bool   `name:"read-only" help:"Block every command and API request that changes data" default:"${read_only}"`
 	Verbose        bool   `help:"Enable verbose logging"`
 }
 
@@ -56,9 +67,15 @@ type CLI struct {
 	Keep       KeepCmd               `cmd:"" help:"Google Keep (Workspace only)"`
 	Sheets     SheetsCmd             `cmd:"" help:"Google Sheets"`
 	Config     ConfigCmd             `cmd:"" help:"Manage configuration"`
+	Doctor     DoctorCmd             `cmd:"" help:"Check config, credentials, keyring, tokens, scopes, APIs and clock"`
+	Audit      AuditCmd              `cmd:"" help:"Local audit log of write operations"`
+	Undo       UndoCmd               `cmd:"" help:"Undo recent reversible operations (labels, moves, renames, task status, event updates)"`
+	API        APICmd                `cmd:"" name:"api" help:"Send a raw request to a Google REST API with a stored account"`
+	Run        RunCmd                `cmd:"" help:"Run a script of gog commands in one process (one command per line)"`
 	VersionCmd VersionCmd            `cmd:"" name:"version" help:"Print version"`
 	Completion CompletionCmd         `cmd:"" help:"Generate shell completion scripts"`
 	Complete   CompletionInternalCmd `cmd:"" name:"__complete" hidden:"" help:"Internal completion helper"`
+	Plugin     PluginCmd             `cmd:"" name:"__plugin" hidden:"" help:"Internal plugin launcher"`
 }
 
 type exitPanic struct{ code int }
@@ -69,6 +86,17 @@ func Execute(args []string) (err error) {
 		return err
 	}
 
+	return execute(parser, cli, args, executeOptions{})
+}
+
+// executeOptions adjusts execute for the lines of a gog run script.
+type executeOptions struct {
+	ctx             context.Context // base context; nil means context.Background()
+	enabledCommands string          // allowlist enforced on top of the line's --enable-commands
+	quiet           bool            // return errors without printing them
+}
+
+func execute(parser *kong.Kong, cli *CLI, args []string, opts executeOptions) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			if ep, ok := r.(exitPanic); ok {
@@ -83,17 +111,28 @@ func Execute(args []string) (err error) {
 		}
 	}()
 
-	kctx, err := parser.Parse(args)
+	rep := &errorReporter{quiet: opts.quiet, json: jsonRequestedInArgs(args)}
+
+	args, err = expandCommandAlias(parser.Model, args)
 	if err != nil {
-		parsedErr := wrapParseError(err)
-		_, _ = fmt.Fprintln(os.Stderr, errfmt.Format(parsedErr))
-		return parsedErr
+		return rep.report(err)
 	}
 
-	if err = enforceEnabledCommands(kctx, cli.EnableCommands); err != nil {
-		_, _ = fmt.Fprintln(os.Stderr, errfmt.Format(err))
-		return err
+	kctx, err := parser.Parse(rewritePluginArgs(parser.Model, args))
+	if err != nil {
+		return rep.report(wrapParseError(err))
+	}
+	rep.json = cli.JSON || cli.Select != "" || structuredFormat(cli.OutputFormat)
+
+	var readOnly bool
+	profile, err := loadProfile(cli.Profile)
+	if err == nil {
+		readOnly, err = enforcePolicy(kctx, cli, opts)
+	}
+	if err != nil {
+		return rep.report(err)
 	}
+	profileTimezone = profile.Timezone
 
 	logLevel := slog.LevelWarn
 	if cli.Verbose {
@@ -105,12 +144,40 @@ func Execute(args []string) (err error) {
 
 	mode, err := outfmt.FromFlags(cli.JSON, cli.Plain)
 	if err != nil {
-		return newUsageError(err)
+		return rep.report(newUsageError(err))
+	}
+	outputFormat := cli.OutputFormat
+	if !cli.JSON && !cli.Plain && outputFormat == "" && cli.Select == "" && cli.Template == "" {
+		outputFormat = profile.Output
+	}
+	mode, err = mode.WithFormat(outputFormat)
+	if err != nil {
+		return rep.report(newUsageError(err))
 	}
 
-	ctx := context.Background()
+	ctx := opts.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, mode, err = withOutputTransform(ctx, mode, &cli.RootFlags)
+	if err != nil {
+		return rep.report(newUsageError(err))
+	}
 	ctx = outfmt.WithMode(ctx, mode)
+	rep.json = outfmt.IsJSON(ctx)
 	ctx = authclient.WithClient(ctx, cli.Client)
+	cacheMode, err := googleapi.ParseCacheMode(cli.Cache)
+	if err != nil {
+		return rep.report(newUsageError(err))
+	}
+	ctx = googleapi.WithCacheMode(ctx, cacheMode)
+	ctx = withAuditSession(ctx, kctx)
+	if readOnly {
+		ctx = googleapi.WithReadOnly(ctx)
+	}
+	if cli.DryRun {
+		ctx = googleapi.WithDryRun(ctx, googleapi.DryRun{Out: os.Stderr, JSON: outfmt.IsJSON(ctx)})
+	}
 
 	uiColor := cli.Color
 	if outfmt.IsJSON(ctx) || outfmt.IsPlain(ctx) {
@@ -123,26 +190,104 @@ func Execute(args []string) (err error) {
 		Color:  uiColor,
 	})
 	if err != nil {
-		return err
+		return rep.report(err)
 	}
 	ctx = ui.WithUI(ctx, u)
+	rep.u = u
+	ctx, scopeTracker := googleapi.WithScopeTracker(ctx)
+	ctx, clients := googleapi.WithClientCache(ctx)
 
 	kctx.BindTo(ctx, (*context.Context)(nil))
 	kctx.Bind(&cli.RootFlags)
 
 	err = kctx.Run()
-	if err == nil {
-		return nil
+	if err != nil {
+		err = reauthorizeOnScopeError(ctx, &cli.RootFlags, scopeTracker, err, opts.quiet, func() error {
+			clients.Reset()
+			return kctx.Run()
+		})
+	}
+	return rep.report(err)
+}
+
+// errorReporter prints the error that ends an invocation: the
+// {"error":{...}} envelope when JSON output was requested, a formatted
+// message otherwise.
+type errorReporter struct {
+	quiet bool
+	json  bool
+	u     *ui.UI
+}
+
+func (r *errorReporter) report(err error) error {
+	if err == nil || r.quiet || silentExit(err) {
+		return err
 	}
 
-	if u := ui.FromContext(ctx); u != nil {
-		u.Err().Error(errfmt.Format(err))
+	if r.json {
+		writeJSONError(os.Stderr, err)
+		return err
+	}
+	if r.u != nil {
+		r.u.Err().Error(errfmt.Format(err))
 		return err
 	}
 	_, _ = fmt.Fprintln(os.Stderr, errfmt.Format(err))
 	return err
 }
 
+// jsonRequestedInArgs reports whether the environment or raw args ask for
+// JSON output, for errors raised before flags are parsed.
+func jsonRequestedInArgs(args []string) bool {
+	want := outfmt.FromEnv().JSON || structuredFormat(os.Getenv("GOG_OUTPUT_FORMAT"))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		name, value, hasValue := strings.Cut(arg, "=")
+		switch {
+		case arg == "--":
+			return want
+		case name == "--json":
+			want = !hasValue || value != "false"
+		case name == "--plain":
+			want = false
+		case name == "--select":
+			want = true
+		case name == "--output-format" && hasValue:
+			want = structuredFormat(value)
+		case name == "--output-format" && i+1 < len(args):
+			i++
+			want = structuredFormat(args[i])
+		}
+	}
+	return want
+}
+
+func structuredFormat(name string) bool {
+	if strings.TrimSpace(name) == "" {
+		return false
+	}
+	f, err := outfmt.ParseFormat(name)
+	return err == nil && f.Structured()
+}
+
+// writeJSONError writes the {"error":{...}} envelope for err.
+func writeJSONError(w io.Writer, err error) {
+	_ = json.NewEncoder(w).Encode(map[string]any{"error": errorInfo(err)})
+}
+
+// errorInfo classifies err, letting an explicit ExitError code win.
+func errorInfo(err error) errfmt.Info {
+	info := errfmt.Classify(err)
+	var ee *ExitError
+	if errors.As(err, &ee) && ee != nil {
+		info.ExitCode = ExitCode(err)
+		if info.ExitCode == errfmt.ExitUsage {
+			info.Code = errfmt.CodeUsage
+		}
+	}
+	return info
+}
+
 func wrapParseError(err error) error {
 	if err == nil {
 		return nil
@@ -172,12 +317,16 @@ func newParser(description string) (*kong.Kong, *CLI, error) {
 	envMode := outfmt.FromEnv()
 	vars := kong.Vars{
 		"auth_services":    googleauth.UserServiceCSV(),
+		"cache":            envOr("GOG_CACHE", "off"),
 		"color":            envOr("GOG_COLOR", "auto"),
 		"calendar_weekday": envOr("GOG_CALENDAR_WEEKDAY", "false"),
 		"client":           envOr("GOG_CLIENT", ""),
 		"enabled_commands": envOr("GOG_ENABLE_COMMANDS", ""),
 		"json":             boolString(envMode.JSON),
+		"output_format":    envOr("GOG_OUTPUT_FORMAT", ""),
 		"plain":            boolString(envMode.Plain),
+		"profile":          envOr("GOG_PROFILE", ""),
+		"read_only":        envOr("GOG_READ_ONLY", "false"),
 		"version":          VersionString(),
 	}
 
@@ -189,6 +338,7 @@ func newParser(description string) (*kong.Kong, *CLI, error) {
 		kong.ConfigureHelp(helpOptions()),
 		kong.Help(helpPrinter),
 		kong.Vars(vars),
+		kong.Resolvers(profileResolver()),
 		kong.Writers(os.Stdout, os.Stderr),
 		kong.Exit(func(code int) { panic(exitPanic{code: code}) }),
 	)
@@ -0,0 +1,427 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/errfmt"
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/secrets"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+const onErrorContinue = "continue"
+
+var (
+	errUnterminatedQuote = errors.New("unterminated quote")
+	scriptAssignRe       = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*=`)
+)
+
+// RunCmd executes a script of gog command lines in this process. Lines
+// share one keyring handle and one googleapi.Session, so tokens are
+// refreshed once per account instead of once per line.
+type RunCmd struct {
+	Script  string   `arg:"" name:"script" help:"Script file with one gog command per line (- for stdin)"`
+	OnError string   `name:"on-error" help:"When a line fails: stop|continue" enum:"stop,continue" default:"stop"`
+	Vars    []string `name:"var" sep:"none" help:"Script variable name=value, used as $$name or $${name} (can be repeated)"`
+}
+
+type scriptLine struct {
+	Num  int
+	Text string
+	Args []string
+}
+
+type scriptResult struct {
+	Line     int          `json:"line"`
+	Command  string       `json:"command"`
+	ExitCode int          `json:"exitCode"`
+	Output   any          `json:"output,omitempty"`
+	Error    *errfmt.Info `json:"error,omitempty"`
+}
+
+type scriptContextKey struct{}
+
+func (c *RunCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	if v, _ := ctx.Value(scriptContextKey{}).(bool); v {
+		return usage("gog run cannot be nested")
+	}
+
+	vars := map[string]string{}
+	for _, kv := range c.Vars {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok || !scriptAssignRe.MatchString(name+"=") {
+			return usagef("invalid --var %q (expected name=value)", kv)
+		}
+		vars[name] = value
+	}
+
+	src, err := readScript(c.Script)
+	if err != nil {
+		return err
+	}
+	lines, err := parseScript(src, vars)
+	if err != nil {
+		return err
+	}
+
+	parser, cli, err := newParser(helpDescription())
+	if err != nil {
+		return err
+	}
+
+	release := secrets.ShareKeyring()
+	defer release()
+
+	base := googleapi.WithSession(context.Background(), googleapi.NewSession())
+	base = context.WithValue(base, scriptContextKey{}, true)
+	opts := executeOptions{ctx: base, enabledCommands: flags.EnableCommands, quiet: true}
+	prefix := scriptFlagArgs(ctx, flags)
+	report := outfmt.IsJSON(ctx)
+
+	results := make([]scriptResult, 0, len(lines))
+	failed := 0
+	var stopErr error
+	for _, line := range lines {
+		args := append(append([]string{}, prefix...), line.Args...)
+
+		var lineErr error
+		result := scriptResult{Line: line.Num, Command: line.Text}
+		if report {
+			var out []byte
+			out, lineErr = captureScriptOutput(func() error { return execute(parser, cli, args, opts) })
+			result.Output = scriptOutput(out)
+		} else {
+			lineErr = execute(parser, cli, args, opts)
+		}
+		result.ExitCode = ExitCode(lineErr)
+		if lineErr != nil {
+			info := errorInfo(lineErr)
+			result.Error = &info
+		}
+		results = append(results, result)
+
+		if lineErr == nil {
+			continue
+		}
+		failed++
+		if c.OnError != onErrorContinue {
+			stopErr = fmt.Errorf("line %d: %w", line.Num, lineErr)
+			break
+		}
+		if !report {
+			u.Err().Error(fmt.Sprintf("line %d: %s", line.Num, errfmt.Format(lineErr)))
+		}
+	}
+
+	if report {
+		if err := outfmt.Write(ctx, os.Stdout, map[string]any{
+			"results":   results,
+			"succeeded": len(results) - failed,
+			"failed":    failed,
+			"skipped":   len(lines) - len(results),
+		}); err != nil {
+			return err
+		}
+	}
+
+	if stopErr != nil {
+		return stopErr
+	}
+	if failed > 0 {
+		return &ExitError{Code: errfmt.ExitError, Err: fmt.Errorf("%d of %d lines failed", failed, len(lines))}
+	}
+	return nil
+}
+
+func readScript(path string) (string, error) {
+	path = strings.TrimSpace(path)
+
+	var (
+		b   []byte
+		err error
+	)
+	if path == "-" {
+		b, err = io.ReadAll(os.Stdin)
+	} else {
+		path, err = config.ExpandPath(path)
+		if err != nil {
+			return "", err
+		}
+		b, err = os.ReadFile(path) //nolint:gosec // user-provided path
+	}
+	if err != nil {
+		return "", fmt.Errorf("read script: %w", err)
+	}
+	return string(b), nil
+}
+
+// scriptFlagArgs carries the global flags given to gog run over to every
+// line; flags on the line itself come later and win. The output mode comes
+// from ctx, so --output-format, --select and profile defaults that make the
+// report JSON also make each line's output JSON.
+func scriptFlagArgs(ctx context.Context, flags *RootFlags) []string {
+	var out []string
+	if v := strings.TrimSpace(flags.Account); v != "" {
+		out = append(out, "--account", v)
+	}
+	if v := strings.TrimSpace(flags.Client); v != "" {
+		out = append(out, "--client", v)
+	}
+	if v := strings.TrimSpace(flags.Profile); v != "" {
+		out = append(out, "--profile", v)
+	}
+	if v := strings.TrimSpace(flags.Color); v != "" {
+		out = append(out, "--color", v)
+	}
+	if v := strings.TrimSpace(flags.Cache); v != "" {
+		out = append(out, "--cache", v)
+	}
+	switch {
+	case outfmt.IsJSON(ctx):
+		out = append(out, "--json")
+	case outfmt.IsPlain(ctx):
+		out = append(out, "--plain")
+	}
+	if flags.DryRun {
+		out = append(out, "--dry-run")
+	}
+	if flags.ReadOnly {
+		out = append(out, "--read-only")
+	}
+	if flags.Force {
+		out = append(out, "--force")
+	}
+	if flags.NoInput {
+		out = append(out, "--no-input")
+	}
+	if flags.Verbose {
+		out = append(out, "--verbose")
+	}
+	return out
+}
+
+// parseScript splits src into command lines. Blank lines and # comments are
+// skipped, a trailing backslash continues a line, a leading "gog" is
+// optional and NAME=value lines set variables for the lines that follow.
+func parseScript(src string, vars map[string]string) ([]scriptLine, error) {
+	lookup := func(name string) (string, bool) {
+		if v, ok := vars[name]; ok {
+			return v, true
+		}
+		return os.LookupEnv(name)
+	}
+
+	var out []scriptLine
+	raw := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	for i := 0; i < len(raw); i++ {
+		num := i + 1
+		text := strings.TrimSpace(raw[i])
+		for strings.HasSuffix(text, "\\") && i+1 < len(raw) {
+			i++
+			text = strings.TrimSpace(strings.TrimSuffix(text, "\\")) + " " + strings.TrimSpace(raw[i])
+		}
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		args, err := splitScriptLine(text, lookup)
+		if err != nil {
+			return nil, usagef("script line %d: %v", num, err)
+		}
+		if len(args) == 0 {
+			continue
+		}
+		if len(args) == 1 && scriptAssignRe.MatchString(args[0]) {
+			name, value, _ := strings.Cut(args[0], "=")
+			vars[name] = value
+			continue
+		}
+		if args[0] == "gog" {
+			args = args[1:]
+			text = strings.TrimSpace(strings.TrimPrefix(text, "gog"))
+		}
+		if len(args) == 0 {
+			continue
+		}
+		out = append(out, scriptLine{Num: num, Text: text, Args: args})
+	}
+	return out, nil
+}
+
+// splitScriptLine splits line into arguments with shell-like quoting:
+// '...' is literal, "..." expands variables and honours \" \\ \$, and a
+// backslash outside quotes escapes the next character. $name, ${name} and
+// the positional $1 and $@ expand outside single quotes; unset variables
+// are an error. A # at the start of a word begins a comment.
+func splitScriptLine(line string, lookup func(string) (string, bool)) ([]string, error) {
+	var (
+		args  []string
+		cur   strings.Builder
+		inArg bool
+	)
+	flush := func() {
+		if inArg {
+			args = append(args, cur.String())
+		}
+		cur.Reset()
+		inArg = false
+	}
+
+	rs := []rune(line)
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		switch {
+		case r == ' ' || r == '\t':
+			flush()
+		case r == '#' && !inArg:
+			flush()
+			return args, nil
+		case r == '\\':
+			inArg = true
+			if i+1 < len(rs) {
+				i++
+				cur.WriteRune(rs[i])
+			}
+		case r == '\'':
+			inArg = true
+			end := indexRune(rs, i+1, '\'')
+			if end < 0 {
+				return nil, errUnterminatedQuote
+			}
+			cur.WriteString(string(rs[i+1 : end]))
+			i = end
+		case r == '"':
+			inArg = true
+			i++
+			for ; i < len(rs) && rs[i] != '"'; i++ {
+				switch {
+				case rs[i] == '\\' && i+1 < len(rs) && strings.ContainsRune(`"\$`, rs[i+1]):
+					i++
+					cur.WriteRune(rs[i])
+				case rs[i] == '$':
+					n, err := expandScriptVar(rs, i, &cur, lookup)
+					if err != nil {
+						return nil, err
+					}
+					i = n
+				default:
+					cur.WriteRune(rs[i])
+				}
+			}
+			if i >= len(rs) {
+				return nil, errUnterminatedQuote
+			}
+		case r == '$':
+			inArg = true
+			n, err := expandScriptVar(rs, i, &cur, lookup)
+			if err != nil {
+				return nil, err
+			}
+			i = n
+		default:
+			inArg = true
+			cur.WriteRune(r)
+		}
+	}
+	flush()
+	return args, nil
+}
+
+// expandScriptVar expands the variable at rs[i] ('$') into cur and returns
+// the index of its last rune. A lone $ is kept literally.
+func expandScriptVar(rs []rune, i int, cur *strings.Builder, lookup func(string) (string, bool)) (int, error) {
+	start, end, next := i+1, i+1, i
+	if start < len(rs) && rs[start] == '{' {
+		closing := indexRune(rs, start+1, '}')
+		if closing < 0 {
+			return 0, fmt.Errorf("unterminated ${ at column %d", i+1)
+		}
+		start, end, next = start+1, closing, closing
+	} else if start < len(rs) && rs[start] == '@' {
+		end, next = start+1, start
+	} else {
+		// $1, $2, ... are positional; names start with a letter or _.
+		positional := start < len(rs) && isDigit(rs[start])
+		for end < len(rs) && (isDigit(rs[end]) || (!positional && (rs[end] == '_' || (rs[end] >= 'a' && rs[end] <= 'z') || (rs[end] >= 'A' && rs[end] <= 'Z')))) {
+			end++
+		}
+		next = end - 1
+	}
+
+	name := string(rs[start:end])
+	if name == "" {
+		cur.WriteRune('$')
+		return i, nil
+	}
+	v, ok := lookup(name)
+	if !ok {
+		return 0, fmt.Errorf("undefined variable %q", name)
+	}
+	cur.WriteString(v)
+	return next, nil
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func indexRune(rs []rune, from int, r rune) int {
+	for i := from; i < len(rs); i++ {
+		if rs[i] == r {
+			return i
+		}
+	}
+	return -1
+}
+
+// captureScriptOutput runs fn with os.Stdout redirected into a buffer.
+func captureScriptOutput(fn func() error) ([]byte, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("capture output: %w", err)
+	}
+
+	done := make(chan []byte, 1)
+	go func() {
+		b, _ := io.ReadAll(r)
+		done <- b
+	}()
+
+	orig := os.Stdout
+	os.Stdout = w
+	defer func() {
+		os.Stdout = orig
+	}()
+
+	runErr := fn()
+	os.Stdout = orig
+	_ = w.Close()
+	out := <-done
+	_ = r.Close()
+
+	return out, runErr
+}
+
+// scriptOutput embeds a line's JSON output in the report; anything else
+// (NDJSON streams, raw downloads) is kept as a string.
+func scriptOutput(out []byte) any {
+	out = bytes.TrimSpace(out)
+	if len(out) == 0 {
+		return nil
+	}
+	if json.Valid(out) {
+		return json.RawMessage(out)
+	}
+	return string(out)
+}
@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitScriptLine(t *testing.T) {
+	vars := map[string]string{"q": "is:unread", "id": "f1"}
+	lookup := func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}
+
+	cases := []struct {
+		in   string
+		want []string
+	}{
+		{`gmail search "$q newer_than:1d" --max 5`, []string{"gmail", "search", "is:unread newer_than:1d", "--max", "5"}},
+		{`drive get ${id}x '$id' \$id`, []string{"drive", "get", "f1x", "$id", "$id"}},
+		{`drive rename f1 "a \"b\"" # trailing comment`, []string{"drive", "rename", "f1", `a "b"`}},
+		{`api drive GET files -p q="name = 'x'" ""`, []string{"api", "drive", "GET", "files", "-p", "q=name = 'x'", ""}},
+		{`echo $ a#b`, []string{"echo", "$", "a#b"}},
+	}
+	for _, tc := range cases {
+		got, err := splitScriptLine(tc.in, lookup)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.in, err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("%s:\n got %q\nwant %q", tc.in, got, tc.want)
+		}
+	}
+
+	for _, bad := range []string{`drive get "open`, `drive get 'open`, `drive get $missing`, `drive get ${id`} {
+		if _, err := splitScriptLine(bad, lookup); err == nil {
+			t.Fatalf("%s: expected error", bad)
+		}
+	}
+}
+
+func TestParseScript(t *testing.T) {
+	src := "# nightly export\n" +
+		"\n" +
+		"folder=abc\n" +
+		"gog drive ls --parent $folder \\\n" +
+		"  --max 10\n" +
+		"name=\"$folder report\"\n" +
+		"drive search \"$name\"\n"
+
+	lines, err := parseScript(src, map[string]string{})
+	if err != nil {
+		t.Fatalf("parseScript: %v", err)
+	}
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %+v", lines)
+	}
+	if lines[0].Num != 4 || !reflect.DeepEqual(lines[0].Args, []string{"drive", "ls", "--parent", "abc", "--max", "10"}) {
+		t.Fatalf("unexpected first line: %+v", lines[0])
+	}
+	if lines[1].Num != 7 || !reflect.DeepEqual(lines[1].Args, []string{"drive", "search", "abc report"}) {
+		t.Fatalf("unexpected second line: %+v", lines[1])
+	}
+
+	if _, err := parseScript("drive get $nope_not_set_anywhere\n", map[string]string{}); err == nil || ExitCode(err) != 2 {
+		t.Fatalf("expected usage error for undefined variable, got %v", err)
+	}
+}
+
+func writeScript(t *testing.T, body string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "script.gog")
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatalf("write script: %v", err)
+	}
+	return path
+}
+
+func TestExecute_Run_JSONReport(t *testing.T) {
+	path := writeScript(t, "time now --timezone $tz\nnope\nconfig path\n")
+
+	var err error
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			err = Execute([]string{"--json", "run", path, "--on-error", "continue", "--var", "tz=UTC"})
+		})
+	})
+	if err == nil || ExitCode(err) != 1 {
+		t.Fatalf("expected failure summary, got %v", err)
+	}
+
+	var report struct {
+		Results []struct {
+			Line     int             `json:"line"`
+			Command  string          `json:"command"`
+			ExitCode int             `json:"exitCode"`
+			Output   json.RawMessage `json:"output"`
+			Error    *struct {
+				Code string `json:"code"`
+			} `json:"error"`
+		} `json:"results"`
+		Succeeded int `json:"succeeded"`
+		Failed    int `json:"failed"`
+		Skipped   int `json:"skipped"`
+	}
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if len(report.Results) != 3 || report.Succeeded != 2 || report.Failed != 1 || report.Skipped != 0 {
+		t.Fatalf("unexpected report: %s", out)
+	}
+	first := report.Results[0]
+	if first.Line != 1 || first.Command != "time now --timezone $tz" || first.ExitCode != 0 || !strings.Contains(string(first.Output), `"timezone"`) {
+		t.Fatalf("unexpected first result: %s", out)
+	}
+	if second := report.Results[1]; second.ExitCode != 2 || second.Error == nil || second.Error.Code != "usage" {
+		t.Fatalf("unexpected second result: %s", out)
+	}
+}
+
+func TestExecute_Run_StopsOnError(t *testing.T) {
+	path := writeScript(t, "nope\ntime now\n")
+
+	var err error
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			err = Execute([]string{"run", path})
+		})
+	})
+	if err == nil || ExitCode(err) != 2 || !strings.Contains(err.Error(), "line 1") {
+		t.Fatalf("expected line 1 usage error, got %v (%d)", err, ExitCode(err))
+	}
+	if strings.TrimSpace(out) != "" {
+		t.Fatalf("expected later lines to be skipped, got %q", out)
+	}
+}
+
+func TestExecute_Run_EnforcesEnabledCommands(t *testing.T) {
+	path := writeScript(t, "config path --enable-commands config,time\n")
+
+	var err error
+	_ = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			err = Execute([]string{"--enable-commands", "run,time", "run", path})
+		})
+	})
+	if err == nil || !strings.Contains(err.Error(), "not enabled") {
+		t.Fatalf("expected allowlist error, got %v", err)
+	}
+}
+
+func TestExecute_Run_RejectsNesting(t *testing.T) {
+	inner := writeScript(t, "time now\n")
+	outer := writeScript(t, "run "+inner+"\n")
+
+	var err error
+	_ = captureStderr(t, func() {
+		err = Execute([]string{"run", outer})
+	})
+	if err == nil || !strings.Contains(err.Error(), "cannot be nested") {
+		t.Fatalf("expected nesting error, got %v", err)
+	}
+}
+
+func TestExecute_Run_OutputFormatJSONLines(t *testing.T) {
+	path := writeScript(t, "time now --timezone UTC\n")
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--output-format", "json", "run", path}); err != nil {
+				t.Fatalf("run: %v", err)
+			}
+		})
+	})
+
+	var report struct {
+		Results []struct {
+			Output json.RawMessage `json:"output"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if len(report.Results) != 1 || !strings.HasPrefix(string(report.Results[0].Output), "{") {
+		t.Fatalf("expected structured line output, got %s", out)
+	}
+}
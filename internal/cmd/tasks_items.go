@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
@@ -20,18 +21,19 @@ const (
 )
 
 type TasksListCmd struct {
-	TasklistID    string `arg:"" name:"tasklistId" help:"Task list ID"`
-	Max           int64  `name:"max" aliases:"limit" help:"Max results (max allowed: 100)" default:"20"`
-	Page          string `name:"page" help:"Page token"`
-	ShowCompleted bool   `name:"show-completed" help:"Include completed tasks (requires --show-hidden for some clients)" default:"true"`
-	ShowDeleted   bool   `name:"show-deleted" help:"Include deleted tasks"`
-	ShowHidden    bool   `name:"show-hidden" help:"Include hidden tasks"`
-	ShowAssigned  bool   `name:"show-assigned" help:"Include tasks assigned to current user" default:"true"`
-	DueMin        string `name:"due-min" help:"Lower bound for due date filter (RFC3339)"`
-	DueMax        string `name:"due-max" help:"Upper bound for due date filter (RFC3339)"`
-	CompletedMin  string `name:"completed-min" help:"Lower bound for completion date filter (RFC3339)"`
-	CompletedMax  string `name:"completed-max" help:"Upper bound for completion date filter (RFC3339)"`
-	UpdatedMin    string `name:"updated-min" help:"Lower bound for updated time filter (RFC3339)"`
+	TasklistID    string      `arg:"" name:"tasklistId" help:"Task list ID"`
+	Max           int64       `name:"max" aliases:"limit" help:"Max results (max allowed: 100)" default:"20"`
+	Page          string      `name:"page" help:"Page token"`
+	Paging        PagingFlags `embed:""`
+	ShowCompleted bool        `name:"show-completed" help:"Include completed tasks (requires --show-hidden for some clients)" default:"true"`
+	ShowDeleted   bool        `name:"show-deleted" help:"Include deleted tasks"`
+	ShowHidden    bool        `name:"show-hidden" help:"Include hidden tasks"`
+	ShowAssigned  bool        `name:"show-assigned" help:"Include tasks assigned to current user" default:"true"`
+	DueMin        string      `name:"due-min" help:"Lower bound for due date filter (RFC3339)"`
+	DueMax        string      `name:"due-max" help:"Upper bound for due date filter (RFC3339)"`
+	CompletedMin  string      `name:"completed-min" help:"Lower bound for completion date filter (RFC3339)"`
+	CompletedMax  string      `name:"completed-max" help:"Upper bound for completion date filter (RFC3339)"`
+	UpdatedMin    string      `name:"updated-min" help:"Lower bound for updated time filter (RFC3339)"`
 }
 
 func (c *TasksListCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -50,58 +52,77 @@ func (c *TasksListCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 
-	call := svc.Tasks.List(tasklistID).
-		MaxResults(c.Max).
-		PageToken(c.Page).
-		ShowCompleted(c.ShowCompleted).
-		ShowDeleted(c.ShowDeleted).
-		ShowHidden(c.ShowHidden).
-		ShowAssigned(c.ShowAssigned)
-	if strings.TrimSpace(c.DueMin) != "" {
-		call = call.DueMin(strings.TrimSpace(c.DueMin))
-	}
-	if strings.TrimSpace(c.DueMax) != "" {
-		call = call.DueMax(strings.TrimSpace(c.DueMax))
-	}
-	if strings.TrimSpace(c.CompletedMin) != "" {
-		call = call.CompletedMin(strings.TrimSpace(c.CompletedMin))
-	}
-	if strings.TrimSpace(c.CompletedMax) != "" {
-		call = call.CompletedMax(strings.TrimSpace(c.CompletedMax))
+	fetch := func(pageToken string) ([]*tasks.Task, string, error) {
+		call := svc.Tasks.List(tasklistID).
+			MaxResults(c.Max).
+			PageToken(pageToken).
+			ShowCompleted(c.ShowCompleted).
+			ShowDeleted(c.ShowDeleted).
+			ShowHidden(c.ShowHidden).
+			ShowAssigned(c.ShowAssigned)
+		if strings.TrimSpace(c.DueMin) != "" {
+			call = call.DueMin(strings.TrimSpace(c.DueMin))
+		}
+		if strings.TrimSpace(c.DueMax) != "" {
+			call = call.DueMax(strings.TrimSpace(c.DueMax))
+		}
+		if strings.TrimSpace(c.CompletedMin) != "" {
+			call = call.CompletedMin(strings.TrimSpace(c.CompletedMin))
+		}
+		if strings.TrimSpace(c.CompletedMax) != "" {
+			call = call.CompletedMax(strings.TrimSpace(c.CompletedMax))
+		}
+		if strings.TrimSpace(c.UpdatedMin) != "" {
+			call = call.UpdatedMin(strings.TrimSpace(c.UpdatedMin))
+		}
+
+		resp, err := call.Do()
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Items, resp.NextPageToken, nil
 	}
-	if strings.TrimSpace(c.UpdatedMin) != "" {
-		call = call.UpdatedMin(strings.TrimSpace(c.UpdatedMin))
+	if c.Paging.AllPages {
+		return streamAllPages(ctx, c.Paging, c.Page, fetch, tasksTable)
 	}
 
-	resp, err := call.Do()
+	items, nextPageToken, err := fetch(c.Page)
 	if err != nil {
 		return err
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
-			"tasks":         resp.Items,
-			"nextPageToken": resp.NextPageToken,
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
+			"tasks":         items,
+			"nextPageToken": nextPageToken,
 		})
 	}
 
-	if len(resp.Items) == 0 {
+	if len(items) == 0 {
 		u.Err().Println("No tasks")
 		return nil
 	}
 
 	w, flush := tableWriter(ctx)
 	defer flush()
-	fmt.Fprintln(w, "ID\tTITLE\tSTATUS\tDUE\tUPDATED")
-	for _, t := range resp.Items {
+	fmt.Fprintln(w, tasksTable.header)
+	for _, t := range items {
+		tasksTable.row(w, t)
+	}
+	printNextPageHint(u, nextPageToken)
+	return nil
+}
+
+var tasksTable = pageTable[*tasks.Task]{
+	header: "ID\tTITLE\tSTATUS\tDUE\tUPDATED",
+	row: func(w io.Writer, t *tasks.Task) {
 		status := strings.TrimSpace(t.Status)
 		if status == "" {
 			status = taskStatusNeedsAction
 		}
 		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", t.Id, t.Title, status, strings.TrimSpace(t.Due), strings.TrimSpace(t.Updated))
-	}
-	printNextPageHint(u, resp.NextPageToken)
-	return nil
+	},
+	empty: "No tasks",
 }
 
 type TasksGetCmd struct {
@@ -134,7 +155,7 @@ func (c *TasksGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"task": task})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"task": task})
 	}
 	u.Out().Printf("id\t%s", task.Id)
 	u.Out().Printf("title\t%s", task.Title)
@@ -212,7 +233,7 @@ func (c *TasksAddCmd) Run(ctx context.Context, flags *RootFlags) error {
 			return createErr
 		}
 		if outfmt.IsJSON(ctx) {
-			return outfmt.WriteJSON(os.Stdout, map[string]any{"task": created})
+			return outfmt.Write(ctx, os.Stdout, map[string]any{"task": created})
 		}
 		u.Out().Printf("id\t%s", created.Id)
 		u.Out().Printf("title\t%s", created.Title)
@@ -312,7 +333,7 @@ func (c *TasksAddCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"tasks": createdTasks,
 			"count": len(createdTasks),
 		})
@@ -409,7 +430,7 @@ func (c *TasksUpdateCmd) Run(ctx context.Context, kctx *kong.Context, flags *Roo
 	}
 
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"task": updated})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"task": updated})
 	}
 	u.Out().Printf("id\t%s", updated.Id)
 	u.Out().Printf("title\t%s", updated.Title)
@@ -450,12 +471,19 @@ func (c *TasksDoneCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 
+	// The previous status is only needed for the undo journal.
+	before, beforeErr := svc.Tasks.Get(tasklistID, taskID).Fields("status,completed").Context(ctx).Do()
+
 	updated, err := svc.Tasks.Patch(tasklistID, taskID, &tasks.Task{Status: taskStatusCompleted}).Do()
 	if err != nil {
 		return err
 	}
+	if beforeErr == nil && before.Status != "" {
+		recordUndo(flags, account, undoKindTasksStatus, fmt.Sprintf("tasks done %s", taskID),
+			undoTasksStatus{TasklistID: tasklistID, TaskID: taskID, Status: before.Status, Completed: before.Completed})
+	}
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"task": updated})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"task": updated})
 	}
 	u.Out().Printf("id\t%s", updated.Id)
 	u.Out().Printf("status\t%s", strings.TrimSpace(updated.Status))
@@ -487,12 +515,19 @@ func (c *TasksUndoCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 
+	// The previous status is only needed for the undo journal.
+	before, beforeErr := svc.Tasks.Get(tasklistID, taskID).Fields("status,completed").Context(ctx).Do()
+
 	updated, err := svc.Tasks.Patch(tasklistID, taskID, &tasks.Task{Status: "needsAction"}).Do()
 	if err != nil {
 		return err
 	}
+	if beforeErr == nil && before.Status != "" {
+		recordUndo(flags, account, undoKindTasksStatus, fmt.Sprintf("tasks undo %s", taskID),
+			undoTasksStatus{TasklistID: tasklistID, TaskID: taskID, Status: before.Status, Completed: before.Completed})
+	}
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"task": updated})
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"task": updated})
 	}
 	u.Out().Printf("id\t%s", updated.Id)
 	u.Out().Printf("status\t%s", strings.TrimSpace(updated.Status))
@@ -532,7 +567,7 @@ func (c *TasksDeleteCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"deleted": true,
 			"id":      taskID,
 		})
@@ -570,7 +605,7 @@ func (c *TasksClearCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"cleared":    true,
 			"tasklistId": tasklistID,
 		})
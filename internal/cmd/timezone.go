@@ -17,18 +17,20 @@ const (
 )
 
 const (
-	flagTimezoneLabel   = "timezone"
-	envTimezoneLabel    = "GOG_TIMEZONE"
-	configTimezoneLabel = "default_timezone"
-	warnConfigFallback  = "warning: invalid %s in config %q, using local timezone\n"
-	warnConfigIgnore    = "warning: invalid %s in config %q, ignoring\n"
+	flagTimezoneLabel    = "timezone"
+	envTimezoneLabel     = "GOG_TIMEZONE"
+	profileTimezoneLabel = "profile timezone"
+	configTimezoneLabel  = "default_timezone"
+	warnConfigFallback   = "warning: invalid %s in config %q, using local timezone\n"
+	warnConfigIgnore     = "warning: invalid %s in config %q, ignoring\n"
 )
 
 func resolveOutputLocation(timezone string, local bool) (*time.Location, error) {
 	return resolveTimezone(timezone, local, timezoneWithFallback)
 }
 
-// getConfiguredTimezone returns the timezone from flag, env var, or config file.
+// getConfiguredTimezone returns the timezone from flag, env var, profile, or
+// config file.
 // Returns nil if no timezone is explicitly configured. The special value "local"
 // returns time.Local to explicitly use the local timezone.
 func getConfiguredTimezone(timezone string) (*time.Location, error) {
@@ -48,6 +50,10 @@ func resolveTimezone(timezone string, local bool, mode timezoneResolveMode) (*ti
 		return loc, err
 	}
 
+	if loc, ok, err := parseTimezoneValue(profileTimezoneLabel, profileTimezone, false); ok || err != nil {
+		return loc, err
+	}
+
 	if cfg, ok := readConfigOptional(); ok && cfg.DefaultTimezone != "" {
 		loc, ok, err := parseTimezoneValue(configTimezoneLabel, cfg.DefaultTimezone, false)
 		if ok {
@@ -0,0 +1,392 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/tasks/v1"
+
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+	"github.com/steipete/gogcli/internal/undo"
+)
+
+// Undo journal kinds and their inverse payloads.
+const (
+	undoKindGmailThreadLabels   = "gmail.thread.labels"
+	undoKindGmailMessagesLabels = "gmail.messages.labels"
+	undoKindDriveMove           = "drive.move"
+	undoKindDriveRename         = "drive.rename"
+	undoKindTasksStatus         = "tasks.status"
+	undoKindCalendarEvent       = "calendar.event"
+)
+
+// undoGmailLabels restores the labels each message had before a modify.
+// Messages that need the same inverse share one change.
+type undoGmailLabels struct {
+	Changes []undoGmailLabelChange `json:"changes"`
+}
+
+type undoGmailLabelChange struct {
+	IDs    []string `json:"ids"`
+	Add    []string `json:"add,omitempty"`
+	Remove []string `json:"remove,omitempty"`
+}
+
+type undoDriveMove struct {
+	FileID        string   `json:"fileId"`
+	AddParents    []string `json:"addParents"`
+	RemoveParents []string `json:"removeParents"`
+}
+
+type undoDriveRename struct {
+	FileID string `json:"fileId"`
+	Name   string `json:"name"`
+}
+
+type undoTasksStatus struct {
+	TasklistID string  `json:"tasklistId"`
+	TaskID     string  `json:"taskId"`
+	Status     string  `json:"status"`
+	Completed  *string `json:"completed,omitempty"`
+}
+
+type undoCalendarEvent struct {
+	CalendarID string `json:"calendarId"`
+	// Snapshot is the event before the update; it is written back in full.
+	Snapshot *calendar.Event `json:"snapshot"`
+	// ParentID/ParentRecurrence restore a series split by --scope future.
+	ParentID         string   `json:"parentId,omitempty"`
+	ParentRecurrence []string `json:"parentRecurrence,omitempty"`
+}
+
+// recordUndo journals the inverse of a successful operation. Journal
+// failures never fail the command that already succeeded.
+func recordUndo(flags *RootFlags, account, kind, summary string, inverse any) {
+	if flags != nil && flags.DryRun {
+		return
+	}
+	j, err := undo.Open()
+	if err == nil {
+		_, err = j.Record(account, kind, summary, inverse)
+	}
+	if err != nil {
+		slog.Warn("undo journal write failed", "err", err)
+	}
+}
+
+type UndoCmd struct {
+	ID   string `arg:"" name:"journalId" optional:"" help:"Journal entry ID (or unique prefix); default: most recent"`
+	Last int    `name:"last" help:"Undo the N most recent pending operations" default:"0"`
+	List bool   `name:"list" help:"List journal entries instead of undoing"`
+}
+
+func (c *UndoCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	j, err := undo.Open()
+	if err != nil {
+		return err
+	}
+
+	if c.List {
+		return c.list(ctx, u, j)
+	}
+
+	id := strings.TrimSpace(c.ID)
+	if id != "" && c.Last > 0 {
+		return usage("use either <journalId> or --last, not both")
+	}
+	if c.Last < 0 {
+		return usage("--last must be positive")
+	}
+
+	var entries []undo.Entry
+	if id != "" {
+		e, ok, getErr := j.Get(id)
+		if getErr != nil {
+			return getErr
+		}
+		if !ok {
+			return usagef("undo entry not found: %s", id)
+		}
+		if e.Undone() {
+			return usagef("undo entry %s was already undone", e.ID)
+		}
+		entries = []undo.Entry{e}
+	} else {
+		n := c.Last
+		if n == 0 {
+			n = 1
+		}
+		entries, err = j.Pending(n)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(entries) == 0 {
+		u.Err().Println("Nothing to undo")
+		return nil
+	}
+
+	undone := make([]undo.Entry, 0, len(entries))
+	for _, e := range entries {
+		if err := applyUndo(ctx, e); err != nil {
+			return fmt.Errorf("undo %s (%s): %w", e.ID, e.Summary, err)
+		}
+		if !flags.DryRun {
+			if err := j.MarkUndone(e.ID, time.Now()); err != nil {
+				return err
+			}
+		}
+		undone = append(undone, e)
+		if !outfmt.IsJSON(ctx) {
+			u.Out().Printf("undone\t%s\t%s", e.ID, e.Summary)
+		}
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"undone": undone})
+	}
+	return nil
+}
+
+func (c *UndoCmd) list(ctx context.Context, u *ui.UI, j *undo.Journal) error {
+	entries, err := j.List()
+	if err != nil {
+		return err
+	}
+	if outfmt.IsJSON(ctx) {
+		return outfmt.Write(ctx, os.Stdout, map[string]any{"entries": entries})
+	}
+	if len(entries) == 0 {
+		u.Err().Println("No journal entries")
+		return nil
+	}
+
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "ID\tTIME\tACCOUNT\tKIND\tSUMMARY\tSTATE")
+	for _, e := range entries {
+		state := "pending"
+		if e.Undone() {
+			state = "undone"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			e.ID,
+			e.Time.Local().Format("2006-01-02 15:04:05"),
+			sanitizeTab(e.Account),
+			e.Kind,
+			sanitizeTab(e.Summary),
+			state,
+		)
+	}
+	return nil
+}
+
+func applyUndo(ctx context.Context, e undo.Entry) error {
+	switch e.Kind {
+	case undoKindGmailThreadLabels, undoKindGmailMessagesLabels:
+		var inv undoGmailLabels
+		if err := json.Unmarshal(e.Inverse, &inv); err != nil {
+			return err
+		}
+		svc, err := newGmailService(ctx, e.Account)
+		if err != nil {
+			return err
+		}
+		for _, ch := range inv.Changes {
+			if err := svc.Users.Messages.BatchModify("me", &gmail.BatchModifyMessagesRequest{
+				Ids:            ch.IDs,
+				AddLabelIds:    ch.Add,
+				RemoveLabelIds: ch.Remove,
+			}).Context(ctx).Do(); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case undoKindDriveMove:
+		var inv undoDriveMove
+		if err := json.Unmarshal(e.Inverse, &inv); err != nil {
+			return err
+		}
+		svc, err := newDriveService(ctx, e.Account)
+		if err != nil {
+			return err
+		}
+		call := svc.Files.Update(inv.FileID, &drive.File{}).SupportsAllDrives(true)
+		if len(inv.AddParents) > 0 {
+			call = call.AddParents(strings.Join(inv.AddParents, ","))
+		}
+		if len(inv.RemoveParents) > 0 {
+			call = call.RemoveParents(strings.Join(inv.RemoveParents, ","))
+		}
+		_, err = call.Context(ctx).Do()
+		return err
+
+	case undoKindDriveRename:
+		var inv undoDriveRename
+		if err := json.Unmarshal(e.Inverse, &inv); err != nil {
+			return err
+		}
+		svc, err := newDriveService(ctx, e.Account)
+		if err != nil {
+			return err
+		}
+		_, err = svc.Files.Update(inv.FileID, &drive.File{Name: inv.Name}).SupportsAllDrives(true).Context(ctx).Do()
+		return err
+
+	case undoKindTasksStatus:
+		var inv undoTasksStatus
+		if err := json.Unmarshal(e.Inverse, &inv); err != nil {
+			return err
+		}
+		svc, err := newTasksService(ctx, e.Account)
+		if err != nil {
+			return err
+		}
+		patch := &tasks.Task{Status: inv.Status}
+		switch {
+		case inv.Status == "needsAction":
+			patch.NullFields = []string{"Completed"}
+		case inv.Completed != nil:
+			patch.Completed = inv.Completed
+		}
+		_, err = svc.Tasks.Patch(inv.TasklistID, inv.TaskID, patch).Context(ctx).Do()
+		return err
+
+	case undoKindCalendarEvent:
+		var inv undoCalendarEvent
+		if err := json.Unmarshal(e.Inverse, &inv); err != nil {
+			return err
+		}
+		if inv.Snapshot == nil {
+			return fmt.Errorf("missing event snapshot")
+		}
+		svc, err := newCalendarService(ctx, e.Account)
+		if err != nil {
+			return err
+		}
+		if inv.ParentID != "" && len(inv.ParentRecurrence) > 0 {
+			if _, err := svc.Events.Patch(inv.CalendarID, inv.ParentID, &calendar.Event{Recurrence: inv.ParentRecurrence}).Context(ctx).Do(); err != nil {
+				return err
+			}
+		}
+		_, err = svc.Events.Update(inv.CalendarID, inv.Snapshot.Id, restorableEvent(inv.Snapshot)).Context(ctx).Do()
+		return err
+
+	default:
+		return fmt.Errorf("unsupported undo kind %q", e.Kind)
+	}
+}
+
+// snapshotMessageLabels returns the labelIds of each message, for computing
+// the inverse of a label modify.
+func snapshotMessageLabels(ctx context.Context, svc *gmail.Service, batch *googleapi.BatchClient, ids []string) (map[string][]string, error) {
+	out := make(map[string][]string, len(ids))
+	if batch != nil && len(ids) > 1 {
+		query := url.Values{"format": {"minimal"}, "fields": {"id,labelIds"}}
+		urls := make([]string, len(ids))
+		for i, id := range ids {
+			urls[i] = batchURL(svc.BasePath, "gmail/v1/users/me/messages/"+url.PathEscape(id), query)
+		}
+		got, errs := batchGetJSON[gmail.Message](ctx, batch, urls)
+		for i, id := range ids {
+			if errs[i] != nil {
+				return nil, fmt.Errorf("message %s: %w", id, errs[i])
+			}
+			out[id] = got[i].LabelIds
+		}
+		return out, nil
+	}
+
+	for _, id := range ids {
+		msg, err := svc.Users.Messages.Get("me", id).Format("minimal").Fields("id,labelIds").Context(ctx).Do()
+		if err != nil {
+			return nil, fmt.Errorf("message %s: %w", id, err)
+		}
+		out[id] = msg.LabelIds
+	}
+	return out, nil
+}
+
+// snapshotThreadLabels returns the message IDs of a thread and their
+// labelIds.
+func snapshotThreadLabels(ctx context.Context, svc *gmail.Service, threadID string) ([]string, map[string][]string, error) {
+	thread, err := svc.Users.Threads.Get("me", threadID).Format("minimal").Fields("messages(id,labelIds)").Context(ctx).Do()
+	if err != nil {
+		return nil, nil, err
+	}
+	ids := make([]string, 0, len(thread.Messages))
+	out := make(map[string][]string, len(thread.Messages))
+	for _, m := range thread.Messages {
+		if m == nil || m.Id == "" {
+			continue
+		}
+		ids = append(ids, m.Id)
+		out[m.Id] = m.LabelIds
+	}
+	return ids, out, nil
+}
+
+// gmailLabelsInverse undoes only what a modify changed: labels it added
+// that a message did not have, and labels it removed that a message had.
+func gmailLabelsInverse(ids []string, before map[string][]string, addIDs, removeIDs []string) undoGmailLabels {
+	inv := undoGmailLabels{Changes: []undoGmailLabelChange{}}
+	index := make(map[string]int)
+	for _, id := range ids {
+		had := make(map[string]bool, len(before[id]))
+		for _, l := range before[id] {
+			had[l] = true
+		}
+		var add, remove []string
+		for _, l := range removeIDs {
+			if had[l] {
+				add = append(add, l)
+			}
+		}
+		for _, l := range addIDs {
+			if !had[l] {
+				remove = append(remove, l)
+			}
+		}
+		if len(add) == 0 && len(remove) == 0 {
+			continue
+		}
+		key := strings.Join(add, ",") + "|" + strings.Join(remove, ",")
+		if i, ok := index[key]; ok {
+			inv.Changes[i].IDs = append(inv.Changes[i].IDs, id)
+			continue
+		}
+		index[key] = len(inv.Changes)
+		inv.Changes = append(inv.Changes, undoGmailLabelChange{IDs: []string{id}, Add: add, Remove: remove})
+	}
+	return inv
+}
+
+// restorableEvent strips server-managed fields from an event snapshot so it
+// can be written back with Events.Update.
+func restorableEvent(ev *calendar.Event) *calendar.Event {
+	cp := *ev
+	cp.Etag = ""
+	cp.HtmlLink = ""
+	cp.ICalUID = ""
+	cp.Created = ""
+	cp.Updated = ""
+	cp.Sequence = 0
+	cp.Creator = nil
+	cp.Organizer = nil
+	cp.Kind = ""
+	return &cp
+}
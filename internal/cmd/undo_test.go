@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+	"google.golang.org/api/tasks/v1"
+
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/internal/undo"
+)
+
+func TestExecute_UndoDriveRenameAndMove(t *testing.T) {
+	origNew := newDriveService
+	t.Cleanup(func() { newDriveService = origNew })
+
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	j, err := undo.Open()
+	if err != nil {
+		t.Fatalf("undo.Open: %v", err)
+	}
+
+	var patches []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "id1", "name": "Old", "parents": []string{"p0"}})
+		case http.MethodPatch:
+			body, _ := io.ReadAll(r.Body)
+			q := r.URL.Query()
+			patches = append(patches, strings.TrimSpace(string(body))+" add="+q.Get("addParents")+" remove="+q.Get("removeParents"))
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "id1", "name": "New"})
+		default:
+			t.Fatalf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	svc, err := drive.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newDriveService = func(context.Context, string) (*drive.Service, error) { return svc, nil }
+
+	_ = captureStdout(t, func() {
+		if err := Execute([]string{"--account", "a@b.com", "drive", "rename", "id1", "New"}); err != nil {
+			t.Fatalf("rename: %v", err)
+		}
+		if err := Execute([]string{"--account", "a@b.com", "drive", "move", "id1", "--parent", "np"}); err != nil {
+			t.Fatalf("move: %v", err)
+		}
+	})
+
+	pending, err := j.Pending(0)
+	if err != nil || len(pending) != 2 || pending[0].Kind != undoKindDriveMove || pending[1].Kind != undoKindDriveRename {
+		t.Fatalf("unexpected journal: %+v %v", pending, err)
+	}
+
+	patches = nil
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--json", "undo", "--last", "2"}); err != nil {
+			t.Fatalf("undo: %v", err)
+		}
+	})
+	if len(patches) != 2 {
+		t.Fatalf("expected 2 inverse calls, got %v", patches)
+	}
+	if !strings.HasSuffix(patches[0], "add=p0 remove=np") {
+		t.Fatalf("unexpected move inverse: %q", patches[0])
+	}
+	if !strings.Contains(patches[1], `"name":"Old"`) {
+		t.Fatalf("unexpected rename inverse: %q", patches[1])
+	}
+	var parsed struct {
+		Undone []undo.Entry `json:"undone"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil || len(parsed.Undone) != 2 {
+		t.Fatalf("unexpected undo output %q: %v", out, err)
+	}
+
+	errOut := captureStderr(t, func() {
+		if err := Execute([]string{"undo"}); err != nil {
+			t.Fatalf("undo (empty): %v", err)
+		}
+	})
+	if !strings.Contains(errOut, "Nothing to undo") {
+		t.Fatalf("expected nothing to undo, got %q", errOut)
+	}
+}
+
+func TestExecute_UndoDryRunSkipsJournal(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	j, err := undo.Open()
+	if err != nil {
+		t.Fatalf("undo.Open: %v", err)
+	}
+
+	recordUndo(&RootFlags{DryRun: true}, "a@b.com", undoKindDriveRename, "dry", undoDriveRename{FileID: "x", Name: "y"})
+	recordUndo(&RootFlags{}, "a@b.com", undoKindDriveRename, "real", undoDriveRename{FileID: "x", Name: "y"})
+
+	entries, _ := j.List()
+	if len(entries) != 1 || entries[0].Summary != "real" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+}
+
+func TestExecute_UndoGmailModifyRestoresPriorLabels(t *testing.T) {
+	origNew, origBatch := newGmailService, newGmailBatch
+	t.Cleanup(func() { newGmailService, newGmailBatch = origNew, origBatch })
+	newGmailBatch = func(context.Context, string) (*googleapi.BatchClient, error) { return nil, errors.New("no batch") }
+
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	labels := map[string][]string{"m1": {"STARRED", "Label_1"}, "m2": {"INBOX"}}
+	var modifies []gmail.BatchModifyMessagesRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/users/me/labels"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"labels": []map[string]any{
+				{"id": "STARRED", "name": "STARRED", "type": "system"},
+				{"id": "Label_1", "name": "Custom", "type": "user"},
+			}})
+		case strings.HasSuffix(r.URL.Path, "/messages/batchModify"):
+			var req gmail.BatchModifyMessagesRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			modifies = append(modifies, req)
+			_ = json.NewEncoder(w).Encode(map[string]any{})
+		case r.Method == http.MethodGet && strings.Contains(r.URL.Path, "/users/me/messages/"):
+			id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": id, "labelIds": labels[id]})
+		default:
+			t.Fatalf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	_ = captureStdout(t, func() {
+		if err := Execute([]string{"--account", "a@b.com", "gmail", "messages", "modify", "--ids", "m1,m2", "--add-label", "STARRED", "--remove-label", "Custom"}); err != nil {
+			t.Fatalf("modify: %v", err)
+		}
+		modifies = nil
+		if err := Execute([]string{"undo"}); err != nil {
+			t.Fatalf("undo: %v", err)
+		}
+	})
+
+	// m1 already had STARRED and lost Custom; m2 gained STARRED only.
+	if len(modifies) != 2 {
+		t.Fatalf("expected 2 inverse calls, got %+v", modifies)
+	}
+	if got := modifies[0]; strings.Join(got.Ids, ",") != "m1" || strings.Join(got.AddLabelIds, ",") != "Label_1" || len(got.RemoveLabelIds) != 0 {
+		t.Fatalf("unexpected m1 inverse: %+v", got)
+	}
+	if got := modifies[1]; strings.Join(got.Ids, ",") != "m2" || len(got.AddLabelIds) != 0 || strings.Join(got.RemoveLabelIds, ",") != "STARRED" {
+		t.Fatalf("unexpected m2 inverse: %+v", got)
+	}
+}
+
+func TestExecute_UndoTasksRestoresPriorStatus(t *testing.T) {
+	origNew := newTasksService
+	t.Cleanup(func() { newTasksService = origNew })
+
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	var patches []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]any{"status": "completed", "completed": "2026-01-02T03:04:05.000Z"})
+		case http.MethodPatch:
+			body, _ := io.ReadAll(r.Body)
+			patches = append(patches, strings.TrimSpace(string(body)))
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "t1", "status": "completed"})
+		default:
+			t.Fatalf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	svc, err := tasks.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newTasksService = func(context.Context, string) (*tasks.Service, error) { return svc, nil }
+
+	_ = captureStdout(t, func() {
+		if err := Execute([]string{"--account", "a@b.com", "tasks", "done", "l1", "t1"}); err != nil {
+			t.Fatalf("done: %v", err)
+		}
+		patches = nil
+		if err := Execute([]string{"undo"}); err != nil {
+			t.Fatalf("undo: %v", err)
+		}
+	})
+
+	// The task was already completed: undo keeps it completed, with its
+	// original completion time.
+	if len(patches) != 1 || !strings.Contains(patches[0], `"status":"completed"`) || !strings.Contains(patches[0], `"completed":"2026-01-02T03:04:05.000Z"`) {
+		t.Fatalf("unexpected inverse: %v", patches)
+	}
+}
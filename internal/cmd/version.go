@@ -36,7 +36,7 @@ type VersionCmd struct{}
 
 func (c *VersionCmd) Run(ctx context.Context) error {
 	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
+		return outfmt.Write(ctx, os.Stdout, map[string]any{
 			"version": strings.TrimSpace(version),
 			"commit":  strings.TrimSpace(commit),
 			"date":    strings.TrimSpace(date),
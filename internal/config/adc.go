@@ -0,0 +1,67 @@
+package config
+
+import "strings"
+
+// ADCAccount makes an account authenticate with Application Default
+// Credentials instead of a stored refresh token or service account key.
+//
+// CredentialsFile overrides the ADC lookup (GOOGLE_APPLICATION_CREDENTIALS,
+// the gcloud application_default_credentials.json file, the metadata
+// server). Impersonate names a service account whose tokens are minted
+// through the IAM Credentials API; for any other account email this is
+// domain-wide delegation via signJwt, so no key file is needed.
+type ADCAccount struct {
+	CredentialsFile string   `json:"credentials_file,omitempty"`
+	Impersonate     string   `json:"impersonate,omitempty"`
+	Delegates       []string `json:"delegates,omitempty"`
+}
+
+func ReadADCAccount(email string) (ADCAccount, bool, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return ADCAccount{}, false, nil
+	}
+
+	cfg, err := ReadConfig()
+	if err != nil {
+		return ADCAccount{}, false, err
+	}
+
+	a, ok := cfg.ADCAccounts[email]
+
+	return a, ok, nil
+}
+
+func SetADCAccount(email string, a ADCAccount) error {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	cfg, err := ReadConfig()
+	if err != nil {
+		return err
+	}
+
+	if cfg.ADCAccounts == nil {
+		cfg.ADCAccounts = map[string]ADCAccount{}
+	}
+
+	cfg.ADCAccounts[email] = a
+
+	return WriteConfig(cfg)
+}
+
+func DeleteADCAccount(email string) (bool, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	cfg, err := ReadConfig()
+	if err != nil {
+		return false, err
+	}
+
+	if _, ok := cfg.ADCAccounts[email]; !ok {
+		return false, nil
+	}
+
+	delete(cfg.ADCAccounts, email)
+
+	return true, WriteConfig(cfg)
+}
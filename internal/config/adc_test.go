@@ -0,0 +1,39 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestADCAccountsCRUD(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg-config"))
+
+	want := ADCAccount{Impersonate: "bot@p.iam.gserviceaccount.com", Delegates: []string{"hop@p.iam.gserviceaccount.com"}}
+	if err := SetADCAccount("User@Example.com", want); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	got, ok, err := ReadADCAccount("user@example.com")
+	if err != nil || !ok {
+		t.Fatalf("read: ok=%v err=%v", ok, err)
+	}
+
+	if got.Impersonate != want.Impersonate || len(got.Delegates) != 1 {
+		t.Fatalf("unexpected account: %#v", got)
+	}
+
+	if _, ok, _ := ReadADCAccount("other@example.com"); ok {
+		t.Fatalf("unexpected entry for other account")
+	}
+
+	deleted, err := DeleteADCAccount("USER@example.com")
+	if err != nil || !deleted {
+		t.Fatalf("delete: deleted=%v err=%v", deleted, err)
+	}
+
+	if deleted, _ := DeleteADCAccount("user@example.com"); deleted {
+		t.Fatalf("expected second delete to be a no-op")
+	}
+}
@@ -0,0 +1,58 @@
+package config
+
+import "strings"
+
+func NormalizeCommandAlias(name string) string {
+	return strings.TrimSpace(name)
+}
+
+// SetCommandAlias maps name to an argument template such as
+// "gmail search 'in:inbox is:unread' --max 50".
+func SetCommandAlias(name, template string) error {
+	name = NormalizeCommandAlias(name)
+	template = strings.TrimSpace(template)
+
+	cfg, err := ReadConfig()
+	if err != nil {
+		return err
+	}
+
+	if cfg.Aliases == nil {
+		cfg.Aliases = map[string]string{}
+	}
+
+	cfg.Aliases[name] = template
+
+	return WriteConfig(cfg)
+}
+
+func DeleteCommandAlias(name string) (bool, error) {
+	name = NormalizeCommandAlias(name)
+
+	cfg, err := ReadConfig()
+	if err != nil {
+		return false, err
+	}
+
+	if _, ok := cfg.Aliases[name]; !ok {
+		return false, nil
+	}
+
+	delete(cfg.Aliases, name)
+
+	return true, WriteConfig(cfg)
+}
+
+func ListCommandAliases() (map[string]string, error) {
+	cfg, err := ReadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]string, len(cfg.Aliases))
+	for k, v := range cfg.Aliases {
+		out[k] = v
+	}
+
+	return out, nil
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestCommandAliasesCRUD(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg-config"))
+
+	if err := SetCommandAlias(" inbox ", " gmail search 'in:inbox is:unread' --max 50 "); err != nil {
+		t.Fatalf("set alias: %v", err)
+	}
+
+	aliases, err := ListCommandAliases()
+	if err != nil {
+		t.Fatalf("list aliases: %v", err)
+	}
+
+	if aliases["inbox"] != "gmail search 'in:inbox is:unread' --max 50" {
+		t.Fatalf("unexpected alias list: %#v", aliases)
+	}
+
+	deleted, err := DeleteCommandAlias("inbox")
+	if err != nil {
+		t.Fatalf("delete alias: %v", err)
+	}
+
+	if !deleted {
+		t.Fatalf("expected alias delete")
+	}
+
+	deleted, err = DeleteCommandAlias("inbox")
+	if err != nil || deleted {
+		t.Fatalf("expected missing alias, got deleted=%v err=%v", deleted, err)
+	}
+}
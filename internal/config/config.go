@@ -10,11 +10,19 @@ import (
 )
 
 type File struct {
-	KeyringBackend  string            `json:"keyring_backend,omitempty"`
-	DefaultTimezone string            `json:"default_timezone,omitempty"`
-	AccountAliases  map[string]string `json:"account_aliases,omitempty"`
-	AccountClients  map[string]string `json:"account_clients,omitempty"`
-	ClientDomains   map[string]string `json:"client_domains,omitempty"`
+	KeyringBackend  string                `json:"keyring_backend,omitempty"`
+	DefaultTimezone string                `json:"default_timezone,omitempty"`
+	AccountAliases  map[string]string     `json:"account_aliases,omitempty"`
+	AccountClients  map[string]string     `json:"account_clients,omitempty"`
+	ClientDomains   map[string]string     `json:"client_domains,omitempty"`
+	CacheTTL        string                `json:"cache_ttl,omitempty"`
+	AuditLog        bool                  `json:"audit_log,omitempty"`
+	AuditLogPath    string                `json:"audit_log_path,omitempty"`
+	RateLimits      map[string]string     `json:"rate_limits,omitempty"`
+	Aliases         map[string]string     `json:"aliases,omitempty"`
+	ADCAccounts     map[string]ADCAccount `json:"adc_accounts,omitempty"`
+	Profiles        map[string]Profile    `json:"profiles,omitempty"`
+	Policy          *Policy               `json:"policy,omitempty"`
 }
 
 func ConfigPath() (string, error) {
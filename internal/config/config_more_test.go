@@ -60,3 +60,19 @@ func TestKeepServiceAccountLegacyPath(t *testing.T) {
 		t.Fatalf("unexpected path: %q", path)
 	}
 }
+
+func TestCacheTTLKey(t *testing.T) {
+	var cfg File
+	if err := SetValue(&cfg, KeyCacheTTL, "10m"); err != nil {
+		t.Fatalf("SetValue: %v", err)
+	}
+	if got := GetValue(cfg, KeyCacheTTL); got != "10m" {
+		t.Fatalf("expected 10m, got %q", got)
+	}
+	if err := SetValue(&cfg, KeyCacheTTL, "soon"); err == nil {
+		t.Fatalf("expected invalid duration error")
+	}
+	if err := UnsetValue(&cfg, KeyCacheTTL); err != nil || cfg.CacheTTL != "" {
+		t.Fatalf("UnsetValue: %v %q", err, cfg.CacheTTL)
+	}
+}
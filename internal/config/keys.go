@@ -3,6 +3,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -12,6 +13,10 @@ type Key string
 const (
 	KeyTimezone       Key = "timezone"
 	KeyKeyringBackend Key = "keyring_backend"
+	KeyCacheTTL       Key = "cache_ttl"
+	KeyAuditLog       Key = "audit_log"
+	KeyAuditLogPath   Key = "audit_log_path"
+	KeyRateLimits     Key = "rate_limits"
 )
 
 type KeySpec struct {
@@ -25,6 +30,10 @@ type KeySpec struct {
 var keyOrder = []Key{
 	KeyTimezone,
 	KeyKeyringBackend,
+	KeyCacheTTL,
+	KeyAuditLog,
+	KeyAuditLogPath,
+	KeyRateLimits,
 }
 
 var keySpecs = map[Key]KeySpec{
@@ -63,6 +72,90 @@ var keySpecs = map[Key]KeySpec{
 			return "(not set, using auto)"
 		},
 	},
+	KeyCacheTTL: {
+		Key: KeyCacheTTL,
+		Get: func(cfg File) string {
+			return cfg.CacheTTL
+		},
+		Set: func(cfg *File, value string) error {
+			d, err := time.ParseDuration(value)
+			if err != nil || d <= 0 {
+				return fmt.Errorf("invalid cache_ttl %q (use a positive duration like 5m, 1h)", value)
+			}
+			cfg.CacheTTL = value
+			return nil
+		},
+		Unset: func(cfg *File) {
+			cfg.CacheTTL = ""
+		},
+		EmptyHint: func() string {
+			return "(not set, using 5m)"
+		},
+	},
+	KeyAuditLog: {
+		Key: KeyAuditLog,
+		Get: func(cfg File) string {
+			if cfg.AuditLog {
+				return "true"
+			}
+			return ""
+		},
+		Set: func(cfg *File, value string) error {
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("invalid audit_log %q (use true or false)", value)
+			}
+			cfg.AuditLog = enabled
+			return nil
+		},
+		Unset: func(cfg *File) {
+			cfg.AuditLog = false
+		},
+		EmptyHint: func() string {
+			return "(not set, audit log disabled)"
+		},
+	},
+	KeyAuditLogPath: {
+		Key: KeyAuditLogPath,
+		Get: func(cfg File) string {
+			return cfg.AuditLogPath
+		},
+		Set: func(cfg *File, value string) error {
+			cfg.AuditLogPath = value
+			return nil
+		},
+		Unset: func(cfg *File) {
+			cfg.AuditLogPath = ""
+		},
+		EmptyHint: func() string {
+			return "(not set, using audit.jsonl in the config dir)"
+		},
+	},
+	KeyRateLimits: {
+		Key: KeyRateLimits,
+		Get: func(cfg File) string {
+			return formatRateLimits(cfg.RateLimits)
+		},
+		Set: func(cfg *File, value string) error {
+			limits, err := ParseRateLimits(value)
+			if err != nil {
+				return err
+			}
+			if cfg.RateLimits == nil {
+				cfg.RateLimits = map[string]string{}
+			}
+			for service, limit := range limits {
+				cfg.RateLimits[service] = limit
+			}
+			return nil
+		},
+		Unset: func(cfg *File) {
+			cfg.RateLimits = nil
+		},
+		EmptyHint: func() string {
+			return "(not set, using published per-user quotas)"
+		},
+	},
 }
 
 var (
@@ -135,6 +135,24 @@ func GmailWatchDir() (string, error) {
 	return filepath.Join(dir, "state", "gmail-watch"), nil
 }
 
+func HTTPCacheDir() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "cache", "http"), nil
+}
+
+func TokenCacheDir() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "cache", "tokens"), nil
+}
+
 func KeepServiceAccountPath(email string) (string, error) {
 	dir, err := Dir()
 	if err != nil {
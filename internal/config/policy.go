@@ -0,0 +1,39 @@
+package config
+
+import "strings"
+
+// Policy restricts what gog may do regardless of flags and environment. It
+// is edited by hand in config.json so that agents cannot loosen it through
+// gog itself.
+type Policy struct {
+	ReadOnly bool            `json:"read_only,omitempty"`
+	Allow    []string        `json:"allow,omitempty"`
+	Deny     []string        `json:"deny,omitempty"`
+	Outbound *OutboundPolicy `json:"outbound,omitempty"`
+}
+
+// OutboundPolicy guards commands that send mail or chat messages, invite
+// attendees or share files. Domains match themselves and their subdomains;
+// InternalDomains defaults to the domain of the acting account.
+type OutboundPolicy struct {
+	AllowedDomains   []string `json:"allowed_domains,omitempty"`
+	BlockedDomains   []string `json:"blocked_domains,omitempty"`
+	MaxRecipients    int      `json:"max_recipients,omitempty"`
+	BlockPublicLinks bool     `json:"block_public_links,omitempty"`
+	InternalDomains  []string `json:"internal_domains,omitempty"`
+	ConfirmExternal  bool     `json:"confirm_external,omitempty"`
+	AllowForce       bool     `json:"allow_force,omitempty"`
+}
+
+// CommandRules returns Allow and Deny in --enable-commands syntax, with
+// deny entries prefixed by "!".
+func (p Policy) CommandRules() string {
+	rules := make([]string, 0, len(p.Allow)+len(p.Deny))
+	rules = append(rules, p.Allow...)
+	for _, d := range p.Deny {
+		if d = strings.TrimSpace(d); d != "" {
+			rules = append(rules, "!"+strings.TrimPrefix(d, "!"))
+		}
+	}
+	return strings.Join(rules, ",")
+}
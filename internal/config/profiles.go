@@ -0,0 +1,222 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Profile bundles the defaults of one persona (personal, work domain, CI
+// bot). Flags maps a command path such as "gmail search" to flag defaults
+// keyed by long flag name.
+type Profile struct {
+	Account        string                       `json:"account,omitempty"`
+	Client         string                       `json:"client,omitempty"`
+	Timezone       string                       `json:"timezone,omitempty"`
+	Output         string                       `json:"output,omitempty"`
+	EnableCommands string                       `json:"enable_commands,omitempty"`
+	Flags          map[string]map[string]string `json:"flags,omitempty"`
+}
+
+const profileFlagsPrefix = "flags."
+
+// ProfileKeys are the scalar profile keys; per-command flag defaults use
+// flags.<command>.<flag>, e.g. flags.gmail.search.max.
+var ProfileKeys = []string{"account", "client", "timezone", "output", "enable_commands"}
+
+var (
+	errUnknownProfileKey = errors.New("unknown profile key")
+	errProfileNotFound   = errors.New("profile not found")
+)
+
+func NormalizeProfileName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// ParseProfileFlagKey splits flags.<command>.<flag> into the command path
+// ("gmail search") and the flag name ("max").
+func ParseProfileFlagKey(key string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(key, profileFlagsPrefix)
+	if !ok {
+		return "", "", false
+	}
+	i := strings.LastIndex(rest, ".")
+	if i <= 0 || i == len(rest)-1 {
+		return "", "", false
+	}
+	return strings.ReplaceAll(rest[:i], ".", " "), rest[i+1:], true
+}
+
+// ProfileFlagKey is the inverse of ParseProfileFlagKey.
+func ProfileFlagKey(command, flag string) string {
+	return profileFlagsPrefix + strings.ReplaceAll(command, " ", ".") + "." + flag
+}
+
+// Values flattens p into key/value pairs in display order.
+func (p Profile) Values() [][2]string {
+	out := [][2]string{}
+	for _, key := range ProfileKeys {
+		if v := p.get(key); v != "" {
+			out = append(out, [2]string{key, v})
+		}
+	}
+
+	var flagKeys []string
+	for command, flags := range p.Flags {
+		for flag := range flags {
+			flagKeys = append(flagKeys, ProfileFlagKey(command, flag))
+		}
+	}
+	sort.Strings(flagKeys)
+	for _, key := range flagKeys {
+		command, flag, _ := ParseProfileFlagKey(key)
+		out = append(out, [2]string{key, p.Flags[command][flag]})
+	}
+	return out
+}
+
+func (p Profile) get(key string) string {
+	switch key {
+	case "account":
+		return p.Account
+	case "client":
+		return p.Client
+	case "timezone":
+		return p.Timezone
+	case "output":
+		return p.Output
+	case "enable_commands":
+		return p.EnableCommands
+	}
+	return ""
+}
+
+func (p *Profile) set(key, value string) error {
+	switch key {
+	case "account":
+		p.Account = strings.ToLower(value)
+	case "client":
+		p.Client = value
+	case "timezone":
+		if value != "" {
+			if _, err := time.LoadLocation(value); err != nil {
+				return fmt.Errorf("invalid timezone %q: %w (use IANA timezone names like America/New_York, UTC, Europe/London)", value, err)
+			}
+		}
+		p.Timezone = value
+	case "output":
+		p.Output = strings.ToLower(value)
+	case "enable_commands":
+		p.EnableCommands = value
+	default:
+		command, flag, ok := ParseProfileFlagKey(key)
+		if !ok {
+			return fmt.Errorf("%w %q (expected %s or flags.<command>.<flag>)", errUnknownProfileKey, key, strings.Join(ProfileKeys, ", "))
+		}
+		if value == "" {
+			delete(p.Flags[command], flag)
+			if len(p.Flags[command]) == 0 {
+				delete(p.Flags, command)
+			}
+			return nil
+		}
+		if p.Flags == nil {
+			p.Flags = map[string]map[string]string{}
+		}
+		if p.Flags[command] == nil {
+			p.Flags[command] = map[string]string{}
+		}
+		p.Flags[command][flag] = value
+	}
+	return nil
+}
+
+func GetProfile(name string) (Profile, bool, error) {
+	cfg, err := ReadConfig()
+	if err != nil {
+		return Profile{}, false, err
+	}
+
+	p, ok := cfg.Profiles[NormalizeProfileName(name)]
+
+	return p, ok, nil
+}
+
+func ListProfiles() (map[string]Profile, error) {
+	cfg, err := ReadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]Profile, len(cfg.Profiles))
+	for k, v := range cfg.Profiles {
+		out[k] = v
+	}
+
+	return out, nil
+}
+
+// SetProfileValue sets one key of a profile, creating the profile if needed.
+func SetProfileValue(name, key, value string) error {
+	name = NormalizeProfileName(name)
+
+	cfg, err := ReadConfig()
+	if err != nil {
+		return err
+	}
+
+	p := cfg.Profiles[name]
+	if err := p.set(strings.TrimSpace(key), strings.TrimSpace(value)); err != nil {
+		return err
+	}
+
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]Profile{}
+	}
+
+	cfg.Profiles[name] = p
+
+	return WriteConfig(cfg)
+}
+
+// UnsetProfileValue clears one key of a profile.
+func UnsetProfileValue(name, key string) error {
+	name = NormalizeProfileName(name)
+
+	cfg, err := ReadConfig()
+	if err != nil {
+		return err
+	}
+
+	p, ok := cfg.Profiles[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", errProfileNotFound, name)
+	}
+
+	if err := p.set(strings.TrimSpace(key), ""); err != nil {
+		return err
+	}
+
+	cfg.Profiles[name] = p
+
+	return WriteConfig(cfg)
+}
+
+func DeleteProfile(name string) (bool, error) {
+	name = NormalizeProfileName(name)
+
+	cfg, err := ReadConfig()
+	if err != nil {
+		return false, err
+	}
+
+	if _, ok := cfg.Profiles[name]; !ok {
+		return false, nil
+	}
+
+	delete(cfg.Profiles, name)
+
+	return true, WriteConfig(cfg)
+}
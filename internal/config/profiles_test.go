@@ -0,0 +1,61 @@
+package config
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestProfilesCRUD(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg-config"))
+
+	for _, kv := range [][2]string{
+		{"account", "Me@Work.com"},
+		{"timezone", "Europe/Vienna"},
+		{"output", "JSON"},
+		{"flags.gmail.search.max", "50"},
+	} {
+		if err := SetProfileValue("Work", kv[0], kv[1]); err != nil {
+			t.Fatalf("set %s: %v", kv[0], err)
+		}
+	}
+
+	p, ok, err := GetProfile("work")
+	if err != nil || !ok {
+		t.Fatalf("get profile: ok=%v err=%v", ok, err)
+	}
+	want := [][2]string{
+		{"account", "me@work.com"},
+		{"timezone", "Europe/Vienna"},
+		{"output", "json"},
+		{"flags.gmail.search.max", "50"},
+	}
+	if got := p.Values(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected values: %v", got)
+	}
+
+	if err := SetProfileValue("work", "timezone", "Mars/Olympus"); err == nil {
+		t.Fatalf("expected invalid timezone error")
+	}
+	if err := SetProfileValue("work", "flags.max", "5"); err == nil {
+		t.Fatalf("expected invalid key error")
+	}
+
+	if err := UnsetProfileValue("work", "flags.gmail.search.max"); err != nil {
+		t.Fatalf("unset: %v", err)
+	}
+	p, _, _ = GetProfile("work")
+	if p.Flags != nil && len(p.Flags) != 0 {
+		t.Fatalf("expected empty flags, got %#v", p.Flags)
+	}
+
+	deleted, err := DeleteProfile("work")
+	if err != nil || !deleted {
+		t.Fatalf("delete: deleted=%v err=%v", deleted, err)
+	}
+	if err := UnsetProfileValue("work", "account"); err == nil {
+		t.Fatalf("expected missing profile error")
+	}
+}
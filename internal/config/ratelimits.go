@@ -0,0 +1,93 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimitOff disables client-side throttling for a service.
+const RateLimitOff = "off"
+
+var errInvalidRateLimit = errors.New("invalid rate limit")
+
+// ParseRateLimit parses "<units>/<window>" such as "250/s", "600/m" or
+// "1000/100s". It returns zero values for "off".
+func ParseRateLimit(raw string) (float64, time.Duration, error) {
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	if raw == RateLimitOff {
+		return 0, 0, nil
+	}
+
+	units, window, ok := strings.Cut(raw, "/")
+	if !ok {
+		return 0, 0, fmt.Errorf("%w %q (use N/s, N/m, N/h or N/<duration>)", errInvalidRateLimit, raw)
+	}
+
+	n, err := strconv.ParseFloat(strings.TrimSpace(units), 64)
+	if err != nil || n <= 0 {
+		return 0, 0, fmt.Errorf("%w %q: units must be a positive number", errInvalidRateLimit, raw)
+	}
+
+	var per time.Duration
+	switch window = strings.TrimSpace(window); window {
+	case "s":
+		per = time.Second
+	case "m":
+		per = time.Minute
+	case "h":
+		per = time.Hour
+	default:
+		per, err = time.ParseDuration(window)
+		if err != nil || per <= 0 {
+			return 0, 0, fmt.Errorf("%w %q: window must be s, m, h or a positive duration", errInvalidRateLimit, raw)
+		}
+	}
+
+	return n, per, nil
+}
+
+// ParseRateLimits parses "service=limit" pairs separated by commas.
+func ParseRateLimits(raw string) (map[string]string, error) {
+	out := map[string]string{}
+	for _, pair := range strings.Split(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		service, limit, ok := strings.Cut(pair, "=")
+		service = strings.ToLower(strings.TrimSpace(service))
+		limit = strings.ToLower(strings.TrimSpace(limit))
+		if !ok || service == "" {
+			return nil, fmt.Errorf("%w %q (use service=N/window, e.g. gmail=100/s)", errInvalidRateLimit, pair)
+		}
+		if _, _, err := ParseRateLimit(limit); err != nil {
+			return nil, err
+		}
+		out[service] = limit
+	}
+
+	if len(out) == 0 {
+		return nil, fmt.Errorf("%w: empty value", errInvalidRateLimit)
+	}
+
+	return out, nil
+}
+
+func formatRateLimits(limits map[string]string) string {
+	services := make([]string, 0, len(limits))
+	for service := range limits {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+
+	pairs := make([]string, 0, len(services))
+	for _, service := range services {
+		pairs = append(pairs, service+"="+limits[service])
+	}
+
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	cases := []struct {
+		in    string
+		units float64
+		per   time.Duration
+	}{
+		{"250/s", 250, time.Second},
+		{"600/m", 600, time.Minute},
+		{"1000/100s", 1000, 100 * time.Second},
+		{" 5 / h ", 5, time.Hour},
+		{"off", 0, 0},
+	}
+	for _, tc := range cases {
+		units, per, err := ParseRateLimit(tc.in)
+		if err != nil {
+			t.Fatalf("%q: %v", tc.in, err)
+		}
+		if units != tc.units || per != tc.per {
+			t.Fatalf("%q: got %v/%v", tc.in, units, per)
+		}
+	}
+
+	for _, bad := range []string{"", "10", "0/s", "-1/s", "x/s", "10/fortnight", "10/-1s"} {
+		if _, _, err := ParseRateLimit(bad); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestRateLimitsKey(t *testing.T) {
+	var cfg File
+	if err := SetValue(&cfg, KeyRateLimits, "Gmail=100/s, drive=off"); err != nil {
+		t.Fatalf("SetValue: %v", err)
+	}
+	if err := SetValue(&cfg, KeyRateLimits, "calendar=300/m"); err != nil {
+		t.Fatalf("SetValue merge: %v", err)
+	}
+	if got := GetValue(cfg, KeyRateLimits); got != "calendar=300/m,drive=off,gmail=100/s" {
+		t.Fatalf("unexpected value %q", got)
+	}
+	if err := SetValue(&cfg, KeyRateLimits, "gmail"); err == nil {
+		t.Fatalf("expected missing limit error")
+	}
+	if err := SetValue(&cfg, KeyRateLimits, "gmail=fast"); err == nil {
+		t.Fatalf("expected invalid limit error")
+	}
+	if err := UnsetValue(&cfg, KeyRateLimits); err != nil || cfg.RateLimits != nil {
+		t.Fatalf("UnsetValue: %v %#v", err, cfg.RateLimits)
+	}
+}
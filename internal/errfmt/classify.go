@@ -0,0 +1,228 @@
+package errfmt
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/99designs/keyring"
+	"github.com/alecthomas/kong"
+	"golang.org/x/oauth2"
+	ggoogleapi "google.golang.org/api/googleapi"
+
+	"github.com/steipete/gogcli/internal/config"
+	gogapi "github.com/steipete/gogcli/internal/googleapi"
+)
+
+// Exit codes are part of the CLI contract; scripts may rely on them.
+const (
+	ExitOK                 = 0
+	ExitError              = 1
+	ExitUsage              = 2
+	ExitAuthRequired       = 3
+	ExitCredentialsMissing = 4
+	ExitPermissionDenied   = 5
+	ExitNotFound           = 6
+	ExitRateLimited        = 7
+	ExitCircuitOpen        = 8
+	ExitConflict           = 9
+	ExitNetwork            = 10
+)
+
+// Error codes used in JSON error envelopes.
+const (
+	CodeError              = "error"
+	CodeUsage              = "usage"
+	CodeAuthRequired       = "auth_required"
+	CodeInsufficientScopes = "insufficient_scopes"
+	CodeCredentialsMissing = "credentials_missing"
+	CodePermissionDenied   = "permission_denied"
+	CodeNotFound           = "not_found"
+	CodeRateLimited        = "rate_limited"
+	CodeCircuitOpen        = "circuit_open"
+	CodeConflict           = "conflict"
+	CodeReadOnly           = "read_only"
+	CodeNetwork            = "network"
+	CodeServerError        = "server_error"
+)
+
+// Info is the machine-readable classification of an error; with --json it
+// is written to stderr as {"error": Info}.
+type Info struct {
+	Code              string `json:"code"`
+	Reason            string `json:"reason,omitempty"`
+	Status            int    `json:"status,omitempty"`
+	Message           string `json:"message"`
+	Hint              string `json:"hint,omitempty"`
+	ExitCode          int    `json:"exitCode"`
+	Service           string `json:"service,omitempty"`
+	RetryAfterSeconds int    `json:"retryAfterSeconds,omitempty"`
+}
+
+// Classify maps err onto the error taxonomy. Message is the same text
+// Format returns.
+func Classify(err error) Info {
+	info := classify(err)
+	info.Message = Format(err)
+
+	return info
+}
+
+// ExitCode returns the process exit code for err.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	return classify(err).ExitCode
+}
+
+func classify(err error) Info {
+	var parseErr *kong.ParseError
+	if errors.As(err, &parseErr) {
+		return Info{Code: CodeUsage, ExitCode: ExitUsage, Hint: "Run with --help to see usage"}
+	}
+
+	var authErr *gogapi.AuthRequiredError
+	if errors.As(err, &authErr) {
+		return Info{
+			Code:     CodeAuthRequired,
+			ExitCode: ExitAuthRequired,
+			Service:  authErr.Service,
+			Hint:     "gog auth add " + authErr.Email + " --services " + authErr.Service,
+		}
+	}
+
+	var scopeErr *gogapi.InsufficientScopesError
+	if errors.As(err, &scopeErr) {
+		return Info{
+			Code:     CodeInsufficientScopes,
+			ExitCode: ExitPermissionDenied,
+			Status:   http.StatusForbidden,
+			Service:  strings.Join(scopeErr.Services, ","),
+			Hint:     scopeErr.Command(),
+		}
+	}
+
+	var credErr *config.CredentialsMissingError
+	if errors.As(err, &credErr) {
+		return Info{Code: CodeCredentialsMissing, ExitCode: ExitCredentialsMissing, Hint: "gog auth credentials <credentials.json>"}
+	}
+
+	if errors.Is(err, keyring.ErrKeyNotFound) {
+		return Info{Code: CodeAuthRequired, ExitCode: ExitAuthRequired, Hint: "gog auth add <email>"}
+	}
+
+	var retrieveErr *oauth2.RetrieveError
+	if errors.As(err, &retrieveErr) {
+		info := Info{Code: CodeAuthRequired, ExitCode: ExitAuthRequired, Reason: retrieveErr.ErrorCode, Hint: "gog auth add <email> --force-consent"}
+		if retrieveErr.Response != nil {
+			info.Status = retrieveErr.Response.StatusCode
+		}
+		return info
+	}
+
+	var cbErr *gogapi.CircuitBreakerError
+	if errors.As(err, &cbErr) {
+		return Info{
+			Code:              CodeCircuitOpen,
+			ExitCode:          ExitCircuitOpen,
+			Service:           cbErr.Service,
+			RetryAfterSeconds: int(math.Ceil(cbErr.RetryAfter.Seconds())),
+			Hint:              "the API failed repeatedly; wait and retry",
+		}
+	}
+
+	var readOnlyErr *gogapi.ReadOnlyError
+	if errors.As(err, &readOnlyErr) {
+		return Info{Code: CodeReadOnly, ExitCode: ExitPermissionDenied, Hint: "read-only mode is on (--read-only, GOG_READ_ONLY or policy.read_only in config.json)"}
+	}
+
+	var rateErr *gogapi.RateLimitError
+	var quotaErr *gogapi.QuotaExceededError
+	if errors.As(err, &rateErr) || errors.As(err, &quotaErr) {
+		return Info{Code: CodeRateLimited, ExitCode: ExitRateLimited, Status: http.StatusTooManyRequests}
+	}
+
+	var notFoundErr *gogapi.NotFoundError
+	if errors.As(err, &notFoundErr) {
+		return Info{Code: CodeNotFound, ExitCode: ExitNotFound, Status: http.StatusNotFound}
+	}
+
+	var deniedErr *gogapi.PermissionDeniedError
+	if errors.As(err, &deniedErr) {
+		return Info{Code: CodePermissionDenied, ExitCode: ExitPermissionDenied, Status: http.StatusForbidden}
+	}
+
+	var gerr *ggoogleapi.Error
+	if errors.As(err, &gerr) {
+		return classifyAPIError(gerr)
+	}
+
+	if isNetworkError(err) {
+		return Info{Code: CodeNetwork, ExitCode: ExitNetwork, Hint: "check your network connection and retry"}
+	}
+
+	return Info{Code: CodeError, ExitCode: ExitError}
+}
+
+func classifyAPIError(gerr *ggoogleapi.Error) Info {
+	info := Info{Status: gerr.Code}
+	if len(gerr.Errors) > 0 {
+		info.Reason = gerr.Errors[0].Reason
+	}
+
+	switch {
+	case gerr.Code == http.StatusUnauthorized:
+		info.Code, info.ExitCode = CodeAuthRequired, ExitAuthRequired
+		info.Hint = "gog auth add <email> --force-consent"
+	case gerr.Code == http.StatusTooManyRequests || isRateLimitReason(info.Reason):
+		info.Code, info.ExitCode = CodeRateLimited, ExitRateLimited
+		info.Hint = "quota exhausted; wait and retry"
+	case gerr.Code == http.StatusForbidden:
+		info.Code, info.ExitCode = CodePermissionDenied, ExitPermissionDenied
+	case gerr.Code == http.StatusNotFound:
+		info.Code, info.ExitCode = CodeNotFound, ExitNotFound
+	case gerr.Code == http.StatusConflict || gerr.Code == http.StatusPreconditionFailed:
+		info.Code, info.ExitCode = CodeConflict, ExitConflict
+		info.Hint = "the resource changed or already exists; re-read it and retry"
+	case gerr.Code >= 500:
+		info.Code, info.ExitCode = CodeServerError, ExitError
+	default:
+		info.Code, info.ExitCode = CodeError, ExitError
+	}
+
+	return info
+}
+
+func isRateLimitReason(reason string) bool {
+	switch reason {
+	case "rateLimitExceeded", "userRateLimitExceeded", "quotaExceeded", "dailyLimitExceeded":
+		return true
+	}
+
+	return false
+}
+
+func isNetworkError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	// *url.Error implements net.Error itself; look at what it wraps so TLS
+	// and request-building failures are not reported as network errors.
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		if urlErr.Timeout() {
+			return true
+		}
+		err = urlErr.Err
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
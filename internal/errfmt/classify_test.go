@@ -0,0 +1,101 @@
+package errfmt
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/99designs/keyring"
+	"golang.org/x/oauth2"
+	ggoogleapi "google.golang.org/api/googleapi"
+
+	"github.com/steipete/gogcli/internal/config"
+	gogapi "github.com/steipete/gogcli/internal/googleapi"
+)
+
+func TestClassify(t *testing.T) {
+	apiErr := func(code int, reason string) error {
+		e := &ggoogleapi.Error{Code: code, Message: "boom"}
+		if reason != "" {
+			e.Errors = []ggoogleapi.ErrorItem{{Reason: reason, Message: "boom"}}
+		}
+		return fmt.Errorf("wrapped: %w", e)
+	}
+
+	cases := []struct {
+		name string
+		err  error
+		code string
+		exit int
+	}{
+		{"generic", errNope, CodeError, ExitError},
+		{"auth required", &gogapi.AuthRequiredError{Service: "gmail", Email: "a@b.com"}, CodeAuthRequired, ExitAuthRequired},
+		{"keyring missing", keyring.ErrKeyNotFound, CodeAuthRequired, ExitAuthRequired},
+		{"token revoked", &oauth2.RetrieveError{ErrorCode: "invalid_grant"}, CodeAuthRequired, ExitAuthRequired},
+		{"credentials missing", &config.CredentialsMissingError{Path: "/x"}, CodeCredentialsMissing, ExitCredentialsMissing},
+		{"401", apiErr(http.StatusUnauthorized, ""), CodeAuthRequired, ExitAuthRequired},
+		{"403", apiErr(http.StatusForbidden, "insufficientPermissions"), CodePermissionDenied, ExitPermissionDenied},
+		{"403 rate", apiErr(http.StatusForbidden, "userRateLimitExceeded"), CodeRateLimited, ExitRateLimited},
+		{"403 scopes", &gogapi.InsufficientScopesError{Email: "a@b.com", Services: []string{"drive"}, Cause: apiErr(http.StatusForbidden, "insufficientPermissions")}, CodeInsufficientScopes, ExitPermissionDenied},
+		{"404", apiErr(http.StatusNotFound, "notFound"), CodeNotFound, ExitNotFound},
+		{"409", apiErr(http.StatusConflict, ""), CodeConflict, ExitConflict},
+		{"412", apiErr(http.StatusPreconditionFailed, "conditionNotMet"), CodeConflict, ExitConflict},
+		{"429", apiErr(http.StatusTooManyRequests, ""), CodeRateLimited, ExitRateLimited},
+		{"500", apiErr(http.StatusInternalServerError, ""), CodeServerError, ExitError},
+		{"read-only", &url.Error{Op: "Post", URL: "https://x", Err: &gogapi.ReadOnlyError{Method: "POST", URL: "https://x"}}, CodeReadOnly, ExitPermissionDenied},
+		{"circuit", &url.Error{Op: "Get", URL: "https://x", Err: &gogapi.CircuitBreakerError{Service: "drive", RetryAfter: 3 * time.Second}}, CodeCircuitOpen, ExitCircuitOpen},
+		{"local file", fmt.Errorf("open: %w", os.ErrNotExist), CodeError, ExitError},
+		{"network", &url.Error{Op: "Get", URL: "https://x", Err: &net.OpError{Op: "dial", Net: "tcp", Err: errNope}}, CodeNetwork, ExitNetwork},
+		{"tls", &url.Error{Op: "Get", URL: "https://x", Err: x509.UnknownAuthorityError{}}, CodeError, ExitError},
+		{"bad scheme", &url.Error{Op: "Get", URL: "ftp://x", Err: errNope}, CodeError, ExitError},
+		{"timeout", fmt.Errorf("x: %w", context.DeadlineExceeded), CodeNetwork, ExitNetwork},
+	}
+	for _, tc := range cases {
+		info := Classify(tc.err)
+		if info.Code != tc.code || info.ExitCode != tc.exit {
+			t.Fatalf("%s: got %s/%d, want %s/%d", tc.name, info.Code, info.ExitCode, tc.code, tc.exit)
+		}
+		if got := ExitCode(tc.err); got != tc.exit {
+			t.Fatalf("%s: ExitCode %d", tc.name, got)
+		}
+	}
+
+	if ExitCode(nil) != ExitOK {
+		t.Fatalf("expected 0 for nil")
+	}
+}
+
+func TestClassify_JSONEnvelope(t *testing.T) {
+	err := &ggoogleapi.Error{Code: 404, Message: "File not found: x", Errors: []ggoogleapi.ErrorItem{{Reason: "notFound", Message: "File not found: x"}}}
+	data, merr := json.Marshal(map[string]any{"error": Classify(err)})
+	if merr != nil {
+		t.Fatalf("marshal: %v", merr)
+	}
+
+	var parsed struct {
+		Error struct {
+			Code     string `json:"code"`
+			Reason   string `json:"reason"`
+			Status   int    `json:"status"`
+			Message  string `json:"message"`
+			ExitCode int    `json:"exitCode"`
+		} `json:"error"`
+	}
+	if uerr := json.Unmarshal(data, &parsed); uerr != nil {
+		t.Fatalf("unmarshal: %v", uerr)
+	}
+	e := parsed.Error
+	if e.Code != CodeNotFound || e.Reason != "notFound" || e.Status != 404 || e.ExitCode != ExitNotFound {
+		t.Fatalf("unexpected envelope: %#v", e)
+	}
+	if e.Message != "Google API error (404 notFound): File not found: x" {
+		t.Fatalf("unexpected message: %q", e.Message)
+	}
+}
@@ -37,6 +37,20 @@ func Format(err error) string {
 		)
 	}
 
+	var scopeErr *gogapi.InsufficientScopesError
+	if errors.As(err, &scopeErr) {
+		msg := fmt.Sprintf(
+			"%s is not authorized for %s (missing OAuth scopes).\n\nGrant them (existing access is kept):\n  %s",
+			scopeErr.Email,
+			strings.Join(scopeErr.Services, ","),
+			scopeErr.Command(),
+		)
+		if scopeErr.Partial {
+			msg += "\n\nThe command made changes before it failed and was not retried; check them, then re-run only what is missing."
+		}
+		return msg
+	}
+
 	var credErr *config.CredentialsMissingError
 	if errors.As(err, &credErr) {
 		return fmt.Sprintf(
@@ -53,6 +67,12 @@ func Format(err error) string {
 		return err.Error()
 	}
 
+	// Drop the "Get <url>:" prefix the HTTP client adds.
+	var cbErr *gogapi.CircuitBreakerError
+	if errors.As(err, &cbErr) {
+		return cbErr.Error()
+	}
+
 	var userErr *UserFacingError
 	if errors.As(err, &userErr) {
 		return userErr.Message
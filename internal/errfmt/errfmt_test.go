@@ -34,6 +34,20 @@ func TestFormat_AuthRequired(t *testing.T) {
 	}
 }
 
+func TestFormat_InsufficientScopes(t *testing.T) {
+	err := &gogapi.InsufficientScopesError{Email: "a@b.com", Client: "work", Services: []string{"drive", "docs"}, Cause: errNope}
+	got := Format(err)
+
+	if !strings.Contains(got, "gog --client work auth add a@b.com --services drive,docs --incremental") || strings.Contains(got, "re-run") {
+		t.Fatalf("unexpected: %q", got)
+	}
+
+	err.Partial = true
+	if got := Format(err); !strings.Contains(got, "not retried") {
+		t.Fatalf("expected partial-change note: %q", got)
+	}
+}
+
 func TestFormat_CredentialsMissing(t *testing.T) {
 	err := &config.CredentialsMissingError{Path: "/tmp/creds.json", Cause: errNope}
 	got := Format(err)
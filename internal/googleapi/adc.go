@@ -0,0 +1,277 @@
+package googleapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+
+	"github.com/steipete/gogcli/internal/config"
+)
+
+const (
+	cloudPlatformScope        = "https://www.googleapis.com/auth/cloud-platform"
+	jwtBearerGrantType        = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+	impersonatedTokenLifetime = time.Hour
+)
+
+var (
+	iamCredentialsBaseURL = "https://iamcredentials.googleapis.com/v1/"
+	googleTokenURL        = google.Endpoint.TokenURL
+)
+
+// tokenSourceForADC returns a token source for accounts configured with
+// `gog auth adc set`. The second return value is false when email has no
+// ADC entry.
+func tokenSourceForADC(ctx context.Context, email string, scopes []string) (oauth2.TokenSource, bool, error) {
+	acct, ok, err := config.ReadADCAccount(email)
+	if err != nil {
+		return nil, false, fmt.Errorf("read adc config: %w", err)
+	}
+
+	if !ok {
+		return nil, false, nil
+	}
+
+	// Ensure token exchanges don't hang forever.
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Timeout: defaultHTTPTimeout})
+
+	target := strings.ToLower(strings.TrimSpace(acct.Impersonate))
+	if target == "" {
+		params := google.CredentialsParams{Scopes: scopes}
+		if !isServiceAccountEmail(email) {
+			// Only used when the ADC file is a service account key.
+			params.Subject = email
+		}
+
+		creds, err := findADC(ctx, acct.CredentialsFile, params)
+		if err != nil {
+			return nil, false, err
+		}
+
+		return creds.TokenSource, true, nil
+	}
+
+	base, err := findADC(ctx, acct.CredentialsFile, google.CredentialsParams{Scopes: []string{cloudPlatformScope}})
+	if err != nil {
+		return nil, false, err
+	}
+
+	its := &impersonatedTokenSource{
+		ctx:       ctx,
+		client:    oauth2.NewClient(ctx, base.TokenSource),
+		target:    target,
+		delegates: acct.Delegates,
+		scopes:    scopes,
+	}
+	if !strings.EqualFold(strings.TrimSpace(email), target) {
+		its.subject = strings.TrimSpace(email)
+	}
+
+	return oauth2.ReuseTokenSource(nil, its), true, nil
+}
+
+// findADC loads credentials from path, or from the standard ADC locations
+// when path is empty. Both accept authorized_user (gcloud),
+// service_account, external_account (workload identity federation) and
+// impersonated_service_account JSON.
+func findADC(ctx context.Context, path string, params google.CredentialsParams) (*google.Credentials, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		creds, err := google.FindDefaultCredentialsWithParams(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("find application default credentials: %w", err)
+		}
+
+		return creds, nil
+	}
+
+	path, err := config.ExpandPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // configured credentials path
+	if err != nil {
+		return nil, fmt.Errorf("read credentials file: %w", err)
+	}
+
+	creds, err := google.CredentialsFromJSONWithParams(ctx, data, params)
+	if err != nil {
+		return nil, fmt.Errorf("parse credentials file %s: %w", path, err)
+	}
+
+	return creds, nil
+}
+
+func isServiceAccountEmail(email string) bool {
+	return strings.HasSuffix(strings.ToLower(strings.TrimSpace(email)), ".gserviceaccount.com")
+}
+
+// impersonatedTokenSource mints tokens for a service account through the
+// IAM Credentials API, authenticated as the ADC principal (which needs
+// roles/iam.serviceAccountTokenCreator on target). With a subject it signs a
+// JWT-bearer assertion for that user instead (domain-wide delegation).
+type impersonatedTokenSource struct {
+	ctx       context.Context //nolint:containedctx // oauth2.TokenSource has no context parameter
+	client    *http.Client
+	target    string
+	subject   string
+	delegates []string
+	scopes    []string
+}
+
+func (s *impersonatedTokenSource) Token() (*oauth2.Token, error) {
+	if s.subject != "" {
+		return s.delegatedToken()
+	}
+
+	var resp struct {
+		AccessToken string `json:"accessToken"`
+		ExpireTime  string `json:"expireTime"`
+	}
+
+	err := s.iamCall("generateAccessToken", map[string]any{
+		"delegates": s.delegateNames(),
+		"scope":     s.scopes,
+		"lifetime":  fmt.Sprintf("%.fs", impersonatedTokenLifetime.Seconds()),
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	expiry, err := time.Parse(time.RFC3339, resp.ExpireTime)
+	if err != nil {
+		return nil, fmt.Errorf("generateAccessToken: invalid expireTime %q", resp.ExpireTime)
+	}
+
+	return &oauth2.Token{AccessToken: resp.AccessToken, TokenType: "Bearer", Expiry: expiry}, nil
+}
+
+// delegatedToken has the service account sign a JWT with sub=subject and
+// exchanges it at the OAuth token endpoint.
+func (s *impersonatedTokenSource) delegatedToken() (*oauth2.Token, error) {
+	now := time.Now()
+
+	claims, err := json.Marshal(map[string]any{
+		"iss":   s.target,
+		"sub":   s.subject,
+		"scope": strings.Join(s.scopes, " "),
+		"aud":   googleTokenURL,
+		"iat":   now.Unix(),
+		"exp":   now.Add(impersonatedTokenLifetime).Unix(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encode jwt claims: %w", err)
+	}
+
+	var signed struct {
+		SignedJWT string `json:"signedJwt"`
+	}
+
+	if err := s.iamCall("signJwt", map[string]any{
+		"delegates": s.delegateNames(),
+		"payload":   string(claims),
+	}, &signed); err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type": {jwtBearerGrantType},
+		"assertion":  {signed.SignedJWT},
+	}
+
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodPost, googleTokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("build token request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	// The assertion is the credential; the token endpoint is called
+	// without the ADC principal's bearer token.
+	if err := doJSON(tokenExchangeClient(s.ctx), req, "exchange delegated jwt", &tok); err != nil {
+		return nil, err
+	}
+
+	return &oauth2.Token{
+		AccessToken: tok.AccessToken,
+		TokenType:   tok.TokenType,
+		Expiry:      now.Add(time.Duration(tok.ExpiresIn) * time.Second),
+	}, nil
+}
+
+func (s *impersonatedTokenSource) iamCall(method string, body map[string]any, out any) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("encode %s request: %w", method, err)
+	}
+
+	endpoint := iamCredentialsBaseURL + "projects/-/serviceAccounts/" + url.PathEscape(s.target) + ":" + method
+
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodPost, endpoint, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("build %s request: %w", method, err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	return doJSON(s.client, req, method+" for "+s.target, out)
+}
+
+func (s *impersonatedTokenSource) delegateNames() []string {
+	out := make([]string, 0, len(s.delegates))
+	for _, d := range s.delegates {
+		if d = strings.TrimSpace(d); d != "" {
+			out = append(out, "projects/-/serviceAccounts/"+d)
+		}
+	}
+
+	return out
+}
+
+func tokenExchangeClient(ctx context.Context) *http.Client {
+	if c, ok := ctx.Value(oauth2.HTTPClient).(*http.Client); ok && c != nil {
+		return c
+	}
+
+	return &http.Client{Timeout: defaultHTTPTimeout}
+}
+
+func doJSON(client *http.Client, req *http.Request, what string, out any) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", what, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("%s: read response: %w", what, err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s: %s: %s", what, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("%s: decode response: %w", what, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,165 @@
+package googleapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steipete/gogcli/internal/config"
+)
+
+func newADCTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.URL.Path == "/token":
+			_ = r.ParseForm()
+			switch r.Form.Get("grant_type") {
+			case "refresh_token":
+				_, _ = w.Write([]byte(`{"access_token":"base-token","token_type":"Bearer","expires_in":3600}`))
+			case jwtBearerGrantType:
+				if r.Form.Get("assertion") != "signed.jwt" || r.Header.Get("Authorization") != "" {
+					http.Error(w, "bad assertion", http.StatusBadRequest)
+					return
+				}
+				_, _ = w.Write([]byte(`{"access_token":"user-token","token_type":"Bearer","expires_in":3600}`))
+			default:
+				http.Error(w, "bad grant", http.StatusBadRequest)
+			}
+		case strings.HasPrefix(r.URL.Path, "/v1/projects/-/serviceAccounts/bot@p.iam.gserviceaccount.com:"):
+			if r.Header.Get("Authorization") != "Bearer base-token" {
+				http.Error(w, "unauthenticated", http.StatusUnauthorized)
+				return
+			}
+
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+
+			if strings.HasSuffix(r.URL.Path, ":generateAccessToken") {
+				if body["lifetime"] != "3600s" || len(body["scope"].([]any)) != 1 {
+					http.Error(w, "bad request", http.StatusBadRequest)
+					return
+				}
+				_, _ = w.Write([]byte(`{"accessToken":"sa-token","expireTime":"2030-01-01T00:00:00Z"}`))
+				return
+			}
+
+			var claims map[string]any
+			_ = json.Unmarshal([]byte(body["payload"].(string)), &claims)
+			if claims["sub"] != "user@corp.com" || claims["iss"] != "bot@p.iam.gserviceaccount.com" || claims["scope"] != "s1" {
+				http.Error(w, "bad claims", http.StatusBadRequest)
+				return
+			}
+			delegates, _ := body["delegates"].([]any)
+			if len(delegates) != 1 || delegates[0] != "projects/-/serviceAccounts/hop@p.iam.gserviceaccount.com" {
+				http.Error(w, "bad delegates", http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(`{"keyId":"k","signedJwt":"signed.jwt"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	origIAM, origToken := iamCredentialsBaseURL, googleTokenURL
+	t.Cleanup(func() {
+		iamCredentialsBaseURL, googleTokenURL = origIAM, origToken
+	})
+	iamCredentialsBaseURL = srv.URL + "/v1/"
+	googleTokenURL = srv.URL + "/token"
+
+	return srv
+}
+
+func writeADCFile(t *testing.T, tokenURL string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "adc.json")
+	data := `{"type":"authorized_user","client_id":"id","client_secret":"secret","refresh_token":"rt","token_uri":"` + tokenURL + `"}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("write adc: %v", err)
+	}
+
+	return path
+}
+
+func TestTokenSourceForADC(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg-config"))
+
+	srv := newADCTestServer(t)
+	credsPath := writeADCFile(t, srv.URL+"/token")
+
+	cases := []struct {
+		name  string
+		email string
+		acct  config.ADCAccount
+		want  string
+	}{
+		{"direct", "user@corp.com", config.ADCAccount{CredentialsFile: credsPath}, "base-token"},
+		{"impersonate service account", "bot@p.iam.gserviceaccount.com", config.ADCAccount{CredentialsFile: credsPath, Impersonate: "bot@p.iam.gserviceaccount.com"}, "sa-token"},
+		{"domain-wide delegation", "user@corp.com", config.ADCAccount{CredentialsFile: credsPath, Impersonate: "bot@p.iam.gserviceaccount.com", Delegates: []string{"hop@p.iam.gserviceaccount.com"}}, "user-token"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := config.SetADCAccount(tc.email, tc.acct); err != nil {
+				t.Fatalf("SetADCAccount: %v", err)
+			}
+
+			ts, ok, err := tokenSourceForADC(context.Background(), tc.email, []string{"s1"})
+			if err != nil || !ok {
+				t.Fatalf("tokenSourceForADC: ok=%v err=%v", ok, err)
+			}
+
+			tok, err := ts.Token()
+			if err != nil {
+				t.Fatalf("Token: %v", err)
+			}
+
+			if tok.AccessToken != tc.want {
+				t.Fatalf("got token %q, want %q", tok.AccessToken, tc.want)
+			}
+		})
+	}
+
+	if _, ok, err := tokenSourceForADC(context.Background(), "other@corp.com", []string{"s1"}); ok || err != nil {
+		t.Fatalf("expected no ADC for unconfigured account, ok=%v err=%v", ok, err)
+	}
+}
+
+func TestAccountTokenSource_PrefersADCOverRefreshToken(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg-config"))
+
+	srv := newADCTestServer(t)
+	if err := config.SetADCAccount("user@corp.com", config.ADCAccount{CredentialsFile: writeADCFile(t, srv.URL+"/token")}); err != nil {
+		t.Fatalf("SetADCAccount: %v", err)
+	}
+
+	origRead := readClientCredentials
+	t.Cleanup(func() { readClientCredentials = origRead })
+	readClientCredentials = func(string) (config.ClientCredentials, error) {
+		t.Fatalf("readClientCredentials should not be called")
+		return config.ClientCredentials{}, nil
+	}
+
+	tok, err := AccessToken(context.Background(), "user@corp.com")
+	if err != nil {
+		t.Fatalf("AccessToken: %v", err)
+	}
+
+	if tok.AccessToken != "base-token" {
+		t.Fatalf("unexpected token: %q", tok.AccessToken)
+	}
+}
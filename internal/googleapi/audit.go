@@ -0,0 +1,148 @@
+package googleapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/steipete/gogcli/internal/audit"
+	"github.com/steipete/gogcli/internal/authclient"
+)
+
+const maxAuditRequestSummary = 512
+
+// AuditTransport appends one audit.Entry per mutating request. Request
+// bodies are summarized with message/file bodies and tokens redacted.
+type AuditTransport struct {
+	Base    http.RoundTripper
+	Session *audit.Session
+	Service string
+	Account string
+	Client  string
+}
+
+func wrapAuditTransport(ctx context.Context, serviceLabel string, email string, base http.RoundTripper) http.RoundTripper {
+	s := audit.SessionFromContext(ctx)
+	if s == nil || s.Log == nil {
+		return base
+	}
+	client, err := authclient.ResolveClient(ctx, email)
+	if err != nil {
+		client = authclient.ClientOverrideFromContext(ctx)
+	}
+
+	return &AuditTransport{Base: base, Session: s, Service: serviceLabel, Account: email, Client: client}
+}
+
+func (t *AuditTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if isReadRequest(req) {
+		return t.Base.RoundTrip(req)
+	}
+
+	entry := audit.Entry{
+		Run:      t.Session.Run,
+		Account:  t.Account,
+		Client:   t.Client,
+		Command:  t.Session.Command,
+		Service:  t.Service,
+		Method:   req.Method,
+		Resource: req.URL.Path,
+	}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		b, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(b))
+		req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(b)), nil }
+		entry.Request = auditSummary(b, req.Header.Get("Content-Type"))
+	}
+
+	resp, err := t.Base.RoundTrip(req)
+	switch {
+	case err != nil:
+		entry.Outcome = audit.OutcomeError
+		entry.Error = err.Error()
+	default:
+		entry.Status = resp.StatusCode
+		entry.Outcome = audit.OutcomeOK
+		body, readErr := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		if readErr != nil {
+			return nil, readErr
+		}
+		if resp.StatusCode >= 400 {
+			entry.Outcome = audit.OutcomeError
+			entry.Error = auditErrorMessage(body, resp.Status)
+		} else {
+			entry.ResourceID = auditResourceID(body)
+		}
+	}
+
+	if logErr := t.Session.Log.Append(entry); logErr != nil {
+		slog.Warn("audit log write failed", "err", logErr)
+	}
+
+	return resp, err
+}
+
+func auditSummary(raw []byte, contentType string) string {
+	body := dryRunBody(raw, contentType)
+	var s string
+	switch v := body.(type) {
+	case nil:
+		return ""
+	case string:
+		s = v
+	default:
+		b, err := json.Marshal(redactJSON(v, true))
+		if err != nil {
+			return ""
+		}
+		s = string(b)
+	}
+	if len(s) > maxAuditRequestSummary {
+		s = s[:maxAuditRequestSummary] + "…"
+	}
+
+	return s
+}
+
+func auditResourceID(body []byte) string {
+	var v struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	if json.Unmarshal(body, &v) != nil {
+		return ""
+	}
+	if v.ID != "" {
+		return v.ID
+	}
+	if strings.Contains(v.Name, "/") {
+		return v.Name
+	}
+
+	return ""
+}
+
+func auditErrorMessage(body []byte, status string) string {
+	var v struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &v) == nil && v.Error.Message != "" {
+		return v.Error.Message
+	}
+
+	return status
+}
@@ -0,0 +1,85 @@
+package googleapi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steipete/gogcli/internal/audit"
+)
+
+func TestAuditTransport_RecordsWrites(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet:
+			_, _ = io.WriteString(w, `{"id":"m1"}`)
+		case strings.HasSuffix(r.URL.Path, "/fail"):
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = io.WriteString(w, `{"error":{"code":403,"message":"Insufficient Permission"}}`)
+		default:
+			_, _ = io.WriteString(w, `{"id":"d1","message":{"id":"m9"}}`)
+		}
+	}))
+	defer srv.Close()
+
+	log := &audit.Log{Path: filepath.Join(t.TempDir(), "audit.jsonl")}
+	rt := &AuditTransport{
+		Base:    http.DefaultTransport,
+		Session: &audit.Session{Log: log, Run: "run1", Command: "gmail drafts create"},
+		Service: "gmail",
+		Account: "a@b.com",
+		Client:  "default",
+	}
+
+	do := func(method, path, body string) {
+		var r io.Reader
+		if body != "" {
+			r = strings.NewReader(body)
+		}
+		req, _ := http.NewRequestWithContext(context.Background(), method, srv.URL+path, r)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("%s %s: %v", method, path, err)
+		}
+		b, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if len(b) == 0 {
+			t.Fatalf("response body must be preserved")
+		}
+	}
+	do(http.MethodGet, "/gmail/v1/users/me/messages/m1", "")
+	do(http.MethodPost, "/gmail/v1/users/me/drafts", `{"message":{"raw":"SGVsbG8="}}`)
+	do(http.MethodDelete, "/gmail/v1/users/me/fail", "")
+	do(http.MethodPost, "/calendar/v3/freeBusy", `{"items":[{"id":"primary"}]}`)
+
+	entries, err := audit.Read(log.Path, audit.Filter{})
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 write entries, got %+v", entries)
+	}
+	var created, failed audit.Entry
+	for _, e := range entries {
+		if e.Method == http.MethodPost {
+			created = e
+		} else {
+			failed = e
+		}
+	}
+	if created.Resource != "/gmail/v1/users/me/drafts" || created.ResourceID != "d1" || created.Outcome != audit.OutcomeOK ||
+		created.Run != "run1" || created.Command != "gmail drafts create" || created.Account != "a@b.com" || created.Service != "gmail" {
+		t.Fatalf("unexpected create entry: %+v", created)
+	}
+	if strings.Contains(created.Request, "SGVsbG8=") || !strings.Contains(created.Request, redacted) {
+		t.Fatalf("request summary must redact bodies: %q", created.Request)
+	}
+	if failed.Outcome != audit.OutcomeError || failed.Status != 403 || failed.Error != "Insufficient Permission" {
+		t.Fatalf("unexpected failed entry: %+v", failed)
+	}
+}
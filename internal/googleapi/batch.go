@@ -0,0 +1,353 @@
+package googleapi
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	gapi "google.golang.org/api/googleapi"
+
+	"github.com/steipete/gogcli/internal/googleauth"
+)
+
+// MaxBatchSize is the most sub-requests Google accepts in one batch call.
+const MaxBatchSize = 100
+
+const (
+	gmailBatchEndpoint    = "https://gmail.googleapis.com/batch/gmail/v1"
+	driveBatchEndpoint    = "https://www.googleapis.com/batch/drive/v3"
+	calendarBatchEndpoint = "https://www.googleapis.com/batch/calendar/v3"
+)
+
+var (
+	errBatchMethod   = errors.New("batch supports GET sub-requests only")
+	errBatchResponse = errors.New("missing response in batch reply")
+)
+
+// BatchClient packs GET requests into multipart/mixed batch calls.
+// The outer call goes through the regular transport chain; sub-requests
+// that come back 429 or 5xx are re-sent in a follow-up batch.
+type BatchClient struct {
+	Client     *http.Client
+	Endpoint   string
+	MaxRetries int
+	BaseDelay  time.Duration
+}
+
+// BatchResult is the reply to one sub-request.
+type BatchResult struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	Err        error
+}
+
+// Decode unmarshals a successful reply into v, or returns the API error.
+func (r BatchResult) Decode(v any) error {
+	if r.Err != nil {
+		return r.Err
+	}
+
+	resp := &http.Response{
+		StatusCode: r.StatusCode,
+		Header:     r.Header,
+		Body:       io.NopCloser(bytes.NewReader(r.Body)),
+	}
+	if err := gapi.CheckResponse(resp); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(r.Body, v); err != nil {
+		return fmt.Errorf("decode batch response: %w", err)
+	}
+
+	return nil
+}
+
+func NewGmailBatch(ctx context.Context, email string) (*BatchClient, error) {
+	return newBatchClient(ctx, googleauth.ServiceGmail, email, gmailBatchEndpoint)
+}
+
+func NewDriveBatch(ctx context.Context, email string) (*BatchClient, error) {
+	return newBatchClient(ctx, googleauth.ServiceDrive, email, driveBatchEndpoint)
+}
+
+func NewCalendarBatch(ctx context.Context, email string) (*BatchClient, error) {
+	return newBatchClient(ctx, googleauth.ServiceCalendar, email, calendarBatchEndpoint)
+}
+
+func newBatchClient(ctx context.Context, service googleauth.Service, email string, endpoint string) (*BatchClient, error) {
+	scopes, err := googleauth.Scopes(service)
+	if err != nil {
+		return nil, fmt.Errorf("resolve scopes: %w", err)
+	}
+
+	c, err := httpClientForAccountScopes(ctx, string(service), email, scopes)
+	if err != nil {
+		return nil, fmt.Errorf("%s batch client: %w", service, err)
+	}
+
+	return &BatchClient{
+		Client:     c,
+		Endpoint:   endpoint,
+		MaxRetries: MaxRateLimitRetries,
+		BaseDelay:  RateLimitBaseDelay,
+	}, nil
+}
+
+// Do sends reqs in batches of at most MaxBatchSize and returns one result
+// per request, in the same order.
+func (b *BatchClient) Do(ctx context.Context, reqs []*http.Request) []BatchResult {
+	results := make([]BatchResult, len(reqs))
+
+	pending := make([]int, 0, len(reqs))
+	for i, req := range reqs {
+		if req.Method != http.MethodGet {
+			results[i] = BatchResult{Err: errBatchMethod}
+			continue
+		}
+		pending = append(pending, i)
+	}
+
+	for attempt := 0; len(pending) > 0; attempt++ {
+		var retry []int
+		for start := 0; start < len(pending); start += MaxBatchSize {
+			chunk := pending[start:min(start+MaxBatchSize, len(pending))]
+			b.send(ctx, reqs, chunk, results)
+			for _, idx := range chunk {
+				if retryableStatus(results[idx].StatusCode) {
+					retry = append(retry, idx)
+				}
+			}
+		}
+
+		if len(retry) == 0 || attempt >= b.MaxRetries {
+			break
+		}
+
+		delay := b.backoff(attempt)
+		slog.Debug("batch sub-requests failed, retrying", "count", len(retry), "delay", delay, "attempt", attempt+1)
+		if err := sleepContext(ctx, delay); err != nil {
+			for _, idx := range retry {
+				results[idx] = BatchResult{Err: err}
+			}
+			break
+		}
+		pending = retry
+	}
+
+	return results
+}
+
+func (b *BatchClient) send(ctx context.Context, reqs []*http.Request, chunk []int, results []BatchResult) {
+	fail := func(err error) {
+		for _, idx := range chunk {
+			results[idx] = BatchResult{Err: err}
+		}
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for _, idx := range chunk {
+		if err := writeBatchPart(mw, idx, reqs[idx]); err != nil {
+			fail(err)
+			return
+		}
+	}
+	if err := mw.Close(); err != nil {
+		fail(fmt.Errorf("write batch body: %w", err))
+		return
+	}
+
+	// The limiter charges the batch for what its sub-requests would cost.
+	var cost float64
+	for _, idx := range chunk {
+		cost += requestCost(reqs[idx])
+	}
+
+	req, err := http.NewRequestWithContext(withRequestCost(withReadOnlyBatch(ctx), cost), http.MethodPost, b.Endpoint, bytes.NewReader(body.Bytes()))
+	if err != nil {
+		fail(fmt.Errorf("build batch request: %w", err))
+		return
+	}
+	req.Header.Set("Content-Type", "multipart/mixed; boundary="+mw.Boundary())
+
+	client := b.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		fail(fmt.Errorf("batch request: %w", err))
+		return
+	}
+	defer resp.Body.Close()
+
+	if err := gapi.CheckResponse(resp); err != nil {
+		fail(err)
+		return
+	}
+
+	parts, err := readBatchResponse(resp)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	for _, idx := range chunk {
+		if r, ok := parts[idx]; ok {
+			results[idx] = r
+		} else {
+			results[idx] = BatchResult{Err: errBatchResponse}
+		}
+	}
+}
+
+func writeBatchPart(mw *multipart.Writer, idx int, req *http.Request) error {
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Type", "application/http")
+	h.Set("Content-ID", "<item-"+strconv.Itoa(idx)+">")
+
+	pw, err := mw.CreatePart(h)
+	if err != nil {
+		return fmt.Errorf("write batch part: %w", err)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s %s HTTP/1.1\r\n", req.Method, req.URL.RequestURI())
+	for k, vs := range req.Header {
+		for _, v := range vs {
+			fmt.Fprintf(&sb, "%s: %s\r\n", k, v)
+		}
+	}
+	sb.WriteString("\r\n")
+
+	if _, err := io.WriteString(pw, sb.String()); err != nil {
+		return fmt.Errorf("write batch part: %w", err)
+	}
+
+	return nil
+}
+
+// readBatchResponse maps each part's Content-ID back to the request index.
+func readBatchResponse(resp *http.Response) (map[int]BatchResult, error) {
+	mediaType, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+		return nil, fmt.Errorf("unexpected batch content type %q", resp.Header.Get("Content-Type"))
+	}
+
+	out := map[int]BatchResult{}
+	mr := multipart.NewReader(resp.Body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			return out, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read batch part: %w", err)
+		}
+
+		idx, ok := batchPartIndex(part.Header.Get("Content-ID"))
+		if !ok {
+			continue
+		}
+
+		sub, err := http.ReadResponse(bufio.NewReader(part), nil)
+		if err != nil {
+			out[idx] = BatchResult{Err: fmt.Errorf("parse batch part: %w", err)}
+			continue
+		}
+		data, err := io.ReadAll(sub.Body)
+		_ = sub.Body.Close()
+		if err != nil {
+			out[idx] = BatchResult{Err: fmt.Errorf("read batch part: %w", err)}
+			continue
+		}
+
+		out[idx] = BatchResult{StatusCode: sub.StatusCode, Header: sub.Header, Body: data}
+	}
+}
+
+func batchPartIndex(contentID string) (int, bool) {
+	id := strings.Trim(strings.TrimSpace(contentID), "<>")
+	id = strings.TrimPrefix(id, "response-")
+	n, err := strconv.Atoi(strings.TrimPrefix(id, "item-"))
+	if err != nil || !strings.HasPrefix(id, "item-") {
+		return 0, false
+	}
+	return n, true
+}
+
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= 500
+}
+
+func (b *BatchClient) backoff(attempt int) time.Duration {
+	if b.BaseDelay <= 0 {
+		return 0
+	}
+	return b.BaseDelay * time.Duration(1<<attempt)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("sleep interrupted: %w", ctx.Err())
+	}
+}
+
+type readOnlyBatchKey struct{}
+
+// withReadOnlyBatch marks a batch POST whose sub-requests are all reads, so
+// dry-run, audit and cache layers treat it like a GET.
+func withReadOnlyBatch(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readOnlyBatchKey{}, true)
+}
+
+// readOnlyQueryPaths are POST endpoints that only query data.
+var readOnlyQueryPaths = []string{
+	"/calendar/v3/freeBusy",
+	":batchGetByDataFilter",
+	":getByDataFilter",
+}
+
+// isReadRequest reports whether req cannot change server state. Every
+// layer (read-only, dry-run, audit, cache, scope tracking) classifies
+// requests with it.
+func isReadRequest(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	case http.MethodPost:
+		if req.URL != nil {
+			for _, suffix := range readOnlyQueryPaths {
+				if strings.HasSuffix(req.URL.Path, suffix) {
+					return true
+				}
+			}
+		}
+	}
+	v, _ := req.Context().Value(readOnlyBatchKey{}).(bool)
+	return v
+}
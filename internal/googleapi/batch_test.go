@@ -0,0 +1,180 @@
+package googleapi
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// batchServer answers each sub-request with handler(path, attempt).
+func batchServer(t *testing.T, handler func(path string, attempt int) (int, string)) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var calls int32
+	attempts := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.Method != http.MethodPost || r.URL.Path != "/batch/test/v1" {
+			http.Error(w, "bad batch request", http.StatusBadRequest)
+			return
+		}
+		_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var out bytes.Buffer
+		mw := multipart.NewWriter(&out)
+		mr := multipart.NewReader(r.Body, params["boundary"])
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			sub, err := http.ReadRequest(bufio.NewReader(part))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			attempts[sub.URL.Path]++
+			code, body := handler(sub.URL.Path, attempts[sub.URL.Path])
+
+			h := textproto.MIMEHeader{}
+			h.Set("Content-Type", "application/http")
+			h.Set("Content-ID", "<response-"+strings.Trim(part.Header.Get("Content-ID"), "<>")+">")
+			pw, _ := mw.CreatePart(h)
+			fmt.Fprintf(pw, "HTTP/1.1 %d %s\r\nContent-Type: application/json\r\n\r\n%s", code, http.StatusText(code), body)
+		}
+		_ = mw.Close()
+
+		w.Header().Set("Content-Type", "multipart/mixed; boundary="+mw.Boundary())
+		_, _ = w.Write(out.Bytes())
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func batchGet(t *testing.T, url string) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	return req
+}
+
+func TestBatchClient_Do(t *testing.T) {
+	srv, calls := batchServer(t, func(path string, _ int) (int, string) {
+		if path == "/v1/items/missing" {
+			return http.StatusNotFound, `{"error":{"code":404,"message":"Not Found"}}`
+		}
+		return http.StatusOK, fmt.Sprintf(`{"id":%q}`, strings.TrimPrefix(path, "/v1/items/"))
+	})
+
+	b := &BatchClient{Client: srv.Client(), Endpoint: srv.URL + "/batch/test/v1"}
+	reqs := []*http.Request{
+		batchGet(t, srv.URL+"/v1/items/a"),
+		batchGet(t, srv.URL+"/v1/items/missing"),
+		batchGet(t, srv.URL+"/v1/items/c?fields=id"),
+	}
+	results := b.Do(context.Background(), reqs)
+	if len(results) != 3 {
+		t.Fatalf("results: %d", len(results))
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Fatalf("expected one batch call, got %d", got)
+	}
+
+	var item struct {
+		ID string `json:"id"`
+	}
+	if err := results[0].Decode(&item); err != nil || item.ID != "a" {
+		t.Fatalf("first: %v %#v", err, item)
+	}
+	if err := results[1].Decode(&item); err == nil || !strings.Contains(err.Error(), "Not Found") {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if err := results[2].Decode(&item); err != nil || item.ID != "c" {
+		t.Fatalf("third: %v %#v", err, item)
+	}
+}
+
+func TestBatchClient_RetriesFailedSubRequests(t *testing.T) {
+	srv, calls := batchServer(t, func(path string, attempt int) (int, string) {
+		if path == "/v1/items/flaky" && attempt == 1 {
+			return http.StatusTooManyRequests, `{"error":{"code":429,"message":"slow down"}}`
+		}
+		return http.StatusOK, `{"ok":true}`
+	})
+
+	b := &BatchClient{Client: srv.Client(), Endpoint: srv.URL + "/batch/test/v1", MaxRetries: 2}
+	results := b.Do(context.Background(), []*http.Request{
+		batchGet(t, srv.URL+"/v1/items/steady"),
+		batchGet(t, srv.URL+"/v1/items/flaky"),
+	})
+	for i, r := range results {
+		if r.StatusCode != http.StatusOK {
+			t.Fatalf("result %d: status %d err %v", i, r.StatusCode, r.Err)
+		}
+	}
+	if got := atomic.LoadInt32(calls); got != 2 {
+		t.Fatalf("expected retry batch, got %d calls", got)
+	}
+}
+
+func TestBatchClient_ChunksAndRejectsWrites(t *testing.T) {
+	srv, calls := batchServer(t, func(string, int) (int, string) {
+		return http.StatusOK, `{}`
+	})
+
+	reqs := make([]*http.Request, 0, MaxBatchSize+2)
+	for i := 0; i < MaxBatchSize+1; i++ {
+		reqs = append(reqs, batchGet(t, fmt.Sprintf("%s/v1/items/%d", srv.URL, i)))
+	}
+	post, _ := http.NewRequest(http.MethodPost, srv.URL+"/v1/items", strings.NewReader("{}"))
+	reqs = append(reqs, post)
+
+	b := &BatchClient{Client: srv.Client(), Endpoint: srv.URL + "/batch/test/v1"}
+	results := b.Do(context.Background(), reqs)
+	if got := atomic.LoadInt32(calls); got != 2 {
+		t.Fatalf("expected 2 batch calls, got %d", got)
+	}
+	if results[MaxBatchSize].StatusCode != http.StatusOK {
+		t.Fatalf("last GET: %#v", results[MaxBatchSize])
+	}
+	if results[len(results)-1].Err == nil {
+		t.Fatalf("expected error for POST sub-request")
+	}
+}
+
+func TestDryRunTransport_PassesReadOnlyBatch(t *testing.T) {
+	srv, calls := batchServer(t, func(string, int) (int, string) {
+		return http.StatusOK, `{}`
+	})
+
+	var out bytes.Buffer
+	client := &http.Client{Transport: &DryRunTransport{Base: http.DefaultTransport, DryRun: DryRun{Out: &out}}}
+	b := &BatchClient{Client: client, Endpoint: srv.URL + "/batch/test/v1"}
+	results := b.Do(context.Background(), []*http.Request{batchGet(t, srv.URL+"/v1/items/a")})
+	if results[0].StatusCode != http.StatusOK || atomic.LoadInt32(calls) != 1 {
+		t.Fatalf("batch read should reach server: %#v", results[0])
+	}
+	if out.Len() != 0 {
+		t.Fatalf("unexpected dry-run output: %q", out.String())
+	}
+}
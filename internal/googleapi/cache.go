@@ -0,0 +1,315 @@
+package googleapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+)
+
+// CacheMode selects how CacheTransport uses the on-disk response cache.
+type CacheMode string
+
+const (
+	// CacheOff bypasses the cache entirely (default).
+	CacheOff CacheMode = "off"
+	// CacheRead serves fresh entries, revalidates stale ones and stores misses.
+	CacheRead CacheMode = "read"
+	// CacheRefresh always fetches from the network and overwrites entries.
+	CacheRefresh CacheMode = "refresh"
+)
+
+const (
+	// DefaultCacheTTL is how long entries without an explicit max-age stay fresh.
+	DefaultCacheTTL = 5 * time.Minute
+	// maxCacheBodyBytes keeps downloads and exports out of the cache.
+	maxCacheBodyBytes = 4 << 20
+)
+
+func ParseCacheMode(s string) (CacheMode, error) {
+	switch m := CacheMode(strings.ToLower(strings.TrimSpace(s))); m {
+	case "", CacheOff:
+		return CacheOff, nil
+	case CacheRead, CacheRefresh:
+		return m, nil
+	default:
+		return "", fmt.Errorf("invalid cache mode %q (expected off|read|refresh)", s)
+	}
+}
+
+type cacheModeKey struct{}
+
+func WithCacheMode(ctx context.Context, mode CacheMode) context.Context {
+	return context.WithValue(ctx, cacheModeKey{}, mode)
+}
+
+func CacheModeFromContext(ctx context.Context) CacheMode {
+	if ctx == nil {
+		return CacheOff
+	}
+	if m, ok := ctx.Value(cacheModeKey{}).(CacheMode); ok && m != "" {
+		return m
+	}
+	return CacheOff
+}
+
+// CacheTransport caches successful GET responses on disk, keyed by account,
+// scopes and URL. Stale entries are revalidated with If-None-Match /
+// If-Modified-Since; "Cache-Control: no-store" responses are never stored.
+// Any successful non-GET request drops the account's entries so reads after
+// writes are not served stale.
+type CacheTransport struct {
+	Base    http.RoundTripper
+	Dir     string
+	Account string
+	Scopes  []string
+	Mode    CacheMode
+	TTL     time.Duration
+	now     func() time.Time
+}
+
+type cacheEntry struct {
+	URL          string      `json:"url"`
+	StatusCode   int         `json:"status"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+	StoredAt     time.Time   `json:"stored_at"`
+	FreshUntil   time.Time   `json:"fresh_until"`
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"last_modified,omitempty"`
+}
+
+// wrapCacheTransport puts a CacheTransport in front of base when the context
+// enables caching; otherwise base is returned unchanged.
+func wrapCacheTransport(ctx context.Context, email string, scopes []string, base http.RoundTripper) http.RoundTripper {
+	mode := CacheModeFromContext(ctx)
+	if mode == CacheOff {
+		return base
+	}
+	dir, err := config.HTTPCacheDir()
+	if err != nil {
+		slog.Debug("http cache disabled", "err", err)
+		return base
+	}
+	ttl := DefaultCacheTTL
+	if cfg, err := config.ReadConfig(); err == nil && strings.TrimSpace(cfg.CacheTTL) != "" {
+		if d, err := time.ParseDuration(strings.TrimSpace(cfg.CacheTTL)); err == nil && d > 0 {
+			ttl = d
+		}
+	}
+	return &CacheTransport{
+		Base:    base,
+		Dir:     dir,
+		Account: email,
+		Scopes:  scopes,
+		Mode:    mode,
+		TTL:     ttl,
+	}
+}
+
+func (t *CacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.Mode == CacheOff || t.Mode == "" || t.Dir == "" {
+		return t.Base.RoundTrip(req)
+	}
+
+	if req.Method != http.MethodGet {
+		resp, err := t.Base.RoundTrip(req)
+		if err == nil && resp.StatusCode < 400 && !isReadRequest(req) {
+			t.purgeAccount()
+		}
+		return resp, err
+	}
+
+	if !cacheableRequest(req) {
+		return t.Base.RoundTrip(req)
+	}
+
+	path := t.entryPath(req)
+	var cached *cacheEntry
+	if t.Mode == CacheRead {
+		cached = readCacheEntry(path)
+		if cached != nil && t.clock().Before(cached.FreshUntil) {
+			slog.Debug("http cache hit", "url", cached.URL)
+			return cached.response(req), nil
+		}
+		if cached != nil {
+			req = req.Clone(req.Context())
+			if cached.ETag != "" {
+				req.Header.Set("If-None-Match", cached.ETag)
+			}
+			if cached.LastModified != "" {
+				req.Header.Set("If-Modified-Since", cached.LastModified)
+			}
+		}
+	}
+
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		drainAndClose(resp.Body)
+		cached.FreshUntil = t.freshUntil(resp.Header)
+		cached.StoredAt = t.clock()
+		writeCacheEntry(path, cached)
+		slog.Debug("http cache revalidated", "url", cached.URL)
+		return cached.response(req), nil
+	}
+
+	if resp.StatusCode != http.StatusOK || noStore(resp.Header) {
+		return resp, nil
+	}
+	if resp.ContentLength > maxCacheBodyBytes {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCacheBodyBytes+1))
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if len(body) > maxCacheBodyBytes {
+		return resp, nil
+	}
+
+	entry := &cacheEntry{
+		URL:          req.URL.String(),
+		StatusCode:   resp.StatusCode,
+		Header:       resp.Header.Clone(),
+		Body:         body,
+		StoredAt:     t.clock(),
+		FreshUntil:   t.freshUntil(resp.Header),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	writeCacheEntry(path, entry)
+	return resp, nil
+}
+
+func (t *CacheTransport) clock() time.Time {
+	if t.now != nil {
+		return t.now()
+	}
+	return time.Now()
+}
+
+// freshUntil honors an explicit positive max-age; otherwise (Google APIs
+// usually send "private, max-age=0") the configured TTL applies.
+func (t *CacheTransport) freshUntil(h http.Header) time.Time {
+	if maxAge, ok := cacheMaxAge(h); ok && maxAge > 0 {
+		return t.clock().Add(maxAge)
+	}
+	ttl := t.TTL
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return t.clock().Add(ttl)
+}
+
+func (t *CacheTransport) accountDir() string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(t.Account))))
+	return filepath.Join(t.Dir, hex.EncodeToString(sum[:8]))
+}
+
+func (t *CacheTransport) entryPath(req *http.Request) string {
+	scopes := append([]string(nil), t.Scopes...)
+	sort.Strings(scopes)
+	sum := sha256.Sum256([]byte(strings.Join(scopes, " ") + "\n" + req.URL.String()))
+	return filepath.Join(t.accountDir(), hex.EncodeToString(sum[:])+".json")
+}
+
+func (t *CacheTransport) purgeAccount() {
+	if err := os.RemoveAll(t.accountDir()); err != nil {
+		slog.Debug("http cache purge failed", "err", err)
+	}
+}
+
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+func cacheableRequest(req *http.Request) bool {
+	if req.URL == nil {
+		return false
+	}
+	// Media downloads and exports can be large and are not worth caching.
+	if req.URL.Query().Get("alt") == "media" || strings.Contains(req.URL.Path, "/export") {
+		return false
+	}
+	cc := strings.ToLower(req.Header.Get("Cache-Control"))
+	return !strings.Contains(cc, "no-store")
+}
+
+func noStore(h http.Header) bool {
+	return strings.Contains(strings.ToLower(h.Get("Cache-Control")), "no-store")
+}
+
+func cacheMaxAge(h http.Header) (time.Duration, bool) {
+	for _, directive := range strings.Split(h.Get("Cache-Control"), ",") {
+		directive = strings.TrimSpace(strings.ToLower(directive))
+		if v, ok := strings.CutPrefix(directive, "max-age="); ok {
+			secs, err := strconv.Atoi(v)
+			if err != nil {
+				return 0, false
+			}
+			return time.Duration(secs) * time.Second, true
+		}
+	}
+	return 0, false
+}
+
+func readCacheEntry(path string) *cacheEntry {
+	data, err := os.ReadFile(path) //nolint:gosec // cache path derived from hashes under config dir
+	if err != nil {
+		return nil
+	}
+	var e cacheEntry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil
+	}
+	return &e
+}
+
+func writeCacheEntry(path string, e *cacheEntry) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		slog.Debug("http cache write failed", "err", err)
+		return
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		slog.Debug("http cache write failed", "err", err)
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		slog.Debug("http cache write failed", "err", err)
+	}
+}
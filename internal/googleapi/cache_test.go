@@ -0,0 +1,177 @@
+package googleapi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func cacheGet(t *testing.T, rt http.RoundTripper, url string) (int, string) {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("round trip: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+func TestCacheTransport_ServesFreshAndRevalidates(t *testing.T) {
+	var hits, notModified atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Cache-Control", "private, max-age=0")
+		_, _ = io.WriteString(w, `{"labels":[]}`)
+	}))
+	defer srv.Close()
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	rt := &CacheTransport{
+		Base:    http.DefaultTransport,
+		Dir:     t.TempDir(),
+		Account: "a@b.com",
+		Scopes:  []string{"s2", "s1"},
+		Mode:    CacheRead,
+		TTL:     time.Minute,
+		now:     func() time.Time { return now },
+	}
+
+	for i := 0; i < 2; i++ {
+		if code, body := cacheGet(t, rt, srv.URL+"/labels"); code != 200 || body != `{"labels":[]}` {
+			t.Fatalf("unexpected response %d %q", code, body)
+		}
+	}
+	if hits.Load() != 1 {
+		t.Fatalf("expected 1 upstream hit, got %d", hits.Load())
+	}
+
+	now = now.Add(2 * time.Minute)
+	if code, body := cacheGet(t, rt, srv.URL+"/labels"); code != 200 || body != `{"labels":[]}` {
+		t.Fatalf("unexpected revalidated response %d %q", code, body)
+	}
+	if notModified.Load() != 1 {
+		t.Fatalf("expected conditional request, got %d", notModified.Load())
+	}
+
+	// Revalidation refreshed the entry.
+	cacheGet(t, rt, srv.URL+"/labels")
+	if hits.Load() != 2 {
+		t.Fatalf("expected 2 upstream hits, got %d", hits.Load())
+	}
+}
+
+func TestCacheTransport_RefreshAndNoStore(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if strings.HasSuffix(r.URL.Path, "/secret") {
+			w.Header().Set("Cache-Control", "no-store")
+		}
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	refresh := &CacheTransport{Base: http.DefaultTransport, Dir: dir, Account: "a@b.com", Mode: CacheRefresh}
+	read := &CacheTransport{Base: http.DefaultTransport, Dir: dir, Account: "a@b.com", Mode: CacheRead}
+
+	cacheGet(t, refresh, srv.URL+"/x")
+	cacheGet(t, refresh, srv.URL+"/x")
+	if hits.Load() != 2 {
+		t.Fatalf("refresh should bypass reads, got %d hits", hits.Load())
+	}
+	cacheGet(t, read, srv.URL+"/x")
+	if hits.Load() != 2 {
+		t.Fatalf("read should use refreshed entry, got %d hits", hits.Load())
+	}
+
+	cacheGet(t, read, srv.URL+"/secret")
+	cacheGet(t, read, srv.URL+"/secret")
+	if hits.Load() != 4 {
+		t.Fatalf("no-store responses must not be cached, got %d hits", hits.Load())
+	}
+
+	// Other accounts don't share entries.
+	other := &CacheTransport{Base: http.DefaultTransport, Dir: dir, Account: "c@d.com", Mode: CacheRead}
+	cacheGet(t, other, srv.URL+"/x")
+	if hits.Load() != 5 {
+		t.Fatalf("expected per-account cache, got %d hits", hits.Load())
+	}
+}
+
+func TestCacheTransport_WritePurgesAccount(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+
+	rt := &CacheTransport{Base: http.DefaultTransport, Dir: t.TempDir(), Account: "a@b.com", Mode: CacheRead}
+	cacheGet(t, rt, srv.URL+"/x")
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodPost, srv.URL+"/x", strings.NewReader("{}"))
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	resp.Body.Close()
+
+	cacheGet(t, rt, srv.URL+"/x")
+	if hits.Load() != 3 {
+		t.Fatalf("expected cache purge after write, got %d hits", hits.Load())
+	}
+}
+
+func TestCacheTransport_QueryKeepsCache(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+
+	rt := &CacheTransport{Base: http.DefaultTransport, Dir: t.TempDir(), Account: "a@b.com", Mode: CacheRead}
+	cacheGet(t, rt, srv.URL+"/x")
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodPost, srv.URL+"/calendar/v3/freeBusy", strings.NewReader("{}"))
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	resp.Body.Close()
+
+	cacheGet(t, rt, srv.URL+"/x")
+	if hits.Load() != 2 {
+		t.Fatalf("expected the cache to survive a query, got %d hits", hits.Load())
+	}
+}
+
+func TestParseCacheMode(t *testing.T) {
+	for in, want := range map[string]CacheMode{"": CacheOff, "OFF": CacheOff, "read": CacheRead, " refresh ": CacheRefresh} {
+		got, err := ParseCacheMode(in)
+		if err != nil || got != want {
+			t.Fatalf("ParseCacheMode(%q) = %q, %v", in, got, err)
+		}
+	}
+	if _, err := ParseCacheMode("always"); err == nil {
+		t.Fatalf("expected error")
+	}
+}
@@ -0,0 +1,380 @@
+package googleapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Cassette env vars. GOG_RECORD and GOG_REPLAY take a directory;
+// GOG_RECORD_REDACT=bodies additionally scrubs message/file bodies.
+const (
+	envRecord       = "GOG_RECORD"
+	envReplay       = "GOG_REPLAY"
+	envRecordRedact = "GOG_RECORD_REDACT"
+)
+
+const (
+	redacted              = "REDACTED"
+	maxCassetteBodyBytes  = 1 << 20
+	cassetteFilePattern   = "%04d-%s.json"
+	cassetteSchemaVersion = 1
+)
+
+var errNoRecording = errors.New("no recorded interaction")
+
+// cassetteSeq numbers cassettes per directory across every RecordTransport
+// in the process; one command builds a transport per API client.
+var cassetteSeq = struct {
+	mu   sync.Mutex
+	next map[string]int
+}{next: map[string]int{}}
+
+// Headers that never make it into a cassette.
+var cassetteSensitiveHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "X-Goog-Api-Key", "Proxy-Authorization"}
+
+// Query parameters stripped from recorded URLs and ignored when matching.
+var cassetteSensitiveParams = []string{"access_token", "key", "oauth_token"}
+
+// JSON fields replaced in recorded bodies. Token fields are always redacted;
+// body fields only with GOG_RECORD_REDACT=bodies.
+var (
+	cassetteTokenFields = map[string]bool{"access_token": true, "refresh_token": true, "id_token": true, "client_secret": true}
+	cassetteBodyFields  = map[string]bool{"raw": true, "data": true, "snippet": true, "body": true, "textBody": true, "htmlBody": true}
+)
+
+type cassette struct {
+	Version  int              `json:"version"`
+	Request  cassetteRequest  `json:"request"`
+	Response cassetteResponse `json:"response"`
+}
+
+type cassetteRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+type cassetteResponse struct {
+	StatusCode int         `json:"status"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+	Truncated  bool        `json:"truncated,omitempty"` // Body holds only the first maxCassetteBodyBytes
+}
+
+// RecordTransport forwards requests to Base and writes each exchange to Dir
+// as a numbered JSON cassette. Credentials are always stripped.
+type RecordTransport struct {
+	Base         http.RoundTripper
+	Dir          string
+	RedactBodies bool
+}
+
+func (t *RecordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		b, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read request body: %w", err)
+		}
+		reqBody = b
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(b))
+		req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(b)), nil }
+	}
+
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Downloads can be large: keep only the first maxCassetteBodyBytes and
+	// stream the rest to the caller.
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxCassetteBodyBytes+1))
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+	truncated := len(respBody) > maxCassetteBodyBytes
+	if truncated {
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(respBody), resp.Body), resp.Body}
+		respBody = respBody[:maxCassetteBodyBytes]
+	} else {
+		_ = resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	}
+
+	c := cassette{
+		Version: cassetteSchemaVersion,
+		Request: cassetteRequest{
+			Method: req.Method,
+			URL:    cassetteURL(req.URL),
+			Header: cassetteHeader(req.Header),
+			Body:   t.sanitizeBody(reqBody, req.Header.Get("Content-Type")),
+		},
+		Response: cassetteResponse{
+			StatusCode: resp.StatusCode,
+			Header:     cassetteHeader(resp.Header),
+			Body:       t.sanitizeBody(respBody, resp.Header.Get("Content-Type")),
+			Truncated:  truncated,
+		},
+	}
+	if err := t.write(req, c); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (t *RecordTransport) write(req *http.Request, c cassette) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode cassette: %w", err)
+	}
+
+	cassetteSeq.mu.Lock()
+	defer cassetteSeq.mu.Unlock()
+
+	if err := os.MkdirAll(t.Dir, 0o700); err != nil {
+		return fmt.Errorf("create cassette dir: %w", err)
+	}
+	key := filepath.Clean(t.Dir)
+	if abs, err := filepath.Abs(key); err == nil {
+		key = abs
+	}
+	seq, ok := cassetteSeq.next[key]
+	if !ok {
+		existing, _ := filepath.Glob(filepath.Join(t.Dir, "*.json"))
+		seq = len(existing)
+	}
+
+	// O_EXCL guards against other processes recording into the same dir.
+	for {
+		seq++
+		name := fmt.Sprintf(cassetteFilePattern, seq, strings.ToLower(req.Method))
+		f, err := os.OpenFile(filepath.Join(t.Dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("write cassette: %w", err)
+		}
+		cassetteSeq.next[key] = seq
+		_, err = f.Write(data)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("write cassette: %w", err)
+		}
+		return nil
+	}
+}
+
+func (t *RecordTransport) sanitizeBody(body []byte, contentType string) string {
+	if len(body) == 0 {
+		return ""
+	}
+	if len(body) > maxCassetteBodyBytes {
+		return redacted
+	}
+	if !strings.Contains(contentType, "json") {
+		if t.RedactBodies {
+			return redacted
+		}
+		return string(body)
+	}
+
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return string(body)
+	}
+	v = redactJSON(v, t.RedactBodies)
+	out, err := json.Marshal(v)
+	if err != nil {
+		return string(body)
+	}
+	return string(out)
+}
+
+func redactJSON(v any, bodies bool) any {
+	switch x := v.(type) {
+	case map[string]any:
+		for k, val := range x {
+			if cassetteTokenFields[k] || (bodies && cassetteBodyFields[k]) {
+				if _, isString := val.(string); isString {
+					x[k] = redacted
+					continue
+				}
+			}
+			x[k] = redactJSON(val, bodies)
+		}
+		return x
+	case []any:
+		for i := range x {
+			x[i] = redactJSON(x[i], bodies)
+		}
+		return x
+	default:
+		return v
+	}
+}
+
+func cassetteHeader(h http.Header) http.Header {
+	out := h.Clone()
+	for _, k := range cassetteSensitiveHeaders {
+		out.Del(k)
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+func cassetteURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	clean := *u
+	q := clean.Query()
+	for _, p := range cassetteSensitiveParams {
+		q.Del(p)
+	}
+	clean.RawQuery = q.Encode()
+	return clean.String()
+}
+
+// ReplayTransport serves responses from cassettes written by RecordTransport
+// without touching the network. Requests match on method and URL (minus
+// credentials), in recorded order; once every match has been used the last
+// one keeps being served, so polling loops still work.
+type ReplayTransport struct {
+	Dir string
+
+	once    sync.Once
+	loadErr error
+	mu      sync.Mutex
+	entries []*replayEntry
+}
+
+type replayEntry struct {
+	key  string
+	c    cassette
+	used bool
+}
+
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	t.once.Do(t.load)
+	if t.loadErr != nil {
+		return nil, t.loadErr
+	}
+
+	key := req.Method + " " + cassetteURL(req.URL)
+
+	t.mu.Lock()
+	var match *replayEntry
+	for _, e := range t.entries {
+		if e.key != key {
+			continue
+		}
+		match = e
+		if !e.used {
+			break
+		}
+	}
+	if match != nil {
+		match.used = true
+	}
+	t.mu.Unlock()
+
+	if match == nil {
+		return nil, fmt.Errorf("%w for %s in %s", errNoRecording, key, t.Dir)
+	}
+	if match.c.Response.Truncated {
+		return nil, fmt.Errorf("recorded response for %s in %s is truncated to %d bytes", key, t.Dir, maxCassetteBodyBytes)
+	}
+
+	body := match.c.Response.Body
+	header := match.c.Response.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", match.c.Response.StatusCode, http.StatusText(match.c.Response.StatusCode)),
+		StatusCode:    match.c.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func (t *ReplayTransport) load() {
+	paths, err := filepath.Glob(filepath.Join(t.Dir, "*.json"))
+	if err != nil {
+		t.loadErr = fmt.Errorf("list cassettes: %w", err)
+		return
+	}
+	if len(paths) == 0 {
+		t.loadErr = fmt.Errorf("%w: %s has no cassettes", errNoRecording, t.Dir)
+		return
+	}
+	sort.Strings(paths)
+
+	for _, p := range paths {
+		data, err := os.ReadFile(p) //nolint:gosec // user-provided replay directory
+		if err != nil {
+			t.loadErr = fmt.Errorf("read cassette: %w", err)
+			return
+		}
+		var c cassette
+		if err := json.Unmarshal(data, &c); err != nil {
+			t.loadErr = fmt.Errorf("parse cassette %s: %w", filepath.Base(p), err)
+			return
+		}
+		u, err := url.Parse(c.Request.URL)
+		if err != nil {
+			t.loadErr = fmt.Errorf("parse cassette %s: %w", filepath.Base(p), err)
+			return
+		}
+		t.entries = append(t.entries, &replayEntry{key: c.Request.Method + " " + cassetteURL(u), c: c})
+	}
+}
+
+func replayDir() string {
+	return strings.TrimSpace(os.Getenv(envReplay))
+}
+
+// wrapRecordTransport records through base when GOG_RECORD is set.
+func wrapRecordTransport(base http.RoundTripper) http.RoundTripper {
+	dir := strings.TrimSpace(os.Getenv(envRecord))
+	if dir == "" {
+		return base
+	}
+	redact := false
+	for _, part := range strings.Split(os.Getenv(envRecordRedact), ",") {
+		if strings.EqualFold(strings.TrimSpace(part), "bodies") {
+			redact = true
+		}
+	}
+	return &RecordTransport{Base: base, Dir: dir, RedactBodies: redact}
+}
@@ -0,0 +1,176 @@
+package googleapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steipete/gogcli/internal/secrets"
+)
+
+func TestRecordReplay_RoundTrip(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "sid=secret")
+		if calls == 1 {
+			_, _ = io.WriteString(w, `{"id":"m1","raw":"SGVsbG8=","access_token":"ya29.secret"}`)
+			return
+		}
+		_, _ = io.WriteString(w, `{"id":"m2","raw":"V29ybGQ="}`)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	rec := &RecordTransport{Base: http.DefaultTransport, Dir: dir, RedactBodies: true}
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL+"/messages/x?format=raw&access_token=tok", nil)
+		req.Header.Set("Authorization", "Bearer tok")
+		resp, err := rec.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("record: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if !strings.Contains(string(body), `"raw"`) || strings.Contains(string(body), redacted) {
+			t.Fatalf("caller must see the real body, got %q", body)
+		}
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 2 || filepath.Base(files[0]) != "0001-get.json" {
+		t.Fatalf("unexpected cassettes: %v", files)
+	}
+	data, _ := os.ReadFile(files[0])
+	for _, secret := range []string{"Bearer tok", "access_token=tok", "ya29.secret", "SGVsbG8=", "sid=secret"} {
+		if strings.Contains(string(data), secret) {
+			t.Fatalf("cassette leaks %q:\n%s", secret, data)
+		}
+	}
+
+	replay := &ReplayTransport{Dir: dir}
+	var ids []string
+	for i := 0; i < 3; i++ {
+		req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL+"/messages/x?access_token=other&format=raw", nil)
+		resp, err := replay.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("replay: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		var msg struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(body, &msg); err != nil {
+			t.Fatalf("decode replay body: %v", err)
+		}
+		ids = append(ids, msg.ID)
+	}
+	if strings.Join(ids, ",") != "m1,m2,m2" {
+		t.Fatalf("unexpected replay order: %v", ids)
+	}
+	if calls != 2 {
+		t.Fatalf("replay must not hit the network, got %d calls", calls)
+	}
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodDelete, srv.URL+"/messages/x", nil)
+	if _, err := replay.RoundTrip(req); !errors.Is(err, errNoRecording) {
+		t.Fatalf("expected errNoRecording, got %v", err)
+	}
+}
+
+func TestRecordTransport_TruncatesLargeBodies(t *testing.T) {
+	payload := strings.Repeat("x", maxCassetteBodyBytes+10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = io.WriteString(w, payload)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	rec := &RecordTransport{Base: http.DefaultTransport, Dir: dir}
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL+"/files/f1?alt=media", nil)
+	resp, err := rec.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != payload {
+		t.Fatalf("caller must see the full body, got %d bytes", len(body))
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "0001-get.json"))
+	if err != nil {
+		t.Fatalf("read cassette: %v", err)
+	}
+	var c cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		t.Fatalf("decode cassette: %v", err)
+	}
+	if !c.Response.Truncated || len(c.Response.Body) != maxCassetteBodyBytes {
+		t.Fatalf("expected a truncated body, got truncated=%t len=%d", c.Response.Truncated, len(c.Response.Body))
+	}
+
+	replay := &ReplayTransport{Dir: dir}
+	req, _ = http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL+"/files/f1?alt=media", nil)
+	if _, err := replay.RoundTrip(req); err == nil || !strings.Contains(err.Error(), "truncated") {
+		t.Fatalf("expected truncated replay error, got %v", err)
+	}
+}
+
+func TestOptionsForAccountScopes_ReplayNeedsNoCredentials(t *testing.T) {
+	origOpen, origCreds := openSecretsStore, readClientCredentials
+	t.Cleanup(func() { openSecretsStore, readClientCredentials = origOpen, origCreds })
+	openSecretsStore = func() (secrets.Store, error) { return nil, errBoom }
+
+	t.Setenv(envReplay, t.TempDir())
+	if _, err := optionsForAccountScopes(context.Background(), "gmail", "a@b.com", []string{"s1"}); err != nil {
+		t.Fatalf("replay options: %v", err)
+	}
+}
+
+func TestRecordTransport_SharedSequence(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"path":"`+r.URL.Path+`"}`)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	// Written by another process: the next cassette must not replace it.
+	if err := os.WriteFile(filepath.Join(dir, "0002-get.json"), []byte("{}"), 0o600); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	gmailRec := &RecordTransport{Base: http.DefaultTransport, Dir: dir}
+	driveRec := &RecordTransport{Base: http.DefaultTransport, Dir: dir}
+	for _, rt := range []*RecordTransport{gmailRec, driveRec, gmailRec} {
+		req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL+"/x", nil)
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("record: %v", err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	names := make([]string, 0, len(files))
+	for _, f := range files {
+		names = append(names, filepath.Base(f))
+	}
+	if strings.Join(names, ",") != "0002-get.json,0003-get.json,0004-get.json,0005-get.json" {
+		t.Fatalf("unexpected cassettes: %v", names)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "0002-get.json")); string(data) != "{}" {
+		t.Fatalf("existing cassette overwritten: %s", data)
+	}
+}
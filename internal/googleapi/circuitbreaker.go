@@ -1,60 +1,129 @@
 package googleapi
 
 import (
+	"encoding/json"
 	"log/slog"
+	"os"
+	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/steipete/gogcli/internal/config"
 )
 
 const (
 	// CircuitBreakerThreshold is the number of consecutive failures to open the circuit
 	CircuitBreakerThreshold = 5
-	// CircuitBreakerResetTime is how long to wait before attempting to close the circuit
+	// CircuitBreakerResetTime is how long to wait before letting a probe request through
 	CircuitBreakerResetTime = 30 * time.Second
 	circuitStateOpen        = "open"
+	circuitStateHalfOpen    = "half-open"
 	circuitStateClosed      = "closed"
 )
 
+// CircuitBreaker opens after CircuitBreakerThreshold consecutive failures.
+// Once CircuitBreakerResetTime has passed it goes half-open and lets a single
+// probe through: success closes it, failure opens it again.
 type CircuitBreaker struct {
+	Service     string
 	mu          sync.Mutex
 	failures    int
 	lastFailure time.Time
 	open        bool
+	halfOpen    bool
+	probeAt     time.Time
+	now         func() time.Time
+	onChange    func()
 }
 
 func NewCircuitBreaker() *CircuitBreaker {
 	return &CircuitBreaker{}
 }
 
+func (cb *CircuitBreaker) clock() time.Time {
+	if cb.now != nil {
+		return cb.now()
+	}
+
+	return time.Now()
+}
+
+func (cb *CircuitBreaker) changed() {
+	if cb.onChange != nil {
+		cb.onChange()
+	}
+}
+
 func (cb *CircuitBreaker) RecordSuccess() {
 	cb.mu.Lock()
-	defer cb.mu.Unlock()
-	wasOpen := cb.open
+	wasOpen := cb.open || cb.halfOpen
+	dirty := wasOpen || cb.failures > 0
 	cb.failures = 0
 	cb.open = false
+	cb.halfOpen = false
+	cb.mu.Unlock()
 
 	if wasOpen {
-		slog.Info("circuit breaker reset")
+		slog.Info("circuit breaker reset", "service", cb.Service)
+	}
+	if dirty {
+		cb.changed()
 	}
 }
 
 func (cb *CircuitBreaker) RecordFailure() bool {
 	cb.mu.Lock()
-	defer cb.mu.Unlock()
-
 	cb.failures++
-	cb.lastFailure = time.Now()
+	cb.lastFailure = cb.clock()
 
-	if cb.failures >= CircuitBreakerThreshold {
+	opened := false
+	switch {
+	case cb.halfOpen:
+		cb.halfOpen = false
+		cb.open = true
+		opened = true
+		slog.Warn("circuit breaker probe failed, reopening", "service", cb.Service, "failures", cb.failures)
+	case !cb.open && cb.failures >= CircuitBreakerThreshold:
 		cb.open = true
-		slog.Warn("circuit breaker opened", "failures", cb.failures)
+		opened = true
+		slog.Warn("circuit breaker opened", "service", cb.Service, "failures", cb.failures)
+	}
+	cb.mu.Unlock()
+
+	cb.changed()
 
-		return true // circuit just opened
+	return opened // circuit just opened
+}
+
+// Allow reports whether a request may go out. When the reset time has passed
+// the breaker turns half-open and admits one probe; other callers are turned
+// away until the probe finishes. The duration is the time until retry.
+func (cb *CircuitBreaker) Allow() (time.Duration, bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	now := cb.clock()
+	if cb.open {
+		if wait := cb.lastFailure.Add(CircuitBreakerResetTime).Sub(now); wait > 0 {
+			return wait, false
+		}
+		cb.open = false
+		cb.halfOpen = true
+		cb.probeAt = time.Time{}
+		slog.Info("circuit breaker half-open, sending probe", "service", cb.Service)
+	}
+	if cb.halfOpen {
+		// A probe that never reported back (e.g. cancelled) expires.
+		if !cb.probeAt.IsZero() && now.Sub(cb.probeAt) < CircuitBreakerResetTime {
+			return cb.probeAt.Add(CircuitBreakerResetTime).Sub(now), false
+		}
+		cb.probeAt = now
 	}
 
-	return false
+	return 0, true
 }
 
+// IsOpen reports whether requests are currently rejected without probing.
 func (cb *CircuitBreaker) IsOpen() bool {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
@@ -62,12 +131,12 @@ func (cb *CircuitBreaker) IsOpen() bool {
 	if !cb.open {
 		return false
 	}
-	// Check if reset time has passed
-	if time.Since(cb.lastFailure) > CircuitBreakerResetTime {
+	if cb.clock().Sub(cb.lastFailure) > CircuitBreakerResetTime {
 		cb.open = false
-		cb.failures = 0
+		cb.halfOpen = true
+		cb.probeAt = time.Time{}
 
-		slog.Info("circuit breaker attempting reset after timeout")
+		slog.Info("circuit breaker half-open after timeout", "service", cb.Service)
 
 		return false
 	}
@@ -79,9 +148,165 @@ func (cb *CircuitBreaker) State() string {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
 
-	if cb.open {
+	switch {
+	case cb.open:
 		return circuitStateOpen
+	case cb.halfOpen:
+		return circuitStateHalfOpen
 	}
 
 	return circuitStateClosed
 }
+
+type breakerState struct {
+	State       string    `json:"state"`
+	Failures    int       `json:"failures"`
+	LastFailure time.Time `json:"lastFailure"`
+}
+
+func (cb *CircuitBreaker) snapshot() breakerState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	s := breakerState{State: circuitStateClosed, Failures: cb.failures, LastFailure: cb.lastFailure}
+	switch {
+	case cb.open:
+		s.State = circuitStateOpen
+	case cb.halfOpen:
+		s.State = circuitStateHalfOpen
+	}
+
+	return s
+}
+
+// CircuitBreakers holds one breaker per service, so an outage of one API
+// does not block calls to another. With Path set, failure counts and open
+// state are saved there and restored by the next invocation for up to
+// CircuitBreakerResetTime, so tight shell loops back off too.
+type CircuitBreakers struct {
+	Path string
+
+	mu       sync.Mutex
+	saveMu   sync.Mutex
+	breakers map[string]*CircuitBreaker
+	loaded   map[string]breakerState
+	now      func() time.Time
+}
+
+func NewCircuitBreakers(path string) *CircuitBreakers {
+	return &CircuitBreakers{Path: path}
+}
+
+var (
+	sharedBreakersOnce sync.Once
+	sharedBreakers     *CircuitBreakers
+)
+
+func sharedCircuitBreakers() *CircuitBreakers {
+	sharedBreakersOnce.Do(func() {
+		path := ""
+		if dir, err := config.Dir(); err == nil {
+			path = filepath.Join(dir, "state", "circuit_breakers.json")
+		}
+		sharedBreakers = NewCircuitBreakers(path)
+	})
+
+	return sharedBreakers
+}
+
+func (s *CircuitBreakers) clock() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+
+	return time.Now()
+}
+
+// For returns the breaker for service, creating it from saved state.
+func (s *CircuitBreakers) For(service string) *CircuitBreaker {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.breakers == nil {
+		s.breakers = map[string]*CircuitBreaker{}
+		s.loaded = s.load()
+	}
+	if cb, ok := s.breakers[service]; ok {
+		return cb
+	}
+
+	cb := &CircuitBreaker{Service: service, now: s.now}
+	if st, ok := s.loaded[service]; ok && s.clock().Sub(st.LastFailure) < CircuitBreakerResetTime {
+		cb.failures = st.Failures
+		cb.lastFailure = st.LastFailure
+		cb.open = st.State == circuitStateOpen || st.State == circuitStateHalfOpen
+	}
+	cb.onChange = s.save
+	s.breakers[service] = cb
+
+	return cb
+}
+
+func (s *CircuitBreakers) load() map[string]breakerState {
+	if s.Path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		return nil
+	}
+	var states map[string]breakerState
+	if err := json.Unmarshal(data, &states); err != nil {
+		slog.Debug("ignoring unreadable circuit breaker state", "path", s.Path, "err", err)
+		return nil
+	}
+
+	return states
+}
+
+func (s *CircuitBreakers) save() {
+	if s.Path == "" {
+		return
+	}
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	s.mu.Lock()
+	now := s.clock()
+	states := map[string]breakerState{}
+	for service, st := range s.loaded {
+		if now.Sub(st.LastFailure) < CircuitBreakerResetTime {
+			states[service] = st
+		}
+	}
+	breakers := make(map[string]*CircuitBreaker, len(s.breakers))
+	for service, cb := range s.breakers {
+		breakers[service] = cb
+	}
+	s.mu.Unlock()
+
+	for service, cb := range breakers {
+		if st := cb.snapshot(); st.Failures > 0 || st.State != circuitStateClosed {
+			states[service] = st
+		} else {
+			delete(states, service)
+		}
+	}
+
+	data, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o700); err != nil {
+		slog.Debug("save circuit breaker state", "err", err)
+		return
+	}
+	tmp := s.Path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o600); err != nil {
+		slog.Debug("save circuit breaker state", "err", err)
+		return
+	}
+	if err := os.Rename(tmp, s.Path); err != nil {
+		slog.Debug("save circuit breaker state", "err", err)
+	}
+}
@@ -1,6 +1,11 @@
 package googleapi
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -28,15 +33,87 @@ func TestCircuitBreakerRecordFailureAndReset(t *testing.T) {
 
 	cb.lastFailure = time.Now().Add(-CircuitBreakerResetTime - time.Second)
 	if cb.IsOpen() {
-		t.Fatalf("expected reset after timeout")
+		t.Fatalf("expected half-open after timeout")
 	}
 
+	if cb.State() != circuitStateHalfOpen {
+		t.Fatalf("expected half-open state")
+	}
+
+	// The probe fails: straight back to open.
+	if opened := cb.RecordFailure(); !opened {
+		t.Fatalf("expected failed probe to reopen")
+	}
+
+	if cb.State() != circuitStateOpen {
+		t.Fatalf("expected open state")
+	}
+}
+
+func TestCircuitBreakerHalfOpenAdmitsSingleProbe(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cb := &CircuitBreaker{now: func() time.Time { return now }}
+	for i := 0; i < CircuitBreakerThreshold; i++ {
+		cb.RecordFailure()
+	}
+
+	if wait, ok := cb.Allow(); ok || wait != CircuitBreakerResetTime {
+		t.Fatalf("expected rejection with full wait, got %v %v", wait, ok)
+	}
+
+	now = now.Add(CircuitBreakerResetTime)
+	if _, ok := cb.Allow(); !ok {
+		t.Fatalf("expected probe to be admitted")
+	}
+	if _, ok := cb.Allow(); ok {
+		t.Fatalf("expected second caller to wait for the probe")
+	}
+
+	cb.RecordSuccess()
 	if cb.State() != circuitStateClosed {
-		t.Fatalf("expected closed state")
+		t.Fatalf("expected closed after successful probe")
+	}
+	if _, ok := cb.Allow(); !ok {
+		t.Fatalf("expected closed breaker to allow")
 	}
+}
 
-	if cb.failures != 0 {
-		t.Fatalf("expected failures reset")
+func TestCircuitBreakersPerServiceAndPersisted(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "state", "circuit_breakers.json")
+
+	set := NewCircuitBreakers(path)
+	set.now = func() time.Time { return now }
+	drive := set.For("drive")
+	for i := 0; i < CircuitBreakerThreshold; i++ {
+		drive.RecordFailure()
+	}
+	set.For("gmail").RecordFailure()
+
+	if set.For("drive") != drive || drive.State() != circuitStateOpen {
+		t.Fatalf("expected drive open")
+	}
+	if _, ok := set.For("gmail").Allow(); !ok {
+		t.Fatalf("drive outage must not block gmail")
+	}
+
+	// A new invocation a few seconds later sees the open breaker.
+	now = now.Add(5 * time.Second)
+	next := NewCircuitBreakers(path)
+	next.now = func() time.Time { return now }
+	if wait, ok := next.For("drive").Allow(); ok || wait != CircuitBreakerResetTime-5*time.Second {
+		t.Fatalf("expected persisted open state, got %v %v", wait, ok)
+	}
+	if next.For("gmail").State() != circuitStateClosed {
+		t.Fatalf("gmail should stay closed")
+	}
+
+	// After the reset window saved state is ignored.
+	now = now.Add(CircuitBreakerResetTime)
+	later := NewCircuitBreakers(path)
+	later.now = func() time.Time { return now }
+	if later.For("drive").State() != circuitStateClosed {
+		t.Fatalf("expected stale state to be dropped")
 	}
 }
 
@@ -55,3 +132,42 @@ func TestCircuitBreakerRecordSuccessResets(t *testing.T) {
 		t.Fatalf("expected failures reset")
 	}
 }
+
+func TestRetryTransport_BreakersPerService(t *testing.T) {
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.Contains(req.URL.Path, "/drive/") {
+			return newTestResponse(http.StatusServiceUnavailable, "down"), nil
+		}
+		return newTestResponse(http.StatusOK, "ok"), nil
+	})
+	rt := &RetryTransport{Base: base, Breakers: NewCircuitBreakers("")}
+
+	get := func(url string) error {
+		req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+		resp, err := rt.RoundTrip(req)
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+		return err
+	}
+
+	for i := 0; i < CircuitBreakerThreshold-1; i++ {
+		if err := get("https://www.googleapis.com/drive/v3/files"); err != nil {
+			t.Fatalf("attempt %d: %v", i, err)
+		}
+	}
+
+	// The failure that trips the breaker already reports it.
+	err := get("https://www.googleapis.com/drive/v3/files")
+	var cbErr *CircuitBreakerError
+	if !errors.As(err, &cbErr) || cbErr.Service != "drive" || cbErr.RetryAfter <= 0 {
+		t.Fatalf("expected drive breaker error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "retry in") {
+		t.Fatalf("expected retry hint, got %q", err.Error())
+	}
+
+	if err := get("https://gmail.googleapis.com/gmail/v1/users/me/profile"); err != nil {
+		t.Fatalf("gmail should not be blocked: %v", err)
+	}
+}
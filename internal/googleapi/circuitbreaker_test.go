@@ -26,14 +26,14 @@ func TestCircuitBreaker_OpenAndReset(t *testing.T) {
 		t.Fatalf("expected open state")
 	}
 
-	// Force timeout-based reset path.
+	// Force timeout-based half-open path.
 	cb.lastFailure = time.Now().Add(-(CircuitBreakerResetTime + time.Second))
 	if cb.IsOpen() {
-		t.Fatalf("expected closed after timeout")
+		t.Fatalf("expected probe allowed after timeout")
 	}
 
-	if cb.State() != "closed" {
-		t.Fatalf("expected closed after timeout")
+	if cb.State() != "half-open" {
+		t.Fatalf("expected half-open after timeout, got %q", cb.State())
 	}
 
 	// Explicit success reset path.
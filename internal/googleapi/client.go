@@ -80,6 +80,14 @@ func tokenSourceForAccountScopes(ctx context.Context, serviceLabel string, email
 	// Ensure refresh-token exchanges don't hang forever.
 	ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Timeout: defaultHTTPTimeout})
 
+	refresh := func() (*oauth2.Token, error) {
+		return cfg.TokenSource(ctx, &oauth2.Token{RefreshToken: tok.RefreshToken}).Token()
+	}
+	keys, _ := store.(secretStore)
+	if cached, ok := newCachedTokenSource(client, email, tok.RefreshToken, requiredScopes, keys, refresh); ok {
+		return cached, nil
+	}
+
 	return cfg.TokenSource(ctx, &oauth2.Token{RefreshToken: tok.RefreshToken}), nil
 }
 
@@ -93,8 +101,90 @@ func optionsForAccount(ctx context.Context, service googleauth.Service, email st
 }
 
 func optionsForAccountScopes(ctx context.Context, serviceLabel string, email string, scopes []string) ([]option.ClientOption, error) {
+	c, err := httpClientForAccountScopes(ctx, serviceLabel, email, scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	return []option.ClientOption{option.WithHTTPClient(c)}, nil
+}
+
+// NewHTTPClient returns an authorized client for raw REST calls to service.
+// It shares the transport chain of the typed API clients.
+func NewHTTPClient(ctx context.Context, service googleauth.Service, email string) (*http.Client, error) {
+	scopes, err := googleauth.Scopes(service)
+	if err != nil {
+		return nil, fmt.Errorf("resolve scopes: %w", err)
+	}
+
+	c, err := httpClientForAccountScopes(ctx, string(service), email, scopes)
+	if err != nil {
+		return nil, fmt.Errorf("%s client: %w", service, err)
+	}
+
+	return c, nil
+}
+
+// httpClientForAccountScopes returns the authorized client for email,
+// shared through the ClientCache of ctx when there is one.
+func httpClientForAccountScopes(ctx context.Context, serviceLabel string, email string, scopes []string) (*http.Client, error) {
+	return clientCacheFromContext(ctx).client(ctx, serviceLabel, email, scopes, func() (*http.Client, error) {
+		return buildHTTPClient(ctx, serviceLabel, email, scopes)
+	})
+}
+
+func buildHTTPClient(ctx context.Context, serviceLabel string, email string, scopes []string) (*http.Client, error) {
 	slog.Debug("creating client options with custom scopes", "serviceLabel", serviceLabel, "email", email)
 
+	// Replay needs no credentials and never touches the network.
+	if dir := replayDir(); dir != "" {
+		slog.Debug("replaying recorded responses", "serviceLabel", serviceLabel, "dir", dir)
+		return newHTTPClient(ctx, serviceLabel, email, scopes, &ReplayTransport{Dir: dir}), nil
+	}
+
+	auth, err := sessionFromContext(ctx).authTransport(ctx, email, scopes, func() (http.RoundTripper, error) {
+		ts, err := accountTokenSource(ctx, serviceLabel, email, scopes)
+		if err != nil {
+			return nil, err
+		}
+		return authTransport(ts), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	c := newHTTPClient(ctx, serviceLabel, email, scopes, wrapRecordTransport(auth))
+
+	slog.Debug("client options with custom scopes created successfully", "serviceLabel", serviceLabel, "email", email)
+
+	return c, nil
+}
+
+// AccessToken mints a short-lived access token for email. OAuth refreshes
+// return every scope the account granted; service accounts are asked for
+// the scopes of all user services. The refresh token never leaves gog.
+func AccessToken(ctx context.Context, email string) (*oauth2.Token, error) {
+	scopes, err := googleauth.ScopesForServices(googleauth.UserServices())
+	if err != nil {
+		return nil, fmt.Errorf("resolve scopes: %w", err)
+	}
+
+	ts, err := accountTokenSource(ctx, googleauth.UserServiceCSV(), email, scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	tok, err := ts.Token()
+	if err != nil {
+		return nil, fmt.Errorf("access token: %w", err)
+	}
+
+	return tok, nil
+}
+
+// accountTokenSource prefers a configured service account key, then
+// Application Default Credentials set up with `gog auth adc`, and falls back
+// to the stored OAuth refresh token for email.
+func accountTokenSource(ctx context.Context, serviceLabel string, email string, scopes []string) (oauth2.TokenSource, error) {
 	var creds config.ClientCredentials
 
 	var ts oauth2.TokenSource
@@ -104,6 +194,11 @@ func optionsForAccountScopes(ctx context.Context, serviceLabel string, email str
 	} else if ok {
 		slog.Debug("using service account credentials", "email", email, "path", saPath)
 		ts = serviceAccountTS
+	} else if adcTS, ok, err := tokenSourceForADC(ctx, email, scopes); err != nil {
+		return nil, fmt.Errorf("adc token source: %w", err)
+	} else if ok {
+		slog.Debug("using application default credentials", "email", email)
+		ts = adcTS
 	} else {
 		client, err := authclient.ResolveClient(ctx, email)
 		if err != nil {
@@ -122,22 +217,48 @@ func optionsForAccountScopes(ctx context.Context, serviceLabel string, email str
 			ts = tokenSource
 		}
 	}
-	baseTransport := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			MinVersion: tls.VersionTLS12,
+
+	return ts, nil
+}
+
+// authTransport signs requests with ts over a TLS 1.2+ transport. Cached
+// access tokens are dropped and refreshed when a request comes back 401.
+func authTransport(ts oauth2.TokenSource) http.RoundTripper {
+	rt := &oauth2.Transport{
+		Source: ts,
+		Base: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				MinVersion: tls.VersionTLS12,
+			},
 		},
 	}
-	// Wrap with retry logic for 429 and 5xx errors
-	retryTransport := NewRetryTransport(&oauth2.Transport{
-		Source: ts,
-		Base:   baseTransport,
-	})
-	c := &http.Client{
-		Transport: retryTransport,
-		Timeout:   defaultHTTPTimeout,
+
+	if cached, ok := ts.(*cachedTokenSource); ok {
+		return &tokenCacheTransport{base: rt, src: cached}
 	}
 
-	slog.Debug("client options with custom scopes created successfully", "serviceLabel", serviceLabel, "email", email)
+	return rt
+}
 
-	return []option.ClientOption{option.WithHTTPClient(c)}, nil
+// newHTTPClient wraps transport in the shared layers, outermost first:
+// read-only enforcement, dry-run, audit log, response cache,
+// insufficient-scope tracking, then the rate limiter and retry logic for 429
+// and 5xx errors. GOG_RECORD belongs between retry and auth so each attempt
+// is captured but bearer tokens never reach the cassette.
+func newHTTPClient(ctx context.Context, serviceLabel string, email string, scopes []string, transport http.RoundTripper) *http.Client {
+	retry := NewRetryTransport(transport)
+	retry.Breakers = sharedCircuitBreakers()
+	retry.Limiter = sharedRateLimiter()
+	retry.Account = email
+
+	rt := wrapScopeTransport(ctx, serviceLabel, email, scopes, retry)
+	rt = wrapCacheTransport(ctx, email, scopes, rt)
+	rt = wrapAuditTransport(ctx, serviceLabel, email, rt)
+	rt = wrapDryRunTransport(ctx, rt)
+	rt = wrapReadOnlyTransport(ctx, rt)
+
+	return &http.Client{
+		Transport: rt,
+		Timeout:   defaultHTTPTimeout,
+	}
 }
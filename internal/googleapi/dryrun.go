@@ -0,0 +1,158 @@
+package googleapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// DryRun configures DryRunTransport. Out receives one entry per intercepted
+// call; JSON switches entries from human-readable text to NDJSON.
+type DryRun struct {
+	Out  io.Writer
+	JSON bool
+}
+
+type dryRunKey struct{}
+
+func WithDryRun(ctx context.Context, d DryRun) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, d)
+}
+
+func DryRunFromContext(ctx context.Context) (DryRun, bool) {
+	if ctx == nil {
+		return DryRun{}, false
+	}
+	d, ok := ctx.Value(dryRunKey{}).(DryRun)
+	return d, ok && d.Out != nil
+}
+
+// DryRunTransport lets reads through and stops every mutating request
+// (anything but GET/HEAD/OPTIONS), printing it and answering with a
+// synthetic 200 that echoes the JSON request body.
+type DryRunTransport struct {
+	Base http.RoundTripper
+	DryRun
+
+	mu sync.Mutex
+}
+
+type dryRunCall struct {
+	DryRun bool   `json:"dryRun"`
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   any    `json:"body,omitempty"`
+}
+
+func wrapDryRunTransport(ctx context.Context, base http.RoundTripper) http.RoundTripper {
+	d, ok := DryRunFromContext(ctx)
+	if !ok {
+		return base
+	}
+	return &DryRunTransport{Base: base, DryRun: d}
+}
+
+func (t *DryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if isReadRequest(req) {
+		return t.Base.RoundTrip(req)
+	}
+
+	var raw []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		b, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read request body: %w", err)
+		}
+		raw = b
+	}
+	body := dryRunBody(raw, req.Header.Get("Content-Type"))
+
+	if err := t.print(dryRunCall{DryRun: true, Method: req.Method, URL: cassetteURL(req.URL), Body: body}); err != nil {
+		return nil, err
+	}
+
+	echo := []byte("{}")
+	if obj, ok := body.(map[string]any); ok {
+		if b, err := json.Marshal(obj); err == nil {
+			echo = b
+		}
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(echo)),
+		ContentLength: int64(len(echo)),
+		Request:       req,
+	}, nil
+}
+
+func (t *DryRunTransport) print(call dryRunCall) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.JSON {
+		b, err := json.Marshal(call)
+		if err != nil {
+			return fmt.Errorf("encode dry-run call: %w", err)
+		}
+		_, err = fmt.Fprintf(t.Out, "%s\n", b)
+		return err
+	}
+
+	if _, err := fmt.Fprintf(t.Out, "dry-run: %s %s\n", call.Method, call.URL); err != nil {
+		return err
+	}
+	switch b := call.Body.(type) {
+	case nil:
+		return nil
+	case string:
+		_, err := fmt.Fprintln(t.Out, b)
+		return err
+	default:
+		pretty, err := json.MarshalIndent(b, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode dry-run body: %w", err)
+		}
+		_, err = fmt.Fprintf(t.Out, "%s\n", pretty)
+		return err
+	}
+}
+
+// dryRunBody decodes JSON bodies (and the JSON metadata part of multipart
+// uploads); anything else is summarized by size and type.
+func dryRunBody(raw []byte, contentType string) any {
+	if len(raw) == 0 {
+		return nil
+	}
+	mediaType, params, _ := mime.ParseMediaType(contentType)
+	if strings.HasPrefix(mediaType, "multipart/") && params["boundary"] != "" {
+		r := multipart.NewReader(bytes.NewReader(raw), params["boundary"])
+		if part, err := r.NextPart(); err == nil {
+			if b, err := io.ReadAll(part); err == nil {
+				var v any
+				if json.Unmarshal(b, &v) == nil {
+					return v
+				}
+			}
+		}
+	}
+	if strings.Contains(mediaType, "json") || mediaType == "" {
+		var v any
+		if json.Unmarshal(raw, &v) == nil {
+			return v
+		}
+	}
+	return fmt.Sprintf("<%d bytes %s>", len(raw), mediaType)
+}
@@ -0,0 +1,124 @@
+package googleapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+)
+
+func TestDryRunTransport_InterceptsWrites(t *testing.T) {
+	var methods []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		_, _ = io.WriteString(w, `{"id":"f1"}`)
+	}))
+	defer srv.Close()
+
+	var out bytes.Buffer
+	rt := &DryRunTransport{Base: http.DefaultTransport, DryRun: DryRun{Out: &out}}
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL+"/files/f1", nil)
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	resp.Body.Close()
+
+	req, _ = http.NewRequestWithContext(context.Background(), http.MethodPatch, srv.URL+"/files/f1?access_token=secret", strings.NewReader(`{"name":"New"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("patch: %v", err)
+	}
+	echo, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if strings.Join(methods, ",") != "GET" {
+		t.Fatalf("only GET should reach the server, got %v", methods)
+	}
+	if resp.StatusCode != 200 || string(echo) != `{"name":"New"}` {
+		t.Fatalf("unexpected synthetic response %d %q", resp.StatusCode, echo)
+	}
+	got := out.String()
+	if !strings.Contains(got, "dry-run: PATCH "+srv.URL+"/files/f1\n") || !strings.Contains(got, `  "name": "New"`) {
+		t.Fatalf("unexpected dry-run output: %q", got)
+	}
+	if strings.Contains(got, "secret") {
+		t.Fatalf("dry-run output leaks token: %q", got)
+	}
+}
+
+func TestDryRunTransport_PassesQueries(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		_, _ = io.WriteString(w, `{"calendars":{}}`)
+	}))
+	defer srv.Close()
+
+	var out bytes.Buffer
+	rt := &DryRunTransport{Base: http.DefaultTransport, DryRun: DryRun{Out: &out}}
+
+	for _, path := range []string{"/calendar/v3/freeBusy", "/v4/spreadsheets/s1/values:batchGetByDataFilter"} {
+		req, _ := http.NewRequestWithContext(context.Background(), http.MethodPost, srv.URL+path, strings.NewReader(`{}`))
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("post %s: %v", path, err)
+		}
+		resp.Body.Close()
+	}
+
+	if hits != 2 || out.Len() != 0 {
+		t.Fatalf("queries should reach the server, hits=%d out=%q", hits, out.String())
+	}
+}
+
+func TestNewHTTPClient_DryRunJSON(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	var out bytes.Buffer
+	ctx := WithDryRun(context.Background(), DryRun{Out: &out, JSON: true})
+	c := newHTTPClient(ctx, "drive", "a@b.com", nil, http.DefaultTransport)
+	svc, err := drive.NewService(ctx, option.WithHTTPClient(c), option.WithEndpoint(srv.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	if err := svc.Files.Delete("f1").Context(ctx).Do(); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if hits != 0 {
+		t.Fatalf("delete reached the server")
+	}
+
+	var call map[string]any
+	if err := json.Unmarshal(out.Bytes(), &call); err != nil {
+		t.Fatalf("decode %q: %v", out.String(), err)
+	}
+	if call["dryRun"] != true || call["method"] != "DELETE" || !strings.Contains(call["url"].(string), "/files/f1?") {
+		t.Fatalf("unexpected call: %#v", call)
+	}
+}
+
+func TestDryRunBody_Multipart(t *testing.T) {
+	body := "--b\r\nContent-Type: application/json\r\n\r\n{\"name\":\"report.pdf\"}\r\n--b\r\nContent-Type: application/pdf\r\n\r\n%PDF\r\n--b--\r\n"
+	got := dryRunBody([]byte(body), "multipart/related; boundary=b")
+	if m, ok := got.(map[string]any); !ok || m["name"] != "report.pdf" {
+		t.Fatalf("unexpected multipart body: %#v", got)
+	}
+	if got := dryRunBody([]byte{0x1, 0x2}, "application/octet-stream"); got != "<2 bytes application/octet-stream>" {
+		t.Fatalf("unexpected binary summary: %#v", got)
+	}
+}
@@ -3,6 +3,7 @@ package googleapi
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -25,6 +26,37 @@ func (e *AuthRequiredError) Unwrap() error {
 	return e.Cause
 }
 
+// InsufficientScopesError reports an API call rejected because the stored
+// OAuth token was granted fewer scopes than Services need. Granting them
+// incrementally keeps the scopes the account already has.
+type InsufficientScopesError struct {
+	Email    string
+	Client   string
+	Services []string
+	// Partial is set when the command changed data before it failed, so
+	// it must not be re-run without the user checking first.
+	Partial bool
+	Cause   error
+}
+
+func (e *InsufficientScopesError) Error() string {
+	return fmt.Sprintf("missing OAuth scopes for %s on %s", strings.Join(e.Services, ","), e.Email)
+}
+
+func (e *InsufficientScopesError) Unwrap() error {
+	return e.Cause
+}
+
+// Command is the gog invocation that grants the missing scopes.
+func (e *InsufficientScopesError) Command() string {
+	cmd := "gog "
+	if e.Client != "" && e.Client != "default" {
+		cmd += "--client " + e.Client + " "
+	}
+
+	return cmd + "auth add " + e.Email + " --services " + strings.Join(e.Services, ",") + " --incremental"
+}
+
 // RateLimitError indicates rate limit was exceeded
 type RateLimitError struct {
 	RetryAfter time.Duration
@@ -40,10 +72,21 @@ func (e *RateLimitError) Error() string {
 }
 
 // CircuitBreakerError indicates the circuit breaker is open
-type CircuitBreakerError struct{}
+type CircuitBreakerError struct {
+	Service    string
+	RetryAfter time.Duration
+}
 
 func (e *CircuitBreakerError) Error() string {
-	return "circuit breaker is open, too many recent failures - try again later"
+	subject := "circuit breaker is open"
+	if e.Service != "" {
+		subject = fmt.Sprintf("circuit breaker for %s is open", e.Service)
+	}
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%s, too many recent failures - retry in %s", subject, e.RetryAfter.Round(time.Second))
+	}
+
+	return subject + ", too many recent failures - try again later"
 }
 
 // QuotaExceededError indicates API quota was exceeded
@@ -122,3 +165,18 @@ func IsPermissionDeniedError(err error) bool {
 	var e *PermissionDeniedError
 	return errors.As(err, &e)
 }
+
+// ReadOnlyError indicates a command or request was blocked by read-only mode
+type ReadOnlyError struct {
+	Command string
+	Method  string
+	URL     string
+}
+
+func (e *ReadOnlyError) Error() string {
+	if e.Command != "" {
+		return fmt.Sprintf("%q is blocked in read-only mode", e.Command)
+	}
+
+	return fmt.Sprintf("read-only mode: blocked %s %s", e.Method, e.URL)
+}
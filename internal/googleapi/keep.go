@@ -23,6 +23,10 @@ func NewKeep(ctx context.Context, email string) (*keep.Service, error) {
 }
 
 func NewKeepWithServiceAccount(ctx context.Context, serviceAccountPath, impersonateEmail string) (*keep.Service, error) {
+	if replayDir() != "" {
+		return NewKeep(ctx, impersonateEmail)
+	}
+
 	data, err := os.ReadFile(serviceAccountPath) //nolint:gosec // user-provided path (or stored config file)
 	if err != nil {
 		return nil, fmt.Errorf("read service account file: %w", err)
@@ -40,7 +44,8 @@ func NewKeepWithServiceAccount(ctx context.Context, serviceAccountPath, imperson
 
 	config.Subject = impersonateEmail
 
-	svc, err := keep.NewService(ctx, option.WithTokenSource(config.TokenSource(ctx)))
+	c := newHTTPClient(ctx, string(googleauth.ServiceKeep), impersonateEmail, scopes, wrapRecordTransport(authTransport(config.TokenSource(ctx))))
+	svc, err := keep.NewService(ctx, option.WithHTTPClient(c))
 	if err != nil {
 		return nil, fmt.Errorf("create keep service: %w", err)
 	}
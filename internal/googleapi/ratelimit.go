@@ -0,0 +1,254 @@
+package googleapi
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+)
+
+// RateLimit is a token bucket refilled at Units per Per, holding at most
+// one window's worth of units.
+type RateLimit struct {
+	Units float64
+	Per   time.Duration
+}
+
+// DefaultRateLimits are the published per-user quotas, keyed by the service
+// name derived from the request host (see rateLimitService). Gmail is
+// measured in quota units, everything else in requests.
+var DefaultRateLimits = map[string]RateLimit{
+	"gmail":         {Units: 250, Per: time.Second},
+	"drive":         {Units: 12000, Per: time.Minute},
+	"calendar":      {Units: 600, Per: time.Minute},
+	"sheets":        {Units: 60, Per: time.Minute},
+	"docs":          {Units: 300, Per: time.Minute},
+	"slides":        {Units: 600, Per: time.Minute},
+	"people":        {Units: 90, Per: time.Minute},
+	"tasks":         {Units: 600, Per: time.Minute},
+	"cloudidentity": {Units: 600, Per: time.Minute},
+	"classroom":     {Units: 1200, Per: time.Minute},
+	"chat":          {Units: 600, Per: time.Minute},
+}
+
+type rateBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// RateLimiter throttles requests per service and account. One limiter is
+// shared by every client in the process so concurrent fetchers draw from
+// the same buckets.
+type RateLimiter struct {
+	mu      sync.Mutex
+	limits  map[string]RateLimit
+	buckets map[string]*rateBucket
+	now     func() time.Time
+	sleep   func(context.Context, time.Duration) error
+}
+
+func NewRateLimiter(limits map[string]RateLimit) *RateLimiter {
+	return &RateLimiter{
+		limits:  limits,
+		buckets: map[string]*rateBucket{},
+		now:     time.Now,
+		sleep:   sleepContext,
+	}
+}
+
+var (
+	sharedLimiterOnce sync.Once
+	sharedLimiter     *RateLimiter
+)
+
+// sharedRateLimiter returns the process-wide limiter: DefaultRateLimits
+// with the rate_limits config overrides applied.
+func sharedRateLimiter() *RateLimiter {
+	sharedLimiterOnce.Do(func() {
+		limits := make(map[string]RateLimit, len(DefaultRateLimits))
+		for service, limit := range DefaultRateLimits {
+			limits[service] = limit
+		}
+		if cfg, err := config.ReadConfig(); err == nil {
+			for service, raw := range cfg.RateLimits {
+				units, per, err := config.ParseRateLimit(raw)
+				if err != nil {
+					slog.Warn("ignoring invalid rate limit", "service", service, "err", err)
+					continue
+				}
+				if units == 0 {
+					delete(limits, service)
+					continue
+				}
+				limits[service] = RateLimit{Units: units, Per: per}
+			}
+		}
+		sharedLimiter = NewRateLimiter(limits)
+	})
+
+	return sharedLimiter
+}
+
+// Wait blocks until cost units are available for service and account.
+// Reservations may drive the bucket negative so waiters are served in
+// arrival order.
+func (l *RateLimiter) Wait(ctx context.Context, service string, account string, cost float64) error {
+	if l == nil || service == "" {
+		return nil
+	}
+	limit, ok := l.limits[service]
+	if !ok || limit.Units <= 0 || limit.Per <= 0 {
+		return nil
+	}
+	rate := limit.Units / limit.Per.Seconds()
+	cost = min(cost, limit.Units)
+
+	l.mu.Lock()
+	b := l.bucket(service, account, limit)
+	b.tokens -= cost
+	var wait time.Duration
+	if b.tokens < 0 {
+		wait = time.Duration(-b.tokens / rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if wait > 0 {
+		slog.Debug("rate limit wait", "service", service, "delay", wait)
+	}
+
+	return l.sleep(ctx, wait)
+}
+
+// Drain empties the bucket after the server answered 429, so following
+// requests wait for a refill instead of piling onto the quota.
+func (l *RateLimiter) Drain(service string, account string) {
+	if l == nil || service == "" {
+		return
+	}
+	limit, ok := l.limits[service]
+	if !ok || limit.Units <= 0 || limit.Per <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if b := l.bucket(service, account, limit); b.tokens > 0 {
+		b.tokens = 0
+	}
+}
+
+// bucket refills and returns the bucket for service/account; l.mu must be held.
+func (l *RateLimiter) bucket(service string, account string, limit RateLimit) *rateBucket {
+	now := l.now()
+	key := service + "|" + strings.ToLower(account)
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &rateBucket{tokens: limit.Units, last: now}
+		l.buckets[key] = b
+		return b
+	}
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(limit.Units, b.tokens+elapsed.Seconds()*limit.Units/limit.Per.Seconds())
+		b.last = now
+	}
+	return b
+}
+
+// rateLimitService maps a request URL to a DefaultRateLimits key:
+// gmail.googleapis.com -> gmail, www.googleapis.com/drive/v3 -> drive.
+// Non-Google hosts are not limited.
+func rateLimitService(u *url.URL) string {
+	host := strings.ToLower(u.Hostname())
+	if host == "www.googleapis.com" {
+		for _, seg := range strings.Split(strings.Trim(u.Path, "/"), "/") {
+			if seg != "batch" && seg != "upload" && seg != "" {
+				return seg
+			}
+		}
+		return ""
+	}
+	if name, ok := strings.CutSuffix(host, ".googleapis.com"); ok && !strings.Contains(name, ".") {
+		return name
+	}
+	return ""
+}
+
+type requestCostKey struct{}
+
+// withRequestCost overrides the quota cost of a request, e.g. the summed
+// cost of a batch call's sub-requests.
+func withRequestCost(ctx context.Context, cost float64) context.Context {
+	return context.WithValue(ctx, requestCostKey{}, cost)
+}
+
+func requestCost(req *http.Request) float64 {
+	if cost, ok := req.Context().Value(requestCostKey{}).(float64); ok {
+		return cost
+	}
+	if rateLimitService(req.URL) == "gmail" {
+		return gmailQuotaUnits(req.Method, req.URL.Path)
+	}
+	return 1
+}
+
+// gmailQuotaUnits returns the published per-method quota cost of a Gmail
+// API call, e.g. 5 for messages.get and 100 for messages.send.
+func gmailQuotaUnits(method string, path string) float64 {
+	_, rest, ok := strings.Cut(path, "/users/")
+	if !ok {
+		return 5
+	}
+	parts := strings.Split(strings.Trim(rest, "/"), "/")
+	if len(parts) < 2 {
+		return 1 // users.getProfile
+	}
+	parts = parts[1:]
+	last := parts[len(parts)-1]
+
+	switch parts[0] {
+	case "messages":
+		switch {
+		case len(parts) >= 3 && parts[2] == "attachments":
+			return 5
+		case last == "send":
+			return 100
+		case last == "batchModify", last == "batchDelete":
+			return 50
+		case last == "import", method == http.MethodPost && len(parts) == 1:
+			return 25
+		case method == http.MethodDelete:
+			return 10
+		}
+		return 5
+	case "threads":
+		if method == http.MethodDelete {
+			return 20
+		}
+		return 10
+	case "drafts":
+		switch {
+		case last == "send":
+			return 100
+		case method == http.MethodPost, method == http.MethodPut, method == http.MethodDelete:
+			return 10
+		}
+		return 5
+	case "history":
+		return 2
+	case "watch":
+		return 100
+	case "stop":
+		return 50
+	case "labels", "profile", "settings":
+		if method == http.MethodGet {
+			return 1
+		}
+		return 5
+	}
+	return 5
+}
@@ -0,0 +1,127 @@
+package googleapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func testLimiter(limits map[string]RateLimit) (*RateLimiter, *time.Time, *[]time.Duration) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var waits []time.Duration
+	l := NewRateLimiter(limits)
+	l.now = func() time.Time { return now }
+	l.sleep = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		now = now.Add(d)
+		return nil
+	}
+	return l, &now, &waits
+}
+
+func TestRateLimiter_WaitsWhenBucketEmpty(t *testing.T) {
+	l, now, waits := testLimiter(map[string]RateLimit{"drive": {Units: 10, Per: time.Second}})
+	ctx := context.Background()
+
+	for i := 0; i < 10; i++ {
+		if err := l.Wait(ctx, "drive", "a@b.com", 1); err != nil {
+			t.Fatalf("Wait: %v", err)
+		}
+	}
+	if got := (*waits)[len(*waits)-1]; got != 0 {
+		t.Fatalf("burst should not wait, got %v", got)
+	}
+
+	_ = l.Wait(ctx, "drive", "a@b.com", 1)
+	if got := (*waits)[len(*waits)-1]; got != 100*time.Millisecond {
+		t.Fatalf("expected 100ms wait, got %v", got)
+	}
+
+	// Other accounts and unknown services have their own (or no) buckets.
+	_ = l.Wait(ctx, "drive", "c@d.com", 1)
+	_ = l.Wait(ctx, "unknown", "a@b.com", 1000)
+	if got := (*waits)[len(*waits)-1]; got != 0 {
+		t.Fatalf("expected no wait, got %v", got)
+	}
+
+	*now = now.Add(time.Second)
+	l.Drain("drive", "a@b.com")
+	_ = l.Wait(ctx, "drive", "a@b.com", 5)
+	if got := (*waits)[len(*waits)-1]; got != 500*time.Millisecond {
+		t.Fatalf("expected 500ms after drain, got %v", got)
+	}
+}
+
+func TestRateLimitService(t *testing.T) {
+	cases := map[string]string{
+		"https://gmail.googleapis.com/gmail/v1/users/me/messages":  "gmail",
+		"https://gmail.googleapis.com/batch/gmail/v1":              "gmail",
+		"https://www.googleapis.com/drive/v3/files/x":              "drive",
+		"https://www.googleapis.com/upload/drive/v3/files":         "drive",
+		"https://www.googleapis.com/batch/calendar/v3":             "calendar",
+		"https://cloudidentity.googleapis.com/v1/groups/x/members": "cloudidentity",
+		"http://127.0.0.1:4321/drive/v3/files":                     "",
+		"https://example.com/gmail/v1":                             "",
+	}
+	for raw, want := range cases {
+		u, _ := url.Parse(raw)
+		if got := rateLimitService(u); got != want {
+			t.Fatalf("%s: got %q want %q", raw, got, want)
+		}
+	}
+}
+
+func TestGmailQuotaUnits(t *testing.T) {
+	cases := []struct {
+		method string
+		path   string
+		want   float64
+	}{
+		{http.MethodGet, "/gmail/v1/users/me/profile", 1},
+		{http.MethodGet, "/gmail/v1/users/me/messages", 5},
+		{http.MethodGet, "/gmail/v1/users/me/messages/m1", 5},
+		{http.MethodGet, "/gmail/v1/users/me/messages/m1/attachments/a1", 5},
+		{http.MethodPost, "/gmail/v1/users/me/messages/send", 100},
+		{http.MethodPost, "/gmail/v1/users/me/messages/batchModify", 50},
+		{http.MethodPost, "/gmail/v1/users/me/messages", 25},
+		{http.MethodDelete, "/gmail/v1/users/me/messages/m1", 10},
+		{http.MethodGet, "/gmail/v1/users/me/threads/t1", 10},
+		{http.MethodDelete, "/gmail/v1/users/me/threads/t1", 20},
+		{http.MethodPost, "/gmail/v1/users/me/drafts/send", 100},
+		{http.MethodGet, "/gmail/v1/users/me/history", 2},
+		{http.MethodGet, "/gmail/v1/users/me/labels", 1},
+		{http.MethodPost, "/gmail/v1/users/me/watch", 100},
+	}
+	for _, tc := range cases {
+		if got := gmailQuotaUnits(tc.method, tc.path); got != tc.want {
+			t.Fatalf("%s %s: got %v want %v", tc.method, tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestRetryTransport_UsesLimiter(t *testing.T) {
+	l, _, waits := testLimiter(map[string]RateLimit{"gmail": {Units: 10, Per: time.Second}})
+	rt := &RetryTransport{
+		Base: roundTripFunc(func(*http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		}),
+		Limiter: l,
+		Account: "a@b.com",
+	}
+
+	for i := 0; i < 3; i++ {
+		req, _ := http.NewRequest(http.MethodGet, "https://gmail.googleapis.com/gmail/v1/users/me/threads/t1", nil)
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("RoundTrip: %v", err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	// threads.get costs 10 units, so each call after the first waits a full refill.
+	if len(*waits) != 3 || (*waits)[0] != 0 || (*waits)[1] != time.Second || (*waits)[2] != time.Second {
+		t.Fatalf("unexpected waits: %v", *waits)
+	}
+}
@@ -0,0 +1,46 @@
+package googleapi
+
+import (
+	"context"
+	"net/http"
+)
+
+type readOnlyKey struct{}
+
+// WithReadOnly makes every client built from ctx refuse mutating requests.
+func WithReadOnly(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readOnlyKey{}, true)
+}
+
+func ReadOnlyFromContext(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	v, _ := ctx.Value(readOnlyKey{}).(bool)
+	return v
+}
+
+// ReadOnlyTransport fails every mutating request with a ReadOnlyError
+// before it leaves the process. It backs up the command-level check of
+// --read-only for requests the command list cannot classify, such as
+// gog api calls.
+type ReadOnlyTransport struct {
+	Base http.RoundTripper
+}
+
+func wrapReadOnlyTransport(ctx context.Context, base http.RoundTripper) http.RoundTripper {
+	if !ReadOnlyFromContext(ctx) {
+		return base
+	}
+	return &ReadOnlyTransport{Base: base}
+}
+
+func (t *ReadOnlyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if isReadRequest(req) {
+		return t.Base.RoundTrip(req)
+	}
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	return nil, &ReadOnlyError{Method: req.Method, URL: cassetteURL(req.URL)}
+}
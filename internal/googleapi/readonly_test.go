@@ -0,0 +1,54 @@
+package googleapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+)
+
+func TestNewHTTPClient_ReadOnly(t *testing.T) {
+	var hits []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits = append(hits, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	ctx := WithReadOnly(context.Background())
+	c := newHTTPClient(ctx, "drive", "a@b.com", nil, http.DefaultTransport)
+
+	svc, err := drive.NewService(ctx, option.WithHTTPClient(c), option.WithEndpoint(srv.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	if _, err := svc.Files.Get("f1").Context(ctx).Do(); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	err = svc.Files.Delete("f1").Context(ctx).Do()
+	var roErr *ReadOnlyError
+	if !errors.As(err, &roErr) || roErr.Method != http.MethodDelete {
+		t.Fatalf("expected ReadOnlyError, got %v", err)
+	}
+
+	cal, err := calendar.NewService(ctx, option.WithHTTPClient(c), option.WithEndpoint(srv.URL+"/calendar/v3/"))
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	if _, err := cal.Freebusy.Query(&calendar.FreeBusyRequest{}).Context(ctx).Do(); err != nil {
+		t.Fatalf("freebusy: %v", err)
+	}
+	if err := cal.Events.Delete("primary", "e1").Context(ctx).Do(); !errors.As(err, &roErr) {
+		t.Fatalf("expected ReadOnlyError, got %v", err)
+	}
+
+	if len(hits) != 2 || hits[0] != "GET /files/f1" || hits[1] != "POST /calendar/v3/freeBusy" {
+		t.Fatalf("unexpected requests: %v", hits)
+	}
+}
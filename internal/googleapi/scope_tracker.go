@@ -0,0 +1,141 @@
+package googleapi
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// maxScopeErrorPeek bounds how much of a 403 body is read to look for the
+// insufficient-scope markers.
+const maxScopeErrorPeek = 64 << 10
+
+// ScopeRejection is a request that Google rejected because the access token
+// lacks scopes.
+type ScopeRejection struct {
+	Service string
+	Email   string
+	Scopes  []string
+}
+
+// ScopeTracker collects scope rejections for one command, so the command
+// layer can offer incremental re-authorization. It also notes whether any
+// mutating request succeeded, in which case re-running the command would
+// repeat that change.
+type ScopeTracker struct {
+	mu      sync.Mutex
+	hits    []ScopeRejection
+	mutated bool
+}
+
+type scopeTrackerKey struct{}
+
+func WithScopeTracker(ctx context.Context) (context.Context, *ScopeTracker) {
+	t := &ScopeTracker{}
+	return context.WithValue(ctx, scopeTrackerKey{}, t), t
+}
+
+func scopeTrackerFromContext(ctx context.Context) *ScopeTracker {
+	t, _ := ctx.Value(scopeTrackerKey{}).(*ScopeTracker)
+	return t
+}
+
+// Rejections returns the recorded rejections, oldest first.
+func (t *ScopeTracker) Rejections() []ScopeRejection {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]ScopeRejection(nil), t.hits...)
+}
+
+// Mutated reports whether a mutating request succeeded.
+func (t *ScopeTracker) Mutated() bool {
+	if t == nil {
+		return false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.mutated
+}
+
+func (t *ScopeTracker) markMutated() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.mutated = true
+}
+
+func (t *ScopeTracker) record(r ScopeRejection) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.hits = append(t.hits, r)
+}
+
+// wrapScopeTransport records insufficient-scope 403s in the context's
+// ScopeTracker; without a tracker base is returned unchanged.
+func wrapScopeTransport(ctx context.Context, serviceLabel string, email string, scopes []string, base http.RoundTripper) http.RoundTripper {
+	tracker := scopeTrackerFromContext(ctx)
+	if tracker == nil {
+		return base
+	}
+
+	return &scopeTrackingTransport{
+		base:      base,
+		tracker:   tracker,
+		rejection: ScopeRejection{Service: serviceLabel, Email: email, Scopes: scopes},
+	}
+}
+
+type scopeTrackingTransport struct {
+	base      http.RoundTripper
+	tracker   *ScopeTracker
+	rejection ScopeRejection
+}
+
+func (t *scopeTrackingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	if resp.StatusCode < http.StatusBadRequest && !isReadRequest(req) {
+		t.tracker.markMutated()
+	}
+	if resp.StatusCode != http.StatusForbidden {
+		return resp, nil
+	}
+
+	if strings.Contains(resp.Header.Get("WWW-Authenticate"), "insufficient_scope") {
+		t.tracker.record(t.rejection)
+		return resp, nil
+	}
+
+	peek, readErr := io.ReadAll(io.LimitReader(resp.Body, maxScopeErrorPeek))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(peek), resp.Body), resp.Body}
+
+	if readErr == nil && isInsufficientScopeBody(peek) {
+		t.tracker.record(t.rejection)
+	}
+
+	return resp, nil
+}
+
+// isInsufficientScopeBody reports whether a 403 error body says the access
+// token lacks scopes (as opposed to the user lacking access).
+func isInsufficientScopeBody(body []byte) bool {
+	return bytes.Contains(body, []byte("ACCESS_TOKEN_SCOPE_INSUFFICIENT")) ||
+		bytes.Contains(body, []byte(`"insufficientPermissions"`)) ||
+		bytes.Contains(body, []byte("insufficient authentication scopes"))
+}
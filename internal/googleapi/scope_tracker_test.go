@@ -0,0 +1,84 @@
+package googleapi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestScopeTrackingTransport(t *testing.T) {
+	const scopeBody = `{"error":{"code":403,"message":"Request had insufficient authentication scopes.","details":[{"reason":"ACCESS_TOKEN_SCOPE_INSUFFICIENT"}]}}`
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		if r.URL.Path == "/scope" {
+			_, _ = io.WriteString(w, scopeBody)
+			return
+		}
+		_, _ = io.WriteString(w, `{"error":{"code":403,"message":"The caller does not have permission"}}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx, tracker := WithScopeTracker(context.Background())
+	client := &http.Client{Transport: wrapScopeTransport(ctx, "drive", "a@b.com", []string{"s1"}, http.DefaultTransport)}
+
+	for _, path := range []string{"/denied", "/scope"} {
+		resp, err := client.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("get %s: %v", path, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if path == "/scope" && string(body) != scopeBody {
+			t.Fatalf("body not restored: %q", body)
+		}
+	}
+
+	got := tracker.Rejections()
+	if len(got) != 1 || got[0].Service != "drive" || got[0].Email != "a@b.com" || strings.Join(got[0].Scopes, ",") != "s1" {
+		t.Fatalf("unexpected rejections: %#v", got)
+	}
+}
+
+func TestScopeTrackingTransport_Mutated(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx, tracker := WithScopeTracker(context.Background())
+	client := &http.Client{Transport: wrapScopeTransport(ctx, "drive", "a@b.com", nil, http.DefaultTransport)}
+
+	do := func(method, path string) {
+		req, _ := http.NewRequestWithContext(context.Background(), method, srv.URL+path, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s: %v", method, path, err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	do(http.MethodGet, "/ok")
+	do(http.MethodPost, "/fail")
+	do(http.MethodPost, "/calendar/v3/freeBusy")
+	if tracker.Mutated() {
+		t.Fatalf("reads, queries and failed writes must not count as mutations")
+	}
+
+	do(http.MethodPost, "/ok")
+	if !tracker.Mutated() {
+		t.Fatalf("expected successful write to count as a mutation")
+	}
+}
+
+func TestWrapScopeTransport_NoTracker(t *testing.T) {
+	base := http.DefaultTransport
+	if got := wrapScopeTransport(context.Background(), "drive", "a@b.com", nil, base); got != base {
+		t.Fatalf("expected base transport without tracker")
+	}
+}
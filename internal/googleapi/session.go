@@ -0,0 +1,111 @@
+package googleapi
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/steipete/gogcli/internal/authclient"
+)
+
+// Session keeps authorized transports alive across the commands of one
+// process (gog run), so each account and scope set resolves credentials and
+// refreshes its access token once and reuses connections. The per-command
+// layers (dry-run, audit, cache, retry) are still built for every client.
+type Session struct {
+	mu         sync.Mutex
+	transports map[string]http.RoundTripper
+}
+
+func NewSession() *Session {
+	return &Session{transports: map[string]http.RoundTripper{}}
+}
+
+type sessionKey struct{}
+
+func WithSession(ctx context.Context, s *Session) context.Context {
+	return context.WithValue(ctx, sessionKey{}, s)
+}
+
+func sessionFromContext(ctx context.Context) *Session {
+	s, _ := ctx.Value(sessionKey{}).(*Session)
+	return s
+}
+
+// authTransport returns the cached transport for the client, account and
+// scopes of ctx, building it on first use. A nil session always builds.
+func (s *Session) authTransport(ctx context.Context, email string, scopes []string, build func() (http.RoundTripper, error)) (http.RoundTripper, error) {
+	if s == nil {
+		return build()
+	}
+
+	key := authclient.ClientOverrideFromContext(ctx) + "|" + strings.ToLower(email) + "|" + strings.Join(scopes, " ")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if rt, ok := s.transports[key]; ok {
+		return rt, nil
+	}
+	rt, err := build()
+	if err != nil {
+		return nil, err
+	}
+	s.transports[key] = rt
+
+	return rt, nil
+}
+
+// ClientCache shares the authorized client of one command between its typed
+// API service and batch client, so the keyring is opened and the access
+// token fetched once per account and scope set.
+type ClientCache struct {
+	mu      sync.Mutex
+	clients map[string]*http.Client
+}
+
+type clientCacheKey struct{}
+
+func WithClientCache(ctx context.Context) (context.Context, *ClientCache) {
+	c := &ClientCache{clients: map[string]*http.Client{}}
+	return context.WithValue(ctx, clientCacheKey{}, c), c
+}
+
+func clientCacheFromContext(ctx context.Context) *ClientCache {
+	c, _ := ctx.Value(clientCacheKey{}).(*ClientCache)
+	return c
+}
+
+// Reset drops every cached client, e.g. after the account was
+// re-authorized.
+func (c *ClientCache) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.clients = map[string]*http.Client{}
+}
+
+// client returns the cached client for the service, account and scopes of
+// ctx, building it on first use. A nil cache always builds.
+func (c *ClientCache) client(ctx context.Context, serviceLabel string, email string, scopes []string, build func() (*http.Client, error)) (*http.Client, error) {
+	if c == nil {
+		return build()
+	}
+
+	key := serviceLabel + "|" + authclient.ClientOverrideFromContext(ctx) + "|" + strings.ToLower(email) + "|" + strings.Join(scopes, " ")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if hc, ok := c.clients[key]; ok {
+		return hc, nil
+	}
+	hc, err := build()
+	if err != nil {
+		return nil, err
+	}
+	c.clients[key] = hc
+
+	return hc, nil
+}
@@ -0,0 +1,75 @@
+package googleapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/steipete/gogcli/internal/authclient"
+)
+
+func TestSession_ReusesAuthTransport(t *testing.T) {
+	builds := 0
+	build := func() (http.RoundTripper, error) {
+		builds++
+		return roundTripFunc(func(*http.Request) (*http.Response, error) { return nil, nil }), nil
+	}
+
+	s := NewSession()
+	ctx := WithSession(context.Background(), s)
+	drive := []string{"https://www.googleapis.com/auth/drive"}
+
+	for i := 0; i < 3; i++ {
+		if _, err := sessionFromContext(ctx).authTransport(ctx, "A@b.com", drive, build); err != nil {
+			t.Fatalf("authTransport: %v", err)
+		}
+	}
+	_, _ = sessionFromContext(ctx).authTransport(ctx, "a@b.com", drive, build)
+	if builds != 1 {
+		t.Fatalf("expected one build for the same account and scopes, got %d", builds)
+	}
+
+	_, _ = s.authTransport(ctx, "a@b.com", []string{"https://www.googleapis.com/auth/gmail.modify"}, build)
+	_, _ = s.authTransport(authclient.WithClient(ctx, "work"), "a@b.com", drive, build)
+	if builds != 3 {
+		t.Fatalf("expected new builds for other scopes and clients, got %d", builds)
+	}
+
+	// Without a session every client builds its own transport.
+	var none *Session
+	_, _ = none.authTransport(context.Background(), "a@b.com", drive, build)
+	_, _ = none.authTransport(context.Background(), "a@b.com", drive, build)
+	if builds != 5 {
+		t.Fatalf("expected builds without session, got %d", builds)
+	}
+}
+
+func TestClientCache_SharesClient(t *testing.T) {
+	builds := 0
+	build := func() (*http.Client, error) {
+		builds++
+		return &http.Client{}, nil
+	}
+
+	ctx, cache := WithClientCache(context.Background())
+	drive := []string{"https://www.googleapis.com/auth/drive"}
+
+	first, _ := clientCacheFromContext(ctx).client(ctx, "drive", "A@b.com", drive, build)
+	second, _ := clientCacheFromContext(ctx).client(ctx, "drive", "a@b.com", drive, build)
+	if first != second || builds != 1 {
+		t.Fatalf("expected the service and batch client to share one client, got %d builds", builds)
+	}
+
+	_, _ = cache.client(ctx, "gmail", "a@b.com", drive, build)
+	cache.Reset()
+	_, _ = cache.client(ctx, "drive", "a@b.com", drive, build)
+	if builds != 3 {
+		t.Fatalf("expected new builds for another service and after Reset, got %d", builds)
+	}
+
+	var none *ClientCache
+	_, _ = none.client(context.Background(), "drive", "a@b.com", drive, build)
+	if builds != 4 {
+		t.Fatalf("expected a build without cache, got %d", builds)
+	}
+}
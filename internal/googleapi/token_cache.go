@@ -0,0 +1,373 @@
+package googleapi
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/99designs/keyring"
+	"golang.org/x/oauth2"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/secrets"
+)
+
+// TokenCacheSecretKey is the keyring entry holding the AES-256 key that
+// encrypts cached access tokens on disk.
+const TokenCacheSecretKey = "token_cache_key"
+
+// tokenCacheMargin keeps a cached token from being handed out when it would
+// expire during the command that uses it.
+const tokenCacheMargin = 2 * time.Minute
+
+var (
+	loadTokenCacheKey = keyringTokenCacheKey
+	tokenCacheNow     = time.Now
+)
+
+// secretStore is the part of secrets.KeyringStore the cache needs. Reading
+// the key through the store that returned the refresh token keeps each
+// invocation at one keyring open.
+type secretStore interface {
+	GetSecret(key string) ([]byte, error)
+	SetSecret(key string, value []byte) error
+}
+
+// keyringSecrets opens the keyring per call; used when the token store
+// cannot hold secrets.
+type keyringSecrets struct{}
+
+func (keyringSecrets) GetSecret(key string) ([]byte, error)     { return secrets.GetSecret(key) }
+func (keyringSecrets) SetSecret(key string, value []byte) error { return secrets.SetSecret(key, value) }
+
+// tokenCacheEnabled reports whether OAuth access tokens are cached across
+// invocations; GOG_TOKEN_CACHE=0 turns the cache off.
+func tokenCacheEnabled() bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("GOG_TOKEN_CACHE"))) {
+	case "0", "off", "false", "no":
+		return false
+	default:
+		return true
+	}
+}
+
+// cachedTokenSource serves access tokens from an encrypted 0600 file shared
+// by all gog processes and only calls refresh when the entry is missing or
+// about to expire. Entries are keyed by client, account, scope set and a
+// fingerprint of the refresh token, so re-authorizing never serves a token
+// minted for the old grant.
+type cachedTokenSource struct {
+	path    string
+	id      string
+	keys    secretStore
+	refresh func() (*oauth2.Token, error)
+
+	mu        sync.Mutex
+	key       []byte
+	keyErr    error
+	tok       *oauth2.Token
+	fromCache bool
+}
+
+func newCachedTokenSource(client string, email string, refreshToken string, scopes []string, keys secretStore, refresh func() (*oauth2.Token, error)) (*cachedTokenSource, bool) {
+	if !tokenCacheEnabled() {
+		return nil, false
+	}
+
+	dir, err := config.TokenCacheDir()
+	if err != nil {
+		slog.Debug("token cache disabled", "err", err)
+		return nil, false
+	}
+
+	sorted := append([]string(nil), scopes...)
+	sort.Strings(sorted)
+	rt := sha256.Sum256([]byte(refreshToken))
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		strings.ToLower(strings.TrimSpace(client)),
+		strings.ToLower(strings.TrimSpace(email)),
+		strings.Join(sorted, " "),
+		hex.EncodeToString(rt[:]),
+	}, "\n")))
+	id := hex.EncodeToString(sum[:])
+
+	if keys == nil {
+		keys = keyringSecrets{}
+	}
+
+	return &cachedTokenSource{
+		path:    filepath.Join(dir, id+".bin"),
+		id:      id,
+		keys:    keys,
+		refresh: refresh,
+	}, true
+}
+
+func (s *cachedTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if usableToken(s.tok) {
+		return s.tok, nil
+	}
+
+	key := s.cacheKey()
+	if key != nil {
+		if tok, err := s.read(key); err == nil && usableToken(tok) {
+			s.tok, s.fromCache = tok, true
+			return tok, nil
+		}
+	}
+
+	tok, err := s.refresh()
+	if err != nil {
+		return nil, err
+	}
+	s.tok, s.fromCache = tok, false
+
+	if key != nil {
+		if err := s.write(key, tok); err != nil {
+			slog.Debug("token cache write failed", "err", err)
+		}
+	}
+
+	return tok, nil
+}
+
+// invalidate drops the current token from memory and disk. It reports
+// whether that token came from the cache, i.e. whether a retry with a
+// freshly refreshed token can help.
+func (s *cachedTokenSource) invalidate() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fromCache := s.fromCache
+	s.tok, s.fromCache = nil, false
+	if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+		slog.Debug("token cache invalidate failed", "err", err)
+	}
+
+	return fromCache
+}
+
+func (s *cachedTokenSource) cacheKey() []byte {
+	if s.key == nil && s.keyErr == nil {
+		s.key, s.keyErr = loadTokenCacheKey(s.keys)
+		if s.keyErr != nil {
+			slog.Debug("token cache disabled", "err", s.keyErr)
+		}
+	}
+
+	return s.key
+}
+
+type tokenCacheEntry struct {
+	AccessToken string    `json:"access_token"`
+	TokenType   string    `json:"token_type,omitempty"`
+	Expiry      time.Time `json:"expiry"`
+}
+
+func (s *cachedTokenSource) read(key []byte) (*oauth2.Token, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newTokenCacheGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("token cache entry too short")
+	}
+
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], []byte(s.id))
+	if err != nil {
+		return nil, fmt.Errorf("decrypt token cache entry: %w", err)
+	}
+
+	var e tokenCacheEntry
+	if err := json.Unmarshal(plain, &e); err != nil {
+		return nil, fmt.Errorf("decode token cache entry: %w", err)
+	}
+
+	return &oauth2.Token{AccessToken: e.AccessToken, TokenType: e.TokenType, Expiry: e.Expiry}, nil
+}
+
+func (s *cachedTokenSource) write(key []byte, tok *oauth2.Token) error {
+	if tok.AccessToken == "" || tok.Expiry.IsZero() {
+		return nil
+	}
+
+	plain, err := json.Marshal(tokenCacheEntry{AccessToken: tok.AccessToken, TokenType: tok.TokenType, Expiry: tok.Expiry})
+	if err != nil {
+		return fmt.Errorf("encode token cache entry: %w", err)
+	}
+
+	gcm, err := newTokenCacheGCM(key)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return fmt.Errorf("token cache nonce: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("create token cache dir: %w", err)
+	}
+
+	// A temp file per process: concurrent gog runs must not share one.
+	f, err := os.CreateTemp(filepath.Dir(s.path), "*.tmp")
+	if err != nil {
+		return fmt.Errorf("write token cache entry: %w", err)
+	}
+	tmp := f.Name()
+	_, err = f.Write(gcm.Seal(nonce, nonce, plain, []byte(s.id)))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("write token cache entry: %w", err)
+	}
+
+	if err := os.Rename(tmp, s.path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("commit token cache entry: %w", err)
+	}
+
+	return nil
+}
+
+func usableToken(tok *oauth2.Token) bool {
+	return tok != nil && tok.AccessToken != "" && tok.Expiry.After(tokenCacheNow().Add(tokenCacheMargin))
+}
+
+func newTokenCacheGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("token cache cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("token cache cipher: %w", err)
+	}
+
+	return gcm, nil
+}
+
+// keyringTokenCacheKey returns the cache encryption key, creating it on
+// first use.
+func keyringTokenCacheKey(store secretStore) ([]byte, error) {
+	key, err := store.GetSecret(TokenCacheSecretKey)
+	if err == nil && len(key) == 32 {
+		return key, nil
+	}
+
+	if err != nil && !errors.Is(err, keyring.ErrKeyNotFound) {
+		return nil, err
+	}
+
+	key = make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, fmt.Errorf("generate token cache key: %w", err)
+	}
+
+	if err := store.SetSecret(TokenCacheSecretKey, key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// FlushTokenCache removes every cached access token and returns how many
+// were removed. The next API call of each account refreshes again.
+func FlushTokenCache() (int, error) {
+	dir, err := config.TokenCacheDir()
+	if err != nil {
+		return 0, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+
+		return 0, fmt.Errorf("read token cache: %w", err)
+	}
+
+	n := 0
+
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(dir, e.Name())); err != nil {
+			return n, fmt.Errorf("remove token cache entry: %w", err)
+		}
+
+		if strings.HasSuffix(e.Name(), ".bin") {
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+// tokenCacheTransport drops a cached access token that Google rejects with
+// 401 (revoked, or outlived a password change) and retries the request once
+// with a freshly refreshed token.
+type tokenCacheTransport struct {
+	base http.RoundTripper
+	src  *cachedTokenSource
+}
+
+func (t *tokenCacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	if !t.src.invalidate() {
+		return resp, nil
+	}
+
+	retry := req.Clone(req.Context())
+	if req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody == nil {
+			return resp, nil
+		}
+
+		body, bodyErr := req.GetBody()
+		if bodyErr != nil {
+			return resp, nil //nolint:nilerr // keep the original 401
+		}
+
+		retry.Body = body
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	return t.base.RoundTrip(retry)
+}
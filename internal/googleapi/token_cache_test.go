@@ -0,0 +1,239 @@
+package googleapi
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/99designs/keyring"
+	"golang.org/x/oauth2"
+)
+
+func setupTokenCache(t *testing.T) {
+	t.Helper()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg-config"))
+	t.Setenv("GOG_TOKEN_CACHE", "")
+
+	origKey := loadTokenCacheKey
+	t.Cleanup(func() { loadTokenCacheKey = origKey })
+	loadTokenCacheKey = func(secretStore) ([]byte, error) { return bytes.Repeat([]byte{7}, 32), nil }
+}
+
+func countingRefresh(calls *int, tokens ...string) func() (*oauth2.Token, error) {
+	return func() (*oauth2.Token, error) {
+		tok := tokens[min(*calls, len(tokens)-1)]
+		*calls++
+		return &oauth2.Token{AccessToken: tok, TokenType: "Bearer", Expiry: time.Now().Add(time.Hour)}, nil
+	}
+}
+
+func TestCachedTokenSource_SharedAcrossSources(t *testing.T) {
+	setupTokenCache(t)
+
+	calls := 0
+	refresh := countingRefresh(&calls, "at-1", "at-2")
+
+	first, ok := newCachedTokenSource("default", "a@b.com", "rt", []string{"s2", "s1"}, nil, refresh)
+	if !ok {
+		t.Fatalf("expected cache enabled")
+	}
+	tok, err := first.Token()
+	if err != nil || tok.AccessToken != "at-1" {
+		t.Fatalf("first Token: %v %v", tok, err)
+	}
+
+	info, err := os.Stat(first.path)
+	if err != nil {
+		t.Fatalf("stat cache entry: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Fatalf("unexpected cache entry mode: %v", info.Mode().Perm())
+	}
+	if data, _ := os.ReadFile(first.path); bytes.Contains(data, []byte("at-1")) {
+		t.Fatalf("cache entry is not encrypted")
+	}
+
+	// A second invocation with the same scope set reuses the entry.
+	second, _ := newCachedTokenSource("default", "A@B.com", "rt", []string{"s1", "s2"}, nil, refresh)
+	if tok, err := second.Token(); err != nil || tok.AccessToken != "at-1" || calls != 1 {
+		t.Fatalf("expected cached token, got %v %v (calls=%d)", tok, err, calls)
+	}
+
+	// Other scopes, clients or refresh tokens get their own entry.
+	for _, src := range []struct{ client, rt string }{{"work", "rt"}, {"default", "rt-new"}} {
+		other, _ := newCachedTokenSource(src.client, "a@b.com", src.rt, []string{"s1", "s2"}, nil, refresh)
+		if other.path == first.path {
+			t.Fatalf("expected separate cache entry for %+v", src)
+		}
+	}
+
+	n, err := FlushTokenCache()
+	if err != nil || n != 1 {
+		t.Fatalf("FlushTokenCache: n=%d err=%v", n, err)
+	}
+	third, _ := newCachedTokenSource("default", "a@b.com", "rt", []string{"s1", "s2"}, nil, refresh)
+	if tok, _ := third.Token(); tok.AccessToken != "at-2" || calls != 2 {
+		t.Fatalf("expected refresh after flush, got %v (calls=%d)", tok, calls)
+	}
+}
+
+func TestCachedTokenSource_ConcurrentWrites(t *testing.T) {
+	setupTokenCache(t)
+
+	src, _ := newCachedTokenSource("default", "a@b.com", "rt", []string{"s1"}, nil, nil)
+	key := bytes.Repeat([]byte{7}, 32)
+	tok := &oauth2.Token{AccessToken: "at", TokenType: "Bearer", Expiry: time.Now().Add(time.Hour)}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- src.write(key, tok)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	if leftovers, _ := filepath.Glob(filepath.Join(filepath.Dir(src.path), "*.tmp")); len(leftovers) != 0 {
+		t.Fatalf("temp files left behind: %v", leftovers)
+	}
+	if got, err := src.read(key); err != nil || got.AccessToken != "at" {
+		t.Fatalf("read: %v %v", got, err)
+	}
+}
+
+func TestCachedTokenSource_ExpiryMargin(t *testing.T) {
+	setupTokenCache(t)
+
+	calls := 0
+	src, _ := newCachedTokenSource("default", "a@b.com", "rt", []string{"s1"}, nil, func() (*oauth2.Token, error) {
+		calls++
+		return &oauth2.Token{AccessToken: "at", Expiry: time.Now().Add(time.Minute)}, nil
+	})
+
+	for range 2 {
+		if _, err := src.Token(); err != nil {
+			t.Fatalf("Token: %v", err)
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("expected tokens inside the safety margin to be refreshed, calls=%d", calls)
+	}
+}
+
+func TestCachedTokenSource_Disabled(t *testing.T) {
+	setupTokenCache(t)
+	t.Setenv("GOG_TOKEN_CACHE", "off")
+
+	if _, ok := newCachedTokenSource("default", "a@b.com", "rt", nil, nil, nil); ok {
+		t.Fatalf("expected cache disabled")
+	}
+}
+
+func TestTokenCacheTransport_InvalidatesOn401(t *testing.T) {
+	setupTokenCache(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get("Authorization") != "Bearer fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+
+	calls := 0
+	refresh := countingRefresh(&calls, "revoked", "fresh")
+
+	// Another process cached a token that has since been revoked.
+	seed, _ := newCachedTokenSource("default", "a@b.com", "rt", []string{"s1"}, nil, refresh)
+	if _, err := seed.Token(); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	src, _ := newCachedTokenSource("default", "a@b.com", "rt", []string{"s1"}, nil, refresh)
+	client := &http.Client{Transport: authTransport(src)}
+
+	resp, err := client.Post(srv.URL, "text/plain", bytes.NewReader([]byte("payload")))
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(body) != "payload" {
+		t.Fatalf("expected retried request to succeed, got %d %q", resp.StatusCode, body)
+	}
+	if calls != 2 {
+		t.Fatalf("expected one refresh after the 401, calls=%d", calls)
+	}
+
+	// A 401 for a freshly refreshed token is returned as is.
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	resp2, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	resp2.Body.Close()
+	if resp2.StatusCode != http.StatusUnauthorized || calls != 2 {
+		t.Fatalf("expected plain 401 without another refresh, got %d (calls=%d)", resp2.StatusCode, calls)
+	}
+}
+
+type memSecrets struct {
+	data map[string][]byte
+}
+
+func (m *memSecrets) GetSecret(key string) ([]byte, error) {
+	if v, ok := m.data[key]; ok {
+		return v, nil
+	}
+	return nil, fmt.Errorf("read secret: %w", keyring.ErrKeyNotFound)
+}
+
+func (m *memSecrets) SetSecret(key string, value []byte) error {
+	m.data[key] = value
+	return nil
+}
+
+func TestCachedTokenSource_KeyFromTokenStore(t *testing.T) {
+	setupTokenCache(t)
+	loadTokenCacheKey = keyringTokenCacheKey
+
+	store := &memSecrets{data: map[string][]byte{}}
+	calls := 0
+	refresh := countingRefresh(&calls, "at-1")
+
+	first, _ := newCachedTokenSource("default", "a@b.com", "rt", []string{"s1"}, store, refresh)
+	if _, err := first.Token(); err != nil {
+		t.Fatalf("first Token: %v", err)
+	}
+	if len(store.data[TokenCacheSecretKey]) != 32 {
+		t.Fatalf("expected cache key created in the token store, got %v", store.data)
+	}
+
+	second, _ := newCachedTokenSource("default", "a@b.com", "rt", []string{"s1"}, store, refresh)
+	if tok, err := second.Token(); err != nil || tok.AccessToken != "at-1" || calls != 1 {
+		t.Fatalf("expected cached token via the same store: %v %v calls=%d", tok, err, calls)
+	}
+}
@@ -12,13 +12,18 @@ import (
 )
 
 // RetryTransport wraps an http.RoundTripper with retry logic for
-// rate limits (429) and server errors (5xx).
+// rate limits (429) and server errors (5xx). With a Limiter set, every
+// attempt first waits for quota in the bucket of its service and Account.
 type RetryTransport struct {
 	Base           http.RoundTripper
 	MaxRetries429  int
 	MaxRetries5xx  int
 	BaseDelay      time.Duration
 	CircuitBreaker *CircuitBreaker
+	// Breakers, when set, replaces CircuitBreaker with one breaker per service.
+	Breakers *CircuitBreakers
+	Limiter  *RateLimiter
+	Account  string
 }
 
 // NewRetryTransport creates a RetryTransport with sensible defaults.
@@ -38,8 +43,13 @@ func NewRetryTransport(base http.RoundTripper) *RetryTransport {
 
 // RoundTrip implements http.RoundTripper with retry logic.
 func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	if t.CircuitBreaker != nil && t.CircuitBreaker.IsOpen() {
-		return nil, &CircuitBreakerError{}
+	service := rateLimitService(req.URL)
+	breaker := t.breaker(service, req.URL.Host)
+	if breaker != nil {
+		if wait, ok := breaker.Allow(); !ok {
+			slog.Debug("circuit breaker open, rejecting request", "service", breaker.Service, "state", breaker.State(), "retry_in", wait)
+			return nil, &CircuitBreakerError{Service: breaker.Service, RetryAfter: wait}
+		}
 	}
 
 	if err := ensureReplayableBody(req); err != nil {
@@ -50,6 +60,7 @@ func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	var err error
 	retries429 := 0
 	retries5xx := 0
+	cost := requestCost(req)
 
 	for {
 		// Reset body for retry
@@ -65,22 +76,33 @@ func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 			}
 		}
 
+		if err := t.Limiter.Wait(req.Context(), service, t.Account, cost); err != nil {
+			return nil, err
+		}
+
 		resp, err = t.Base.RoundTrip(req)
 		if err != nil {
+			if breaker != nil && req.Context().Err() == nil {
+				breaker.RecordFailure()
+			}
+
 			return nil, fmt.Errorf("round trip: %w", err)
 		}
 
+		// Any answer below 500 means the API is up.
+		if resp.StatusCode < 500 && breaker != nil {
+			breaker.RecordSuccess()
+		}
+
 		// Success
 		if resp.StatusCode < 400 {
-			if t.CircuitBreaker != nil {
-				t.CircuitBreaker.RecordSuccess()
-			}
-
 			return resp, nil
 		}
 
 		// Rate limit (429)
 		if resp.StatusCode == http.StatusTooManyRequests {
+			t.Limiter.Drain(service, t.Account)
+
 			if retries429 >= t.MaxRetries429 {
 				return resp, nil // Return the 429 response after max retries
 			}
@@ -104,8 +126,14 @@ func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 
 		// Server error (5xx)
 		if resp.StatusCode >= 500 {
-			if t.CircuitBreaker != nil {
-				t.CircuitBreaker.RecordFailure()
+			if breaker != nil {
+				breaker.RecordFailure()
+				// A failed probe or the last failure before the threshold
+				// opens the breaker; stop retrying instead of hammering.
+				if breaker.IsOpen() {
+					drainAndClose(resp.Body)
+					return nil, &CircuitBreakerError{Service: breaker.Service, RetryAfter: CircuitBreakerResetTime}
+				}
 			}
 
 			if retries5xx >= t.MaxRetries5xx {
@@ -132,6 +160,17 @@ func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	}
 }
 
+func (t *RetryTransport) breaker(service string, host string) *CircuitBreaker {
+	if t.Breakers == nil {
+		return t.CircuitBreaker
+	}
+	if service == "" {
+		service = host
+	}
+
+	return t.Breakers.For(service)
+}
+
 func (t *RetryTransport) calculateBackoff(attempt int, resp *http.Response) time.Duration {
 	// Check Retry-After header
 	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
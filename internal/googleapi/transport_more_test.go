@@ -202,6 +202,39 @@ func TestRetryTransportRoundTripRetries5xx(t *testing.T) {
 	}
 }
 
+func TestRetryTransportFailedProbeStopsRetries(t *testing.T) {
+	calls := 0
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return newTestResponse(http.StatusServiceUnavailable, "down"), nil
+	})
+
+	cb := NewCircuitBreaker()
+	cb.open = true
+	cb.lastFailure = time.Now().Add(-CircuitBreakerResetTime - time.Second)
+	rt := &RetryTransport{
+		Base:           base,
+		MaxRetries5xx:  3,
+		CircuitBreaker: cb,
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://example.com", nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+
+	resp, err := rt.RoundTrip(req)
+	if resp != nil {
+		_ = resp.Body.Close()
+	}
+	if !IsCircuitBreakerError(err) {
+		t.Fatalf("expected circuit breaker error, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected only the probe to be sent, got %d calls", calls)
+	}
+}
+
 func TestRetryTransportCircuitBreakerOpen(t *testing.T) {
 	calls := 0
 	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
@@ -43,16 +43,37 @@ const (
 	DriveScopeFile     DriveScopeMode = "file"
 )
 
+// DriveScopeModeOf returns the Drive scope mode a token was granted with.
+// Full Drive wins over drive.file, which wins over drive.readonly; "" means
+// scopes hold no Drive scope.
+func DriveScopeModeOf(scopes []string) DriveScopeMode {
+	var mode DriveScopeMode
+	for _, s := range scopes {
+		switch s {
+		case "https://www.googleapis.com/auth/drive":
+			return DriveScopeFull
+		case "https://www.googleapis.com/auth/drive.file":
+			mode = DriveScopeFile
+		case "https://www.googleapis.com/auth/drive.readonly":
+			if mode == "" {
+				mode = DriveScopeReadonly
+			}
+		}
+	}
+	return mode
+}
+
 type ScopeOptions struct {
 	Readonly   bool
 	DriveScope DriveScopeMode
 }
 
 type serviceInfo struct {
-	scopes []string
-	user   bool
-	apis   []string
-	note   string
+	scopes  []string
+	user    bool
+	apis    []string
+	note    string
+	baseURL string
 }
 
 var serviceOrder = []Service{
@@ -77,13 +98,15 @@ var serviceInfoByService = map[Service]serviceInfo{
 			"https://www.googleapis.com/auth/gmail.settings.basic",
 			"https://www.googleapis.com/auth/gmail.settings.sharing",
 		},
-		user: true,
-		apis: []string{"Gmail API"},
+		user:    true,
+		apis:    []string{"Gmail API"},
+		baseURL: "https://gmail.googleapis.com/gmail/v1/",
 	},
 	ServiceCalendar: {
-		scopes: []string{"https://www.googleapis.com/auth/calendar"},
-		user:   true,
-		apis:   []string{"Calendar API"},
+		scopes:  []string{"https://www.googleapis.com/auth/calendar"},
+		user:    true,
+		apis:    []string{"Calendar API"},
+		baseURL: "https://www.googleapis.com/calendar/v3/",
 	},
 	ServiceChat: {
 		scopes: []string{
@@ -92,8 +115,9 @@ var serviceInfoByService = map[Service]serviceInfo{
 			"https://www.googleapis.com/auth/chat.memberships",
 			"https://www.googleapis.com/auth/chat.users.readstate.readonly",
 		},
-		user: true,
-		apis: []string{"Chat API"},
+		user:    true,
+		apis:    []string{"Chat API"},
+		baseURL: "https://chat.googleapis.com/v1/",
 	},
 	ServiceClassroom: {
 		scopes: []string{
@@ -108,13 +132,15 @@ var serviceInfoByService = map[Service]serviceInfo{
 			"https://www.googleapis.com/auth/classroom.profile.emails",
 			"https://www.googleapis.com/auth/classroom.profile.photos",
 		},
-		user: true,
-		apis: []string{"Classroom API"},
+		user:    true,
+		apis:    []string{"Classroom API"},
+		baseURL: "https://classroom.googleapis.com/v1/",
 	},
 	ServiceDrive: {
-		scopes: []string{"https://www.googleapis.com/auth/drive"},
-		user:   true,
-		apis:   []string{"Drive API"},
+		scopes:  []string{"https://www.googleapis.com/auth/drive"},
+		user:    true,
+		apis:    []string{"Drive API"},
+		baseURL: "https://www.googleapis.com/drive/v3/",
 	},
 	ServiceDocs: {
 		// Docs commands are implemented via Drive APIs (export/copy/create),
@@ -123,9 +149,10 @@ var serviceInfoByService = map[Service]serviceInfo{
 			"https://www.googleapis.com/auth/drive",
 			"https://www.googleapis.com/auth/documents",
 		},
-		user: true,
-		apis: []string{"Docs API", "Drive API"},
-		note: "Export/copy/create via Drive",
+		user:    true,
+		apis:    []string{"Docs API", "Drive API"},
+		note:    "Export/copy/create via Drive",
+		baseURL: "https://docs.googleapis.com/v1/",
 	},
 	ServiceContacts: {
 		scopes: []string{
@@ -133,42 +160,48 @@ var serviceInfoByService = map[Service]serviceInfo{
 			"https://www.googleapis.com/auth/contacts.other.readonly",
 			"https://www.googleapis.com/auth/directory.readonly",
 		},
-		user: true,
-		apis: []string{"People API"},
-		note: "Contacts + other contacts + directory",
+		user:    true,
+		apis:    []string{"People API"},
+		note:    "Contacts + other contacts + directory",
+		baseURL: "https://people.googleapis.com/v1/",
 	},
 	ServiceTasks: {
-		scopes: []string{"https://www.googleapis.com/auth/tasks"},
-		user:   true,
-		apis:   []string{"Tasks API"},
+		scopes:  []string{"https://www.googleapis.com/auth/tasks"},
+		user:    true,
+		apis:    []string{"Tasks API"},
+		baseURL: "https://tasks.googleapis.com/tasks/v1/",
 	},
 	ServicePeople: {
 		// Needed for "people/me" requests.
-		scopes: []string{"profile"},
-		user:   true,
-		apis:   []string{"People API"},
-		note:   "OIDC profile scope",
+		scopes:  []string{"profile"},
+		user:    true,
+		apis:    []string{"People API"},
+		note:    "OIDC profile scope",
+		baseURL: "https://people.googleapis.com/v1/",
 	},
 	ServiceSheets: {
 		scopes: []string{
 			"https://www.googleapis.com/auth/drive",
 			"https://www.googleapis.com/auth/spreadsheets",
 		},
-		user: true,
-		apis: []string{"Sheets API", "Drive API"},
-		note: "Export via Drive",
+		user:    true,
+		apis:    []string{"Sheets API", "Drive API"},
+		note:    "Export via Drive",
+		baseURL: "https://sheets.googleapis.com/v4/",
 	},
 	ServiceGroups: {
-		scopes: []string{"https://www.googleapis.com/auth/cloud-identity.groups.readonly"},
-		user:   false,
-		apis:   []string{"Cloud Identity API"},
-		note:   "Workspace only",
+		scopes:  []string{"https://www.googleapis.com/auth/cloud-identity.groups.readonly"},
+		user:    false,
+		apis:    []string{"Cloud Identity API"},
+		note:    "Workspace only",
+		baseURL: "https://cloudidentity.googleapis.com/v1/",
 	},
 	ServiceKeep: {
-		scopes: []string{"https://www.googleapis.com/auth/keep.readonly"},
-		user:   false,
-		apis:   []string{"Keep API"},
-		note:   "Workspace only; service account (domain-wide delegation)",
+		scopes:  []string{"https://www.googleapis.com/auth/keep.readonly"},
+		user:    false,
+		apis:    []string{"Keep API"},
+		note:    "Workspace only; service account (domain-wide delegation)",
+		baseURL: "https://keep.googleapis.com/v1/",
 	},
 }
 
@@ -222,6 +255,17 @@ func Scopes(service Service) ([]string, error) {
 	return append([]string(nil), info.scopes...), nil
 }
 
+// BaseURL returns the REST root for service, including the API version,
+// e.g. https://www.googleapis.com/drive/v3/.
+func BaseURL(service Service) (string, error) {
+	info, ok := serviceInfoByService[service]
+	if !ok {
+		return "", errUnknownService
+	}
+
+	return info.baseURL, nil
+}
+
 type ServiceInfo struct {
 	Service Service  `json:"service"`
 	User    bool     `json:"user"`
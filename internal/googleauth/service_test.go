@@ -1,6 +1,9 @@
 package googleauth
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestParseService(t *testing.T) {
 	tests := []struct {
@@ -425,3 +428,41 @@ func TestScopes_UnknownService(t *testing.T) {
 		t.Fatalf("expected error")
 	}
 }
+
+func TestBaseURL(t *testing.T) {
+	for _, svc := range AllServices() {
+		u, err := BaseURL(svc)
+		if err != nil {
+			t.Fatalf("%s: %v", svc, err)
+		}
+		if !strings.HasPrefix(u, "https://") || !strings.HasSuffix(u, "/") {
+			t.Fatalf("%s: unexpected base URL %q", svc, u)
+		}
+	}
+
+	if u, _ := BaseURL(ServiceDrive); u != "https://www.googleapis.com/drive/v3/" {
+		t.Fatalf("drive base URL: %q", u)
+	}
+
+	if _, err := BaseURL(Service("nope")); err == nil {
+		t.Fatalf("expected error for unknown service")
+	}
+}
+
+func TestDriveScopeModeOf(t *testing.T) {
+	cases := []struct {
+		scopes []string
+		want   DriveScopeMode
+	}{
+		{nil, ""},
+		{[]string{"https://www.googleapis.com/auth/gmail.modify"}, ""},
+		{[]string{"https://www.googleapis.com/auth/drive.readonly"}, DriveScopeReadonly},
+		{[]string{"https://www.googleapis.com/auth/drive.file", "https://www.googleapis.com/auth/drive.readonly"}, DriveScopeFile},
+		{[]string{"https://www.googleapis.com/auth/drive.readonly", "https://www.googleapis.com/auth/drive"}, DriveScopeFull},
+	}
+	for _, tc := range cases {
+		if got := DriveScopeModeOf(tc.scopes); got != tc.want {
+			t.Fatalf("%v: want %q, got %q", tc.scopes, tc.want, got)
+		}
+	}
+}
@@ -4,19 +4,27 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"golang.org/x/oauth2"
 )
 
 func CheckRefreshToken(ctx context.Context, client string, refreshToken string, scopes []string, timeout time.Duration) error {
+	_, err := RefreshAccessToken(ctx, client, refreshToken, scopes, timeout)
+	return err
+}
+
+// RefreshAccessToken exchanges a refresh token for a fresh access token.
+// The token endpoint lists the scopes actually granted; see GrantedScopes.
+func RefreshAccessToken(ctx context.Context, client string, refreshToken string, scopes []string, timeout time.Duration) (*oauth2.Token, error) {
 	if timeout <= 0 {
 		timeout = 15 * time.Second
 	}
 
 	creds, err := readClientCredentials(client)
 	if err != nil {
-		return fmt.Errorf("read credentials: %w", err)
+		return nil, fmt.Errorf("read credentials: %w", err)
 	}
 
 	cfg := oauth2.Config{
@@ -32,9 +40,22 @@ func CheckRefreshToken(ctx context.Context, client string, refreshToken string,
 	ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Timeout: timeout})
 
 	ts := cfg.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken})
-	if _, err := ts.Token(); err != nil {
-		return fmt.Errorf("refresh access token: %w", err)
+	tok, err := ts.Token()
+	if err != nil {
+		return nil, fmt.Errorf("refresh access token: %w", err)
+	}
+
+	return tok, nil
+}
+
+// GrantedScopes returns the space-separated "scope" field of a token
+// response, or nil when the server did not include it.
+func GrantedScopes(tok *oauth2.Token) []string {
+	if tok == nil {
+		return nil
 	}
 
-	return nil
+	raw, _ := tok.Extra("scope").(string)
+
+	return strings.Fields(raw)
 }
@@ -92,3 +92,48 @@ func TestCheckRefreshTokenReadCredentialsError(t *testing.T) {
 		t.Fatalf("expected error")
 	}
 }
+
+func TestRefreshAccessTokenGrantedScopes(t *testing.T) {
+	origRead := readClientCredentials
+	origEndpoint := oauthEndpoint
+
+	t.Cleanup(func() {
+		readClientCredentials = origRead
+		oauthEndpoint = origEndpoint
+	})
+
+	readClientCredentials = func(string) (config.ClientCredentials, error) {
+		return config.ClientCredentials{ClientID: "id", ClientSecret: "secret"}, nil
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "access",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"scope":        "openid https://www.googleapis.com/auth/drive",
+		})
+	}))
+	defer srv.Close()
+
+	oauthEndpoint = oauth2.Endpoint{AuthURL: srv.URL, TokenURL: srv.URL}
+
+	tok, err := RefreshAccessToken(context.Background(), "default", "good", nil, time.Second)
+	if err != nil {
+		t.Fatalf("RefreshAccessToken: %v", err)
+	}
+
+	if tok.AccessToken != "access" {
+		t.Fatalf("unexpected access token %q", tok.AccessToken)
+	}
+
+	got := GrantedScopes(tok)
+	if len(got) != 2 || got[1] != "https://www.googleapis.com/auth/drive" {
+		t.Fatalf("unexpected granted scopes %q", got)
+	}
+
+	if GrantedScopes(nil) != nil {
+		t.Fatalf("expected nil scopes for nil token")
+	}
+}
@@ -0,0 +1,151 @@
+package outfmt
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Format names an output format selectable via --output-format.
+type Format string
+
+const (
+	FormatTable  Format = "table"
+	FormatTSV    Format = "tsv"
+	FormatJSON   Format = "json"
+	FormatNDJSON Format = "ndjson"
+	FormatCSV    Format = "csv"
+	FormatYAML   Format = "yaml"
+)
+
+// Structured reports whether the format renders command payloads (as opposed
+// to the human table/tsv text output).
+func (f Format) Structured() bool {
+	return f != FormatTable && f != FormatTSV
+}
+
+// Encoder renders a command payload to w.
+type Encoder func(w io.Writer, v any) error
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[Format]Encoder{
+		FormatJSON:   WriteJSON,
+		FormatNDJSON: WriteNDJSON,
+		FormatCSV:    WriteCSV,
+		FormatYAML:   WriteYAML,
+	}
+)
+
+// Register adds (or replaces) a structured format encoder.
+func Register(f Format, enc Encoder) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[f] = enc
+}
+
+func lookupEncoder(f Format) (Encoder, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	enc, ok := registry[f]
+	return enc, ok
+}
+
+// Formats returns every selectable format name, text formats first.
+func Formats() []Format {
+	registryMu.RLock()
+	structured := make([]Format, 0, len(registry))
+	for f := range registry {
+		structured = append(structured, f)
+	}
+	registryMu.RUnlock()
+
+	sort.Slice(structured, func(i, j int) bool { return structured[i] < structured[j] })
+	return append([]Format{FormatTable, FormatTSV}, structured...)
+}
+
+func ParseFormat(s string) (Format, error) {
+	f := Format(strings.ToLower(strings.TrimSpace(s)))
+	switch f {
+	case FormatTable, FormatTSV:
+		return f, nil
+	case "plain", "text":
+		return FormatTSV, nil
+	}
+	if _, ok := lookupEncoder(f); ok {
+		return f, nil
+	}
+
+	names := make([]string, 0, len(Formats()))
+	for _, known := range Formats() {
+		names = append(names, string(known))
+	}
+	return "", &ParseError{msg: fmt.Sprintf("invalid output format %q (expected %s)", s, strings.Join(names, "|"))}
+}
+
+// WriteNDJSON writes one compact JSON document per record.
+func WriteNDJSON(w io.Writer, v any) error {
+	tree, err := normalize(v)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, rec := range records(tree) {
+		if err := enc.Encode(rec); err != nil {
+			return fmt.Errorf("encode ndjson: %w", err)
+		}
+	}
+	return nil
+}
+
+// WriteCSV writes RFC 4180 CSV with a header row. Nested objects are
+// flattened into dotted column names.
+func WriteCSV(w io.Writer, v any) error {
+	tree, err := normalize(v)
+	if err != nil {
+		return err
+	}
+
+	recs := records(tree)
+	rows := make([]*object, 0, len(recs))
+	var header []string
+	seen := map[string]bool{}
+	for _, rec := range recs {
+		row := &object{values: map[string]any{}}
+		flatten(row, "", rec)
+		for _, k := range row.keys {
+			if !seen[k] {
+				seen[k] = true
+				header = append(header, k)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	cw := csv.NewWriter(w)
+	if len(header) > 0 {
+		if err := cw.Write(header); err != nil {
+			return fmt.Errorf("encode csv: %w", err)
+		}
+	}
+	for _, row := range rows {
+		line := make([]string, len(header))
+		for i, k := range header {
+			line[i] = scalarString(row.values[k])
+		}
+		if err := cw.Write(line); err != nil {
+			return fmt.Errorf("encode csv: %w", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("encode csv: %w", err)
+	}
+	return nil
+}
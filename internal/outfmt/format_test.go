@@ -0,0 +1,166 @@
+package outfmt
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+type fileRow struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Owner struct {
+		Email string `json:"email"`
+	} `json:"owner"`
+	Labels []string `json:"labels,omitempty"`
+}
+
+func samplePayload() map[string]any {
+	a := fileRow{ID: "1", Name: "a, \"quoted\""}
+	a.Owner.Email = "a@b.com"
+	b := fileRow{ID: "2", Name: "b", Labels: []string{"X", "Y"}}
+	return map[string]any{
+		"files":         []fileRow{a, b},
+		"nextPageToken": "tok",
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, name := range []string{"table", "tsv", "json", "ndjson", "csv", "yaml", "YAML", " plain "} {
+		if _, err := ParseFormat(name); err != nil {
+			t.Fatalf("ParseFormat(%q): %v", name, err)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil || !strings.Contains(err.Error(), "ndjson") {
+		t.Fatalf("expected error listing formats, got %v", err)
+	}
+}
+
+func TestModeWithFormat(t *testing.T) {
+	m, err := Mode{}.WithFormat("csv")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !m.JSON || m.Plain || m.ResolvedFormat() != FormatCSV {
+		t.Fatalf("unexpected mode: %#v", m)
+	}
+
+	m, err = Mode{}.WithFormat("tsv")
+	if err != nil || !m.Plain || m.JSON {
+		t.Fatalf("unexpected tsv mode: %#v err=%v", m, err)
+	}
+
+	if _, err = (Mode{JSON: true}).WithFormat("ndjson"); err != nil {
+		t.Fatalf("--json should combine with ndjson: %v", err)
+	}
+	if _, err = (Mode{JSON: true}).WithFormat("table"); err == nil {
+		t.Fatalf("expected conflict for --json + table")
+	}
+	if _, err = (Mode{Plain: true}).WithFormat("csv"); err == nil {
+		t.Fatalf("expected conflict for --plain + csv")
+	}
+
+	if got := (Mode{JSON: true}).ResolvedFormat(); got != FormatJSON {
+		t.Fatalf("legacy json resolved to %q", got)
+	}
+	if got := (Mode{}).ResolvedFormat(); got != FormatTable {
+		t.Fatalf("default resolved to %q", got)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, samplePayload()); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	want := "id,name,owner.email,labels\n" +
+		"1,\"a, \"\"quoted\"\"\",a@b.com,\n" +
+		"2,b,,\"[\"\"X\"\",\"\"Y\"\"]\"\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected csv:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestWriteNDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteNDJSON(&buf, samplePayload()); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", buf.String())
+	}
+	if lines[0] != `{"id":"1","name":"a, \"quoted\"","owner":{"email":"a@b.com"}}` {
+		t.Fatalf("unexpected first record: %s", lines[0])
+	}
+
+	buf.Reset()
+	if err := WriteNDJSON(&buf, map[string]any{"id": "x"}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if buf.String() != "{\"id\":\"x\"}\n" {
+		t.Fatalf("single object should be one line, got %q", buf.String())
+	}
+}
+
+func TestWriteYAML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteYAML(&buf, samplePayload()); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	want := `files:
+- id: "1"
+  name: a, "quoted"
+  owner:
+    email: a@b.com
+- id: "2"
+  name: b
+  owner:
+    email: ""
+  labels:
+  - X
+  - "Y"
+nextPageToken: tok
+`
+	if buf.String() != want {
+		t.Fatalf("unexpected yaml:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestWriteUsesContextFormat(t *testing.T) {
+	ctx := WithMode(context.Background(), Mode{JSON: true, Format: FormatCSV})
+	var buf bytes.Buffer
+	if err := Write(ctx, &buf, map[string]any{"items": []map[string]string{{"k": "v"}}}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if buf.String() != "k\nv\n" {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := Write(context.Background(), &buf, map[string]any{"ok": true}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !strings.Contains(buf.String(), "\"ok\": true") {
+		t.Fatalf("expected indented json fallback, got %q", buf.String())
+	}
+}
+
+func TestRecords_EmptyListEnvelope(t *testing.T) {
+	var buf bytes.Buffer
+	var files []fileRow
+	if err := WriteCSV(&buf, map[string]any{"files": files, "nextPageToken": ""}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("expected no csv output, got %q", buf.String())
+	}
+
+	if err := WriteNDJSON(&buf, map[string]any{"found": false}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if buf.String() != "{\"found\":false}\n" {
+		t.Fatalf("unexpected ndjson: %q", buf.String())
+	}
+}
@@ -9,9 +9,15 @@ import (
 	"strings"
 )
 
+// Mode is the resolved output mode for a single invocation.
+//
+// JSON is set for every structured format (json, ndjson, csv, yaml) so
+// commands keep a single "machine payload" code path; Format selects how
+// that payload is rendered by Write.
 type Mode struct {
-	JSON  bool
-	Plain bool
+	JSON   bool
+	Plain  bool
+	Format Format
 }
 
 type ParseError struct{ msg string }
@@ -26,6 +32,45 @@ func FromFlags(jsonOut bool, plainOut bool) (Mode, error) {
 	return Mode{JSON: jsonOut, Plain: plainOut}, nil
 }
 
+// WithFormat applies an explicit --output-format on top of the legacy
+// --json/--plain flags. --json combines with any structured format, --plain
+// only with tsv. An empty name leaves the mode unchanged.
+func (m Mode) WithFormat(name string) (Mode, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return m, nil
+	}
+
+	f, err := ParseFormat(name)
+	if err != nil {
+		return Mode{}, err
+	}
+
+	if m.JSON && !f.Structured() {
+		return Mode{}, &ParseError{msg: fmt.Sprintf("invalid output mode (cannot combine --json and --output-format=%s)", f)}
+	}
+	if m.Plain && f != FormatTSV {
+		return Mode{}, &ParseError{msg: fmt.Sprintf("invalid output mode (cannot combine --plain and --output-format=%s)", f)}
+	}
+
+	return Mode{JSON: f.Structured(), Plain: f == FormatTSV, Format: f}, nil
+}
+
+// ResolvedFormat returns the effective format, deriving it from the legacy
+// JSON/Plain booleans when no explicit format was set.
+func (m Mode) ResolvedFormat() Format {
+	switch {
+	case m.Format != "":
+		return m.Format
+	case m.JSON:
+		return FormatJSON
+	case m.Plain:
+		return FormatTSV
+	default:
+		return FormatTable
+	}
+}
+
 func FromEnv() Mode {
 	return Mode{
 		JSON:  envBool("GOG_JSON"),
@@ -52,6 +97,26 @@ func FromContext(ctx context.Context) Mode {
 func IsJSON(ctx context.Context) bool  { return FromContext(ctx).JSON }
 func IsPlain(ctx context.Context) bool { return FromContext(ctx).Plain }
 
+// Write renders a command payload in the structured format selected for ctx,
+// after applying any --select/--template transform. Text modes (table/tsv)
+// never reach Write in practice; they fall back to JSON.
+func Write(ctx context.Context, w io.Writer, v any) error {
+	enc, ok := lookupEncoder(FromContext(ctx).ResolvedFormat())
+	if !ok {
+		enc = WriteJSON
+	}
+
+	t := TransformFromContext(ctx)
+	if t.empty() {
+		return enc(w, v)
+	}
+	tree, err := normalize(v)
+	if err != nil {
+		return err
+	}
+	return applyTransform(w, t, tree, enc)
+}
+
 func WriteJSON(w io.Writer, v any) error {
 	enc := json.NewEncoder(w)
 	enc.SetEscapeHTML(false)
@@ -0,0 +1,202 @@
+package outfmt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// object is a JSON object that remembers key order, so CSV headers and YAML
+// keys follow the order the API types declare their fields in.
+type object struct {
+	keys   []string
+	values map[string]any
+}
+
+func (o *object) set(k string, v any) {
+	if _, ok := o.values[k]; !ok {
+		o.keys = append(o.keys, k)
+	}
+	o.values[k] = v
+}
+
+func (o *object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		kb, err := marshalNoEscape(k)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(kb)
+		buf.WriteByte(':')
+		vb, err := marshalNoEscape(o.values[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(vb)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func marshalNoEscape(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// normalize round-trips v through JSON so every encoder sees the same shape
+// WriteJSON would print: *object, []any, string, json.Number, bool or nil.
+func normalize(v any) (any, error) {
+	data, err := marshalNoEscape(v)
+	if err != nil {
+		return nil, fmt.Errorf("encode payload: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	out, err := decodeValue(dec)
+	if err != nil {
+		return nil, fmt.Errorf("decode payload: %w", err)
+	}
+	return out, nil
+}
+
+func decodeValue(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			obj := &object{values: map[string]any{}}
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key, ok := keyTok.(string)
+				if !ok {
+					return nil, errors.New("unexpected object key")
+				}
+				val, err := decodeValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				obj.set(key, val)
+			}
+			if _, err := dec.Token(); err != nil && !errors.Is(err, io.EOF) {
+				return nil, err
+			}
+			return obj, nil
+		case '[':
+			arr := []any{}
+			for dec.More() {
+				val, err := decodeValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				arr = append(arr, val)
+			}
+			if _, err := dec.Token(); err != nil && !errors.Is(err, io.EOF) {
+				return nil, err
+			}
+			return arr, nil
+		}
+		return nil, fmt.Errorf("unexpected delimiter %q", t)
+	default:
+		return t, nil
+	}
+}
+
+// records picks the row set out of a command payload. List commands return
+// an envelope like {"files": [...], "nextPageToken": "..."}; when exactly one
+// field is an array, its elements are the records. An envelope whose only
+// non-scalar field is null (an empty Go slice) has no records. Anything else
+// is treated as a single record.
+func records(tree any) []any {
+	switch t := tree.(type) {
+	case []any:
+		return t
+	case *object:
+		var list []any
+		arrays, nulls, objects := 0, 0, 0
+		for _, k := range t.keys {
+			switch v := t.values[k].(type) {
+			case []any:
+				list = v
+				arrays++
+			case *object:
+				objects++
+			case nil:
+				nulls++
+			}
+		}
+		if arrays == 1 {
+			return list
+		}
+		if arrays == 0 && objects == 0 && nulls == 1 {
+			return nil
+		}
+		return []any{t}
+	case nil:
+		return nil
+	default:
+		return []any{t}
+	}
+}
+
+func flatten(dst *object, prefix string, v any) {
+	obj, ok := v.(*object)
+	if !ok {
+		if prefix == "" {
+			prefix = "value"
+		}
+		dst.set(prefix, v)
+		return
+	}
+	for _, k := range obj.keys {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		if child, ok := obj.values[k].(*object); ok {
+			flatten(dst, key, child)
+			continue
+		}
+		dst.set(key, obj.values[k])
+	}
+}
+
+func scalarString(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case json.Number:
+		return t.String()
+	case bool:
+		if t {
+			return "true"
+		}
+		return "false"
+	default:
+		b, err := marshalNoEscape(t)
+		if err != nil {
+			return fmt.Sprint(t)
+		}
+		return string(b)
+	}
+}
@@ -0,0 +1,95 @@
+package outfmt
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// RecordWriter streams records one at a time, for output that is produced
+// while pages are still being fetched. json and ndjson both emit NDJSON, csv
+// writes a header taken from the first record, yaml separates records with
+// "---", and --select/--template apply per record as they do for Write.
+type RecordWriter struct {
+	w      io.Writer
+	format Format
+	t      Transform
+	csv    *csv.Writer
+	header []string
+}
+
+func NewRecordWriter(ctx context.Context, w io.Writer) *RecordWriter {
+	return &RecordWriter{
+		w:      w,
+		format: FromContext(ctx).ResolvedFormat(),
+		t:      TransformFromContext(ctx),
+	}
+}
+
+func (rw *RecordWriter) Write(v any) error {
+	rec, err := normalize(v)
+	if err != nil {
+		return err
+	}
+	if len(rw.t.Fields) > 0 {
+		rec = project(rec, rw.t.Fields)
+	}
+
+	if rw.t.Template != nil {
+		var buf bytes.Buffer
+		if err := rw.t.Template.Execute(&buf, plain(rec)); err != nil {
+			return fmt.Errorf("render template: %w", err)
+		}
+		if buf.Len() == 0 || buf.Bytes()[buf.Len()-1] != '\n' {
+			buf.WriteByte('\n')
+		}
+		_, err := rw.w.Write(buf.Bytes())
+		return err
+	}
+
+	switch rw.format {
+	case FormatCSV:
+		return rw.writeCSV(rec)
+	case FormatYAML:
+		bw := bufio.NewWriter(rw.w)
+		_, _ = bw.WriteString("---\n")
+		writeYAMLValue(bw, rec, 0)
+		if err := bw.Flush(); err != nil {
+			return fmt.Errorf("encode yaml: %w", err)
+		}
+		return nil
+	default:
+		b, err := marshalNoEscape(rec)
+		if err != nil {
+			return fmt.Errorf("encode ndjson: %w", err)
+		}
+		_, err = rw.w.Write(append(b, '\n'))
+		return err
+	}
+}
+
+// writeCSV fixes the columns from the first record; fields that only appear
+// in later records are dropped since the header has already been written.
+func (rw *RecordWriter) writeCSV(rec any) error {
+	row := &object{values: map[string]any{}}
+	flatten(row, "", rec)
+	if rw.csv == nil {
+		rw.csv = csv.NewWriter(rw.w)
+		rw.header = row.keys
+		if err := rw.csv.Write(rw.header); err != nil {
+			return fmt.Errorf("encode csv: %w", err)
+		}
+	}
+	line := make([]string, len(rw.header))
+	for i, k := range rw.header {
+		line[i] = scalarString(row.values[k])
+	}
+	if err := rw.csv.Write(line); err != nil {
+		return fmt.Errorf("encode csv: %w", err)
+	}
+	rw.csv.Flush()
+	return rw.csv.Error()
+}
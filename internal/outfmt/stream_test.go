@@ -0,0 +1,45 @@
+package outfmt
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestRecordWriter_CSVHeaderFromFirstRecord(t *testing.T) {
+	ctx := WithMode(context.Background(), Mode{JSON: true, Format: FormatCSV})
+	var buf bytes.Buffer
+	rw := NewRecordWriter(ctx, &buf)
+	for _, rec := range []fileRow{{ID: "1", Name: "a"}, {ID: "2", Name: "b", Labels: []string{"X"}}} {
+		if err := rw.Write(rec); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+	}
+	if buf.String() != "id,name,owner.email\n1,a,\n2,b,\n" {
+		t.Fatalf("unexpected csv: %q", buf.String())
+	}
+}
+
+func TestRecordWriter_NDJSONWithSelect(t *testing.T) {
+	fields, _ := ParseSelect("name")
+	ctx := WithTransform(WithMode(context.Background(), Mode{JSON: true}), Transform{Fields: fields})
+	var buf bytes.Buffer
+	rw := NewRecordWriter(ctx, &buf)
+	if err := rw.Write(fileRow{ID: "1", Name: "a"}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if buf.String() != "{\"name\":\"a\"}\n" {
+		t.Fatalf("unexpected ndjson: %q", buf.String())
+	}
+}
+
+func TestRecordWriter_YAMLDocuments(t *testing.T) {
+	ctx := WithMode(context.Background(), Mode{JSON: true, Format: FormatYAML})
+	var buf bytes.Buffer
+	rw := NewRecordWriter(ctx, &buf)
+	_ = rw.Write(map[string]string{"id": "a"})
+	_ = rw.Write(map[string]string{"id": "b"})
+	if buf.String() != "---\nid: a\n---\nid: b\n" {
+		t.Fatalf("unexpected yaml: %q", buf.String())
+	}
+}
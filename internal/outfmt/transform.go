@@ -0,0 +1,289 @@
+package outfmt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Transform post-processes command payloads before they are rendered:
+// Fields projects each record onto the selected (dotted) paths, Template
+// renders each record through a Go text/template instead of an encoder.
+type Transform struct {
+	Fields   [][]string
+	Template *template.Template
+}
+
+func (t Transform) empty() bool {
+	return len(t.Fields) == 0 && t.Template == nil
+}
+
+type transformKey struct{}
+
+func WithTransform(ctx context.Context, t Transform) context.Context {
+	return context.WithValue(ctx, transformKey{}, t)
+}
+
+func TransformFromContext(ctx context.Context) Transform {
+	if v := ctx.Value(transformKey{}); v != nil {
+		if t, ok := v.(Transform); ok {
+			return t
+		}
+	}
+	return Transform{}
+}
+
+// ParseSelect parses a --select value like "id,payload.headers" into paths.
+func ParseSelect(s string) ([][]string, error) {
+	var out [][]string
+	for _, raw := range strings.Split(s, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		parts := strings.Split(raw, ".")
+		for _, p := range parts {
+			if strings.TrimSpace(p) == "" {
+				return nil, &ParseError{msg: fmt.Sprintf("invalid --select path %q", raw)}
+			}
+		}
+		out = append(out, parts)
+	}
+	return out, nil
+}
+
+// ParseTemplate parses a --template value. The escapes \t and \n are
+// expanded so shell-quoted templates can produce TSV lines.
+func ParseTemplate(s string) (*template.Template, error) {
+	text := strings.NewReplacer(`\t`, "\t", `\n`, "\n").Replace(s)
+	tmpl, err := template.New("output").Option("missingkey=zero").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, &ParseError{msg: fmt.Sprintf("invalid --template: %v", err)}
+	}
+	return tmpl, nil
+}
+
+var templateFuncs = template.FuncMap{
+	"date":     templateDate,
+	"join":     templateJoin,
+	"truncate": templateTruncate,
+	"json":     templateJSON,
+	"upper":    strings.ToUpper,
+	"lower":    strings.ToLower,
+}
+
+// templateDate formats RFC 3339 strings, YYYY-MM-DD dates and epoch
+// milliseconds (Gmail internalDate) with a Go time layout (default RFC 3339).
+func templateDate(v any, layout ...string) string {
+	s := strings.TrimSpace(fmt.Sprint(v))
+	if v == nil || s == "" {
+		return ""
+	}
+	out := time.RFC3339
+	if len(layout) > 0 && layout[0] != "" {
+		out = layout[0]
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t.Format(out)
+	}
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t.Format(out)
+	}
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.UnixMilli(ms).UTC().Format(out)
+	}
+	return s
+}
+
+func templateJoin(v any, sep string) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case []any:
+		parts := make([]string, 0, len(t))
+		for _, item := range t {
+			parts = append(parts, templateString(item))
+		}
+		return strings.Join(parts, sep)
+	case []string:
+		return strings.Join(t, sep)
+	default:
+		return templateString(t)
+	}
+}
+
+func templateTruncate(v any, n int) string {
+	s := templateString(v)
+	r := []rune(s)
+	if n <= 0 || len(r) <= n {
+		return s
+	}
+	if n <= 1 {
+		return string(r[:n])
+	}
+	return string(r[:n-1]) + "…"
+}
+
+func templateJSON(v any) (string, error) {
+	b, err := marshalNoEscape(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func templateString(v any) string {
+	switch t := v.(type) {
+	case map[string]any, []any:
+		b, err := marshalNoEscape(t)
+		if err != nil {
+			return fmt.Sprint(t)
+		}
+		return string(b)
+	default:
+		return scalarString(t)
+	}
+}
+
+func applyTransform(w io.Writer, t Transform, tree any, enc Encoder) error {
+	if len(t.Fields) > 0 {
+		tree = mapRecords(tree, func(rec any) any { return project(rec, t.Fields) })
+	}
+	if t.Template == nil {
+		return enc(w, tree)
+	}
+
+	var buf bytes.Buffer
+	for _, rec := range records(tree) {
+		buf.Reset()
+		if err := t.Template.Execute(&buf, plain(unwrapResource(rec))); err != nil {
+			return fmt.Errorf("render template: %w", err)
+		}
+		if buf.Len() == 0 || buf.Bytes()[buf.Len()-1] != '\n' {
+			buf.WriteByte('\n')
+		}
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mapRecords rewrites the records of a payload (see records) in place,
+// keeping list envelopes and single-resource envelopes intact.
+func mapRecords(tree any, fn func(any) any) any {
+	switch t := tree.(type) {
+	case []any:
+		out := make([]any, len(t))
+		for i, rec := range t {
+			out[i] = fn(rec)
+		}
+		return out
+	case *object:
+		recs := records(t)
+		if len(recs) != 1 || recs[0] != any(t) {
+			for _, k := range t.keys {
+				if arr, ok := t.values[k].([]any); ok {
+					t.values[k] = mapRecords(arr, fn)
+				}
+			}
+			return t
+		}
+		if k, inner, ok := resourceEnvelope(t); ok {
+			t.values[k] = fn(inner)
+			return t
+		}
+		return fn(t)
+	default:
+		return tree
+	}
+}
+
+// resourceEnvelope reports whether obj is a single-resource payload such as
+// {"file": {...}}, returning the key and wrapped object.
+func resourceEnvelope(obj *object) (string, *object, bool) {
+	if len(obj.keys) != 1 {
+		return "", nil, false
+	}
+	inner, ok := obj.values[obj.keys[0]].(*object)
+	return obj.keys[0], inner, ok
+}
+
+func unwrapResource(rec any) any {
+	if obj, ok := rec.(*object); ok {
+		if _, inner, ok := resourceEnvelope(obj); ok {
+			return inner
+		}
+	}
+	return rec
+}
+
+func project(rec any, fields [][]string) any {
+	src, ok := rec.(*object)
+	if !ok {
+		return rec
+	}
+	dst := &object{values: map[string]any{}}
+	for _, path := range fields {
+		setPath(dst, path, lookupPath(src, path))
+	}
+	return dst
+}
+
+func lookupPath(v any, path []string) any {
+	for _, p := range path {
+		obj, ok := v.(*object)
+		if !ok {
+			return nil
+		}
+		v = obj.values[p]
+	}
+	return v
+}
+
+func setPath(dst *object, path []string, v any) {
+	for _, p := range path[:len(path)-1] {
+		child, ok := dst.values[p].(*object)
+		if !ok {
+			child = &object{values: map[string]any{}}
+			dst.set(p, child)
+		}
+		dst = child
+	}
+	dst.set(path[len(path)-1], v)
+}
+
+// plain converts ordered values into maps and slices so templates can use
+// {{.field}} and {{index .labels 0}}.
+func plain(v any) any {
+	switch t := v.(type) {
+	case *object:
+		m := make(map[string]any, len(t.keys))
+		for _, k := range t.keys {
+			m[k] = plain(t.values[k])
+		}
+		return m
+	case []any:
+		out := make([]any, len(t))
+		for i, item := range t {
+			out[i] = plain(item)
+		}
+		return out
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i
+		}
+		if f, err := t.Float64(); err == nil {
+			return f
+		}
+		return t.String()
+	default:
+		return t
+	}
+}
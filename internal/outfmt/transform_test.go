@@ -0,0 +1,87 @@
+package outfmt
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestParseSelect(t *testing.T) {
+	got, err := ParseSelect(" id, owner.email ,,")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(got) != 2 || got[0][0] != "id" || strings.Join(got[1], ".") != "owner.email" {
+		t.Fatalf("unexpected paths: %#v", got)
+	}
+	if _, err := ParseSelect("a..b"); err == nil {
+		t.Fatalf("expected error for empty path segment")
+	}
+}
+
+func TestWrite_SelectListEnvelope(t *testing.T) {
+	fields, _ := ParseSelect("id,owner.email,missing")
+	ctx := WithTransform(WithMode(context.Background(), Mode{JSON: true, Format: FormatNDJSON}), Transform{Fields: fields})
+
+	var buf bytes.Buffer
+	if err := Write(ctx, &buf, samplePayload()); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	want := `{"id":"1","owner":{"email":"a@b.com"},"missing":null}` + "\n" +
+		`{"id":"2","owner":{"email":""},"missing":null}` + "\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected output:\n%s", buf.String())
+	}
+}
+
+func TestWrite_SelectResourceEnvelope(t *testing.T) {
+	fields, _ := ParseSelect("name")
+	ctx := WithTransform(WithMode(context.Background(), Mode{JSON: true}), Transform{Fields: fields})
+
+	var buf bytes.Buffer
+	if err := Write(ctx, &buf, map[string]any{"file": fileRow{ID: "1", Name: "doc"}}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if buf.String() != "{\n  \"file\": {\n    \"name\": \"doc\"\n  }\n}\n" {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+}
+
+func TestWrite_Template(t *testing.T) {
+	tmpl, err := ParseTemplate(`{{.id}}\t{{upper .name}}\t{{join .labels ","}}`)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	ctx := WithTransform(WithMode(context.Background(), Mode{JSON: true}), Transform{Template: tmpl})
+
+	var buf bytes.Buffer
+	if err := Write(ctx, &buf, samplePayload()); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if buf.String() != "1\tA, \"QUOTED\"\t\n2\tB\tX,Y\n" {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+
+	if _, err := ParseTemplate("{{.id"); err == nil {
+		t.Fatalf("expected parse error")
+	}
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	if got := templateDate("2025-01-02T03:04:05Z", "2006-01-02"); got != "2025-01-02" {
+		t.Fatalf("date rfc3339: %q", got)
+	}
+	if got := templateDate("1735787045000", "2006-01-02 15:04"); got != "2025-01-02 03:04" {
+		t.Fatalf("date epoch ms: %q", got)
+	}
+	if got := templateDate("not a date"); got != "not a date" {
+		t.Fatalf("date passthrough: %q", got)
+	}
+	if got := templateTruncate("hello world", 6); got != "hello…" {
+		t.Fatalf("truncate: %q", got)
+	}
+	if got := templateTruncate("hi", 6); got != "hi" {
+		t.Fatalf("truncate short: %q", got)
+	}
+}
@@ -0,0 +1,171 @@
+package outfmt
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteYAML writes the payload as a YAML 1.2 document. Strings that YAML
+// would otherwise reinterpret are emitted as JSON-style double-quoted
+// scalars, which YAML accepts verbatim.
+func WriteYAML(w io.Writer, v any) error {
+	tree, err := normalize(v)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	writeYAMLValue(bw, tree, 0)
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("encode yaml: %w", err)
+	}
+	return nil
+}
+
+func writeYAMLValue(w *bufio.Writer, v any, indent int) {
+	switch t := v.(type) {
+	case *object:
+		if len(t.keys) == 0 {
+			writeIndent(w, indent)
+			_, _ = w.WriteString("{}\n")
+			return
+		}
+		for _, k := range t.keys {
+			writeIndent(w, indent)
+			writeYAMLEntry(w, yamlString(k), t.values[k], indent)
+		}
+	case []any:
+		if len(t) == 0 {
+			writeIndent(w, indent)
+			_, _ = w.WriteString("[]\n")
+			return
+		}
+		for _, item := range t {
+			writeIndent(w, indent)
+			writeYAMLItem(w, item, indent)
+		}
+	default:
+		writeIndent(w, indent)
+		_, _ = w.WriteString(yamlScalar(t))
+		_ = w.WriteByte('\n')
+	}
+}
+
+// writeYAMLEntry writes "key: value" for a mapping entry whose indentation
+// has already been written.
+func writeYAMLEntry(w *bufio.Writer, key string, v any, indent int) {
+	_, _ = w.WriteString(key)
+	_ = w.WriteByte(':')
+	switch t := v.(type) {
+	case *object:
+		if len(t.keys) == 0 {
+			_, _ = w.WriteString(" {}\n")
+			return
+		}
+		_ = w.WriteByte('\n')
+		writeYAMLValue(w, t, indent+2)
+	case []any:
+		if len(t) == 0 {
+			_, _ = w.WriteString(" []\n")
+			return
+		}
+		_ = w.WriteByte('\n')
+		writeYAMLValue(w, t, indent)
+	default:
+		_ = w.WriteByte(' ')
+		_, _ = w.WriteString(yamlScalar(t))
+		_ = w.WriteByte('\n')
+	}
+}
+
+// writeYAMLItem writes a "- item" sequence entry whose indentation has
+// already been written. Mapping items keep their first key on the dash line.
+func writeYAMLItem(w *bufio.Writer, v any, indent int) {
+	_, _ = w.WriteString("- ")
+	switch t := v.(type) {
+	case *object:
+		if len(t.keys) == 0 {
+			_, _ = w.WriteString("{}\n")
+			return
+		}
+		for i, k := range t.keys {
+			if i > 0 {
+				writeIndent(w, indent+2)
+			}
+			writeYAMLEntry(w, yamlString(k), t.values[k], indent+2)
+		}
+	case []any:
+		if len(t) == 0 {
+			_, _ = w.WriteString("[]\n")
+			return
+		}
+		for i, item := range t {
+			if i > 0 {
+				writeIndent(w, indent+2)
+			}
+			writeYAMLItem(w, item, indent+2)
+		}
+	default:
+		_, _ = w.WriteString(yamlScalar(t))
+		_ = w.WriteByte('\n')
+	}
+}
+
+func writeIndent(w *bufio.Writer, n int) {
+	_, _ = w.WriteString(strings.Repeat(" ", n))
+}
+
+func yamlScalar(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return yamlString(t)
+	case json.Number:
+		return t.String()
+	case bool:
+		return strconv.FormatBool(t)
+	default:
+		return yamlString(fmt.Sprint(t))
+	}
+}
+
+func yamlString(s string) string {
+	if yamlNeedsQuote(s) {
+		b, err := marshalNoEscape(s)
+		if err != nil {
+			return strconv.Quote(s)
+		}
+		return string(b)
+	}
+	return s
+}
+
+func yamlNeedsQuote(s string) bool {
+	if s == "" || strings.TrimSpace(s) != s {
+		return true
+	}
+	switch strings.ToLower(s) {
+	case "null", "~", "true", "false", "yes", "no", "on", "off", "y", "n", ".nan", ".inf", "-.inf":
+		return true
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return true
+	}
+	if strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") {
+		return true
+	}
+	if strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return true
+	}
+	for _, r := range s {
+		if r < 0x20 || r == 0x7f {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+package secrets
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+
+	"github.com/99designs/keyring"
+)
+
+const (
+	MigrateCopied    = "copied"
+	MigrateUnchanged = "unchanged"
+	MigrateConflict  = "conflict"
+	MigrateFailed    = "failed"
+)
+
+var (
+	errMigrateVerify = errors.New("verification failed: destination value differs after write")
+	openBackendFunc  = openKeyringFor
+)
+
+type MigrateOptions struct {
+	// Overwrite replaces destination keys that hold a different value.
+	Overwrite bool
+	// DeleteSource removes each key from the source once the destination
+	// holds a verified copy.
+	DeleteSource bool
+}
+
+// MigrateResult is the outcome for one keyring key. Values are never
+// included.
+type MigrateResult struct {
+	Key     string `json:"key"`
+	Status  string `json:"status"`
+	Deleted bool   `json:"deleted,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// OpenBackend opens the keyring of backend (auto, keychain or file),
+// regardless of GOG_KEYRING_BACKEND and config.json.
+func OpenBackend(backend string) (*KeyringStore, error) {
+	ring, err := openBackendFunc(KeyringBackendInfo{Value: normalizeKeyringBackend(backend), Source: keyringBackendSourceFlag})
+	if err != nil {
+		return nil, err
+	}
+
+	return &KeyringStore{ring: ring, backend: EffectiveKeyringBackend(backend)}, nil
+}
+
+// EffectiveKeyringBackend resolves "auto" to the backend it opens on this
+// machine where that is known up front: file on Linux without a D-Bus
+// session, keychain on macOS. Other values are returned normalized.
+func EffectiveKeyringBackend(backend string) string {
+	return effectiveKeyringBackend(runtime.GOOS, normalizeKeyringBackend(backend), os.Getenv("DBUS_SESSION_BUS_ADDRESS"))
+}
+
+func effectiveKeyringBackend(goos string, backend string, dbusAddr string) string {
+	if backend != keyringBackendAuto {
+		return backend
+	}
+
+	switch {
+	case shouldForceFileBackend(goos, KeyringBackendInfo{Value: backend}, dbusAddr):
+		return "file"
+	case goos == "darwin":
+		return "keychain"
+	default:
+		return backend
+	}
+}
+
+func sameKeyring(a, b *KeyringStore) bool {
+	return a.ring == b.ring || (a.backend != "" && a.backend == b.backend)
+}
+
+// distinctKeyrings writes a probe key to dst and reports whether src does
+// not see it. "auto" can fall back to another backend at runtime, so two
+// stores with different backend names may still share one keyring.
+func distinctKeyrings(src, dst keyring.Keyring) bool {
+	nonce := make([]byte, 8)
+	if _, err := rand.Read(nonce); err != nil {
+		return false
+	}
+	key := fmt.Sprintf("migrate_probe:%x", nonce)
+	if err := dst.Set(keyring.Item{Key: key, Data: nonce}); err != nil {
+		return false
+	}
+	defer func() { _ = dst.Remove(key) }()
+
+	_, err := src.Get(key)
+	return errors.Is(err, keyring.ErrKeyNotFound)
+}
+
+// Migrate copies every key (tokens, default-account pointers and secrets)
+// from src to dst and reads each one back to verify it. Per-key problems
+// are reported in the results; the error is only set when src cannot be
+// listed. When src and dst turn out to be the same keyring, keys are
+// reported unchanged and never deleted; unchanged keys are only deleted
+// once a probe key shows the two keyrings differ.
+func Migrate(src, dst *KeyringStore, opts MigrateOptions) ([]MigrateResult, error) {
+	keys, err := src.Keys()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(keys)
+
+	if sameKeyring(src, dst) {
+		opts.DeleteSource = false
+	}
+	distinct := opts.DeleteSource && distinctKeyrings(src.ring, dst.ring)
+
+	out := make([]MigrateResult, 0, len(keys))
+	for _, key := range keys {
+		out = append(out, migrateKey(src.ring, dst.ring, key, opts, distinct))
+	}
+
+	return out, nil
+}
+
+func migrateKey(src, dst keyring.Keyring, key string, opts MigrateOptions, distinct bool) MigrateResult {
+	res := MigrateResult{Key: key}
+
+	item, err := src.Get(key)
+	if err != nil {
+		res.Status, res.Error = MigrateFailed, fmt.Sprintf("read source: %v", err)
+		return res
+	}
+
+	existing, err := dst.Get(key)
+
+	switch {
+	case err == nil && bytes.Equal(existing.Data, item.Data):
+		res.Status = MigrateUnchanged
+	case err == nil && !opts.Overwrite:
+		res.Status, res.Error = MigrateConflict, "destination holds a different value (use --overwrite)"
+		return res
+	case err != nil && !errors.Is(err, keyring.ErrKeyNotFound):
+		res.Status, res.Error = MigrateFailed, fmt.Sprintf("read destination: %v", err)
+		return res
+	default:
+		if err := dst.Set(keyring.Item{Key: key, Data: item.Data, Label: item.Label, Description: item.Description}); err != nil {
+			res.Status, res.Error = MigrateFailed, wrapKeychainError(fmt.Errorf("write destination: %w", err)).Error()
+			return res
+		}
+
+		check, err := dst.Get(key)
+		if err != nil || !bytes.Equal(check.Data, item.Data) {
+			res.Status, res.Error = MigrateFailed, errMigrateVerify.Error()
+			return res
+		}
+
+		res.Status = MigrateCopied
+	}
+
+	// A copy proves dst lacked the key; an unchanged key may be the very
+	// item we are about to delete.
+	if opts.DeleteSource && (res.Status == MigrateCopied || distinct) {
+		if err := src.Remove(key); err != nil && !errors.Is(err, keyring.ErrKeyNotFound) {
+			res.Error = fmt.Sprintf("delete source: %v", err)
+			return res
+		}
+
+		res.Deleted = true
+	}
+
+	return res
+}
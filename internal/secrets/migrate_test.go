@@ -0,0 +1,144 @@
+package secrets
+
+import (
+	"testing"
+
+	"github.com/99designs/keyring"
+)
+
+func TestMigrate(t *testing.T) {
+	src := &KeyringStore{ring: keyring.NewArrayKeyring([]keyring.Item{
+		{Key: "default_account", Data: []byte("a@b.com")},
+		{Key: "token:default:a@b.com", Data: []byte(`{"refresh_token":"r1"}`)},
+		{Key: "tracking_key", Data: []byte("k1")},
+		{Key: "token:work:c@d.com", Data: []byte(`{"refresh_token":"r2"}`)},
+	})}
+	dst := &KeyringStore{ring: keyring.NewArrayKeyring([]keyring.Item{
+		{Key: "tracking_key", Data: []byte("k1")},
+		{Key: "token:work:c@d.com", Data: []byte(`{"refresh_token":"other"}`)},
+	})}
+
+	results, err := Migrate(src, dst, MigrateOptions{DeleteSource: true})
+	if err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	want := map[string]string{
+		"default_account":       MigrateCopied,
+		"token:default:a@b.com": MigrateCopied,
+		"token:work:c@d.com":    MigrateConflict,
+		"tracking_key":          MigrateUnchanged,
+	}
+	if len(results) != len(want) {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	for _, r := range results {
+		if r.Status != want[r.Key] {
+			t.Fatalf("%s: want %s, got %+v", r.Key, want[r.Key], r)
+		}
+		if r.Deleted == (r.Status == MigrateConflict) {
+			t.Fatalf("%s: unexpected deleted=%t", r.Key, r.Deleted)
+		}
+	}
+
+	if item, err := dst.ring.Get("token:default:a@b.com"); err != nil || string(item.Data) != `{"refresh_token":"r1"}` {
+		t.Fatalf("token not copied: %v %q", err, item.Data)
+	}
+	if item, err := dst.ring.Get("token:work:c@d.com"); err != nil || string(item.Data) != `{"refresh_token":"other"}` {
+		t.Fatalf("conflicting key overwritten: %v %q", err, item.Data)
+	}
+	keys, _ := src.ring.Keys()
+	if len(keys) != 1 || keys[0] != "token:work:c@d.com" {
+		t.Fatalf("expected only the conflicting key left in source, got %q", keys)
+	}
+
+	results, err = Migrate(src, dst, MigrateOptions{Overwrite: true})
+	if err != nil {
+		t.Fatalf("Migrate overwrite: %v", err)
+	}
+	if len(results) != 1 || results[0].Status != MigrateCopied || results[0].Deleted {
+		t.Fatalf("unexpected overwrite results: %+v", results)
+	}
+	if item, _ := dst.ring.Get("token:work:c@d.com"); string(item.Data) != `{"refresh_token":"r2"}` {
+		t.Fatalf("expected overwritten value, got %q", item.Data)
+	}
+}
+
+func TestOpenBackend(t *testing.T) {
+	orig := openBackendFunc
+	t.Cleanup(func() { openBackendFunc = orig })
+
+	var got KeyringBackendInfo
+	openBackendFunc = func(info KeyringBackendInfo) (keyring.Keyring, error) {
+		got = info
+		return keyring.NewArrayKeyring(nil), nil
+	}
+
+	if _, err := OpenBackend(" File "); err != nil {
+		t.Fatalf("OpenBackend: %v", err)
+	}
+	if got.Value != "file" || got.Source != keyringBackendSourceFlag {
+		t.Fatalf("unexpected backend info %+v", got)
+	}
+}
+
+func TestEffectiveKeyringBackend(t *testing.T) {
+	tests := []struct {
+		goos, backend, dbus, want string
+	}{
+		{"linux", "auto", "", "file"},
+		{"linux", "auto", "unix:path=/run/user/1000/bus", "auto"},
+		{"darwin", "auto", "", "keychain"},
+		{"windows", "auto", "", "auto"},
+		{"linux", "keychain", "", "keychain"},
+	}
+	for _, tt := range tests {
+		if got := effectiveKeyringBackend(tt.goos, tt.backend, tt.dbus); got != tt.want {
+			t.Fatalf("%s/%s/%q: want %s, got %s", tt.goos, tt.backend, tt.dbus, tt.want, got)
+		}
+	}
+}
+
+func TestMigrate_SameKeyringKeepsSource(t *testing.T) {
+	ring := keyring.NewArrayKeyring([]keyring.Item{
+		{Key: "token:default:a@b.com", Data: []byte(`{"refresh_token":"r1"}`)},
+	})
+	src := &KeyringStore{ring: ring, backend: "file"}
+	dst := &KeyringStore{ring: keyring.NewArrayKeyring(nil), backend: "file"}
+	_ = dst.ring.Set(keyring.Item{Key: "token:default:a@b.com", Data: []byte(`{"refresh_token":"r1"}`)})
+
+	results, err := Migrate(src, dst, MigrateOptions{DeleteSource: true})
+	if err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if len(results) != 1 || results[0].Status != MigrateUnchanged || results[0].Deleted {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if _, err := ring.Get("token:default:a@b.com"); err != nil {
+		t.Fatalf("source key deleted: %v", err)
+	}
+}
+
+// aliasRing is a second handle on the same keyring, as when "auto" falls
+// back to the file backend at runtime.
+type aliasRing struct{ keyring.Keyring }
+
+func TestMigrate_SharedKeyringKeepsSource(t *testing.T) {
+	ring := keyring.NewArrayKeyring([]keyring.Item{
+		{Key: "token:default:a@b.com", Data: []byte(`{"refresh_token":"r1"}`)},
+	})
+	src := &KeyringStore{ring: aliasRing{ring}, backend: "auto"}
+	dst := &KeyringStore{ring: ring, backend: "file"}
+
+	results, err := Migrate(src, dst, MigrateOptions{DeleteSource: true})
+	if err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if len(results) != 1 || results[0].Status != MigrateUnchanged || results[0].Deleted {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	keys, _ := ring.Keys()
+	if len(keys) != 1 || keys[0] != "token:default:a@b.com" {
+		t.Fatalf("expected the key kept and no probe left, got %q", keys)
+	}
+}
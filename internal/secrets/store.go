@@ -7,6 +7,7 @@ import (
 	"os"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/99designs/keyring"
@@ -27,6 +28,8 @@ type Store interface {
 
 type KeyringStore struct {
 	ring keyring.Keyring
+	// backend is the effective backend for stores opened via OpenBackend.
+	backend string
 }
 
 type Token struct {
@@ -63,6 +66,7 @@ const (
 	keyringBackendSourceEnv     = "env"
 	keyringBackendSourceConfig  = "config"
 	keyringBackendSourceDefault = "default"
+	keyringBackendSourceFlag    = "flag"
 	keyringBackendAuto          = "auto"
 )
 
@@ -147,6 +151,15 @@ func shouldUseKeyringTimeout(goos string, backendInfo KeyringBackendInfo, dbusAd
 }
 
 func openKeyring() (keyring.Keyring, error) {
+	backendInfo, err := ResolveKeyringBackendInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	return openKeyringFor(backendInfo)
+}
+
+func openKeyringFor(backendInfo KeyringBackendInfo) (keyring.Keyring, error) {
 	// On Linux/WSL/containers, OS keychains (secret-service/kwallet) may be unavailable.
 	// In that case github.com/99designs/keyring falls back to the "file" backend,
 	// which *requires* both a directory and a password prompt function.
@@ -155,11 +168,6 @@ func openKeyring() (keyring.Keyring, error) {
 		return nil, fmt.Errorf("ensure keyring dir: %w", err)
 	}
 
-	backendInfo, err := ResolveKeyringBackendInfo()
-	if err != nil {
-		return nil, err
-	}
-
 	backends, err := allowedBackends(backendInfo)
 	if err != nil {
 		return nil, err
@@ -236,27 +244,91 @@ func openKeyringWithTimeout(cfg keyring.Config, timeout time.Duration) (keyring.
 	}
 }
 
-func OpenDefault() (Store, error) {
+var (
+	sharedRingMu sync.Mutex
+	sharedRing   keyring.Keyring
+	shareRing    bool
+)
+
+// ShareKeyring makes later opens reuse a single keyring handle until the
+// returned func is called. gog run uses it so a script opens the keyring
+// (and asks for the file backend password) once instead of once per line.
+func ShareKeyring() func() {
+	sharedRingMu.Lock()
+	shareRing = true
+	sharedRingMu.Unlock()
+
+	return func() {
+		sharedRingMu.Lock()
+		shareRing = false
+		sharedRing = nil
+		sharedRingMu.Unlock()
+	}
+}
+
+func openRing() (keyring.Keyring, error) {
+	sharedRingMu.Lock()
+	defer sharedRingMu.Unlock()
+
+	if !shareRing {
+		return openKeyringFunc()
+	}
+	if sharedRing != nil {
+		return sharedRing, nil
+	}
+
 	ring, err := openKeyringFunc()
 	if err != nil {
 		return nil, err
 	}
+	sharedRing = ring
+
+	return ring, nil
+}
+
+func OpenDefault() (Store, error) {
+	ring, err := openRing()
+	if err != nil {
+		return nil, err
+	}
 
 	return &KeyringStore{ring: ring}, nil
 }
 
 func SetSecret(key string, value []byte) error {
-	key = strings.TrimSpace(key)
-	if key == "" {
+	if strings.TrimSpace(key) == "" {
 		return errMissingSecretKey
 	}
 
-	ring, err := openKeyringFunc()
+	ring, err := openRing()
 	if err != nil {
 		return err
 	}
 
-	if err := ring.Set(keyring.Item{
+	return (&KeyringStore{ring: ring}).SetSecret(key, value)
+}
+
+func GetSecret(key string) ([]byte, error) {
+	if strings.TrimSpace(key) == "" {
+		return nil, errMissingSecretKey
+	}
+
+	ring, err := openRing()
+	if err != nil {
+		return nil, err
+	}
+
+	return (&KeyringStore{ring: ring}).GetSecret(key)
+}
+
+// SetSecret stores a raw secret in the already opened keyring.
+func (s *KeyringStore) SetSecret(key string, value []byte) error {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return errMissingSecretKey
+	}
+
+	if err := s.ring.Set(keyring.Item{
 		Key:  key,
 		Data: value,
 	}); err != nil {
@@ -266,18 +338,14 @@ func SetSecret(key string, value []byte) error {
 	return nil
 }
 
-func GetSecret(key string) ([]byte, error) {
+// GetSecret reads a raw secret from the already opened keyring.
+func (s *KeyringStore) GetSecret(key string) ([]byte, error) {
 	key = strings.TrimSpace(key)
 	if key == "" {
 		return nil, errMissingSecretKey
 	}
 
-	ring, err := openKeyringFunc()
-	if err != nil {
-		return nil, err
-	}
-
-	item, err := ring.Get(key)
+	item, err := s.ring.Get(key)
 	if err != nil {
 		return nil, fmt.Errorf("read secret: %w", err)
 	}
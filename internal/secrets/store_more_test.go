@@ -162,3 +162,32 @@ func TestKeyringStoreDeleteAndDefaultErrors(t *testing.T) {
 		t.Fatalf("expected missing email, got %v", err)
 	}
 }
+
+func TestShareKeyring(t *testing.T) {
+	origOpen := openKeyringFunc
+
+	t.Cleanup(func() { openKeyringFunc = origOpen })
+
+	opens := 0
+	openKeyringFunc = func() (keyring.Keyring, error) {
+		opens++
+		return keyring.NewArrayKeyring(nil), nil
+	}
+
+	release := ShareKeyring()
+	for i := 0; i < 3; i++ {
+		if _, err := OpenDefault(); err != nil {
+			t.Fatalf("OpenDefault: %v", err)
+		}
+	}
+	if opens != 1 {
+		t.Fatalf("expected one open while shared, got %d", opens)
+	}
+
+	release()
+	_, _ = OpenDefault()
+	_, _ = OpenDefault()
+	if opens != 3 {
+		t.Fatalf("expected an open per call after release, got %d", opens)
+	}
+}
@@ -0,0 +1,221 @@
+// Package undo stores inverses of reversible write operations so `gog undo`
+// can replay them.
+package undo
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+)
+
+// MaxEntries bounds the journal; the oldest entries are dropped first.
+const MaxEntries = 200
+
+const journalVersion = 1
+
+// Entry is one reversible operation. Inverse is kind-specific JSON that the
+// command layer knows how to replay.
+type Entry struct {
+	ID       string          `json:"id"`
+	Time     time.Time       `json:"time"`
+	Account  string          `json:"account"`
+	Kind     string          `json:"kind"`
+	Summary  string          `json:"summary"`
+	Inverse  json.RawMessage `json:"inverse"`
+	UndoneAt *time.Time      `json:"undoneAt,omitempty"`
+}
+
+func (e Entry) Undone() bool {
+	return e.UndoneAt != nil
+}
+
+type journalFile struct {
+	Version int     `json:"version"`
+	Entries []Entry `json:"entries"`
+}
+
+// Path returns the journal location under the config state dir.
+func Path() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", fmt.Errorf("config dir: %w", err)
+	}
+
+	return filepath.Join(dir, "state", "undo.json"), nil
+}
+
+type Journal struct {
+	Path string
+}
+
+// Open returns the journal at the default path.
+func Open() (*Journal, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Journal{Path: path}, nil
+}
+
+func (j *Journal) read() (journalFile, error) {
+	data, err := os.ReadFile(j.Path) //nolint:gosec // config dir state file
+	if err != nil {
+		if os.IsNotExist(err) {
+			return journalFile{Version: journalVersion}, nil
+		}
+
+		return journalFile{}, fmt.Errorf("read undo journal: %w", err)
+	}
+
+	var f journalFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return journalFile{}, fmt.Errorf("parse undo journal: %w", err)
+	}
+
+	return f, nil
+}
+
+func (j *Journal) write(f journalFile) error {
+	if len(f.Entries) > MaxEntries {
+		f.Entries = f.Entries[len(f.Entries)-MaxEntries:]
+	}
+	f.Version = journalVersion
+
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode undo journal: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(j.Path), 0o700); err != nil {
+		return fmt.Errorf("ensure undo journal dir: %w", err)
+	}
+
+	tmp := j.Path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("write undo journal: %w", err)
+	}
+	if err := os.Rename(tmp, j.Path); err != nil {
+		return fmt.Errorf("commit undo journal: %w", err)
+	}
+
+	return nil
+}
+
+// Record appends an entry with a fresh ID and timestamp.
+func (j *Journal) Record(account, kind, summary string, inverse any) (Entry, error) {
+	raw, err := json.Marshal(inverse)
+	if err != nil {
+		return Entry{}, fmt.Errorf("encode inverse: %w", err)
+	}
+
+	f, err := j.read()
+	if err != nil {
+		return Entry{}, err
+	}
+
+	e := Entry{
+		ID:      newID(),
+		Time:    time.Now().UTC(),
+		Account: account,
+		Kind:    kind,
+		Summary: summary,
+		Inverse: raw,
+	}
+	f.Entries = append(f.Entries, e)
+
+	return e, j.write(f)
+}
+
+// List returns entries newest first.
+func (j *Journal) List() ([]Entry, error) {
+	f, err := j.read()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]Entry, 0, len(f.Entries))
+	for i := len(f.Entries) - 1; i >= 0; i-- {
+		out = append(out, f.Entries[i])
+	}
+
+	return out, nil
+}
+
+// Pending returns up to n entries that have not been undone, newest first.
+func (j *Journal) Pending(n int) ([]Entry, error) {
+	all, err := j.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Entry
+	for _, e := range all {
+		if e.Undone() {
+			continue
+		}
+		out = append(out, e)
+		if n > 0 && len(out) == n {
+			break
+		}
+	}
+
+	return out, nil
+}
+
+// Get finds an entry by ID or unique ID prefix.
+func (j *Journal) Get(id string) (Entry, bool, error) {
+	id = strings.TrimSpace(id)
+	all, err := j.List()
+	if err != nil || id == "" {
+		return Entry{}, false, err
+	}
+
+	var found []Entry
+	for _, e := range all {
+		if e.ID == id {
+			return e, true, nil
+		}
+		if strings.HasPrefix(e.ID, id) {
+			found = append(found, e)
+		}
+	}
+	if len(found) == 1 {
+		return found[0], true, nil
+	}
+
+	return Entry{}, false, nil
+}
+
+func (j *Journal) MarkUndone(id string, at time.Time) error {
+	f, err := j.read()
+	if err != nil {
+		return err
+	}
+
+	for i := range f.Entries {
+		if f.Entries[i].ID == id {
+			t := at.UTC()
+			f.Entries[i].UndoneAt = &t
+
+			return j.write(f)
+		}
+	}
+
+	return fmt.Errorf("undo entry not found: %s", id)
+}
+
+func newID() string {
+	var b [4]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+
+	return hex.EncodeToString(b[:])
+}
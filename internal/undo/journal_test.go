@@ -0,0 +1,60 @@
+package undo
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestJournal_RecordPendingUndone(t *testing.T) {
+	j := &Journal{Path: filepath.Join(t.TempDir(), "state", "undo.json")}
+
+	first, err := j.Record("a@b.com", "drive.rename", "rename", map[string]string{"name": "Old"})
+	if err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	second, err := j.Record("a@b.com", "drive.move", "move", map[string]any{"fileId": "f1"})
+	if err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+
+	pending, err := j.Pending(1)
+	if err != nil || len(pending) != 1 || pending[0].ID != second.ID {
+		t.Fatalf("expected newest pending entry, got %+v %v", pending, err)
+	}
+
+	if err := j.MarkUndone(second.ID, time.Now()); err != nil {
+		t.Fatalf("MarkUndone: %v", err)
+	}
+	pending, _ = j.Pending(0)
+	if len(pending) != 1 || pending[0].ID != first.ID {
+		t.Fatalf("expected only first pending, got %+v", pending)
+	}
+
+	got, ok, err := j.Get(first.ID[:4])
+	if err != nil || !ok || got.Kind != "drive.rename" {
+		t.Fatalf("Get by prefix: %+v %v %v", got, ok, err)
+	}
+	var inv map[string]string
+	if err := json.Unmarshal(got.Inverse, &inv); err != nil || inv["name"] != "Old" {
+		t.Fatalf("unexpected inverse %s: %v", got.Inverse, err)
+	}
+
+	if err := j.MarkUndone("missing", time.Now()); err == nil {
+		t.Fatalf("expected error for missing entry")
+	}
+}
+
+func TestJournal_Bounded(t *testing.T) {
+	j := &Journal{Path: filepath.Join(t.TempDir(), "undo.json")}
+	for i := 0; i < MaxEntries+5; i++ {
+		if _, err := j.Record("a@b.com", "k", "s", nil); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+	all, err := j.List()
+	if err != nil || len(all) != MaxEntries {
+		t.Fatalf("expected %d entries, got %d (%v)", MaxEntries, len(all), err)
+	}
+}